@@ -86,7 +86,7 @@ func (cmd CheckCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	endpoint := loadEndpoint(dEnv, apr)
 
-	dc, verr := loadCred(dEnv, apr)
+	dc, verr := loadCred(ctx, dEnv, apr)
 	if verr != nil {
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
@@ -107,10 +107,10 @@ func loadEndpoint(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) string {
 	return fmt.Sprintf("%s:%s", *host, *port)
 }
 
-func loadCred(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (creds.DoltCreds, errhand.VerboseError) {
+func loadCred(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (creds.DoltCreds, errhand.VerboseError) {
 	keyIdOrPubKey, argSupplied := apr.GetValue("creds")
 	if argSupplied {
-		credsdir, err := dEnv.CredsDir()
+		credsdir, err := dEnv.CredsDir(ctx)
 		if err != nil {
 			return creds.EmptyCreds, errhand.BuildDError("error: reading credentials").AddCause(err).Build()
 		}
@@ -126,7 +126,7 @@ func loadCred(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (creds.DoltCred
 		}
 		return dc, nil
 	} else {
-		dc, valid, err := dEnv.UserRPCCreds()
+		dc, valid, err := dEnv.UserRPCCreds(ctx)
 		if !valid {
 			return creds.EmptyCreds, errhand.BuildDError("error: no user credentials found").Build()
 		}
@@ -138,7 +138,7 @@ func loadCred(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (creds.DoltCred
 }
 
 func checkCredAndPrintSuccess(ctx context.Context, dEnv *env.DoltEnv, dc creds.DoltCreds, endpoint string) errhand.VerboseError {
-	endpoint, opts, err := dEnv.GetGRPCDialParams(grpcendpoint.Config{
+	endpoint, opts, err := dEnv.GetGRPCDialParams(ctx, grpcendpoint.Config{
 		Endpoint: endpoint,
 		Creds:    dc,
 	})
@@ -96,7 +96,7 @@ func (cmd ImportCmd) Exec(ctx context.Context, commandStr string, args []string,
 	help, usage := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, importDocs, ap))
 	apr := cli.ParseArgs(ap, args, help)
 
-	credsDir, verr := actions.EnsureCredsDir(dEnv)
+	credsDir, verr := actions.EnsureCredsDir(ctx, dEnv)
 	if verr != nil {
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
@@ -163,7 +163,7 @@ func updateProfileWithCredentials(ctx context.Context, dEnv *env.DoltEnv, c cred
 	host := dEnv.Config.GetStringOrDefault(env.RemotesApiHostKey, env.DefaultRemotesApiHost)
 	port := dEnv.Config.GetStringOrDefault(env.RemotesApiHostPortKey, env.DefaultRemotesApiPort)
 	hostAndPort := fmt.Sprintf("%s:%s", *host, *port)
-	endpoint, opts, err := dEnv.GetGRPCDialParams(grpcendpoint.Config{
+	endpoint, opts, err := dEnv.GetGRPCDialParams(ctx, grpcendpoint.Config{
 		Endpoint: hostAndPort,
 		Creds:    c,
 	})
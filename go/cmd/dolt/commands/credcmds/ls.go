@@ -85,17 +85,17 @@ func (cmd LsCmd) Exec(ctx context.Context, commandStr string, args []string, dEn
 		lsVerbose = true
 	}
 
-	credsDir, verr := actions.EnsureCredsDir(dEnv)
+	credsDir, verr := actions.EnsureCredsDir(ctx, dEnv)
 
 	if verr == nil {
-		dEnv.FS.Iter(credsDir, false, getJWKHandler(dEnv))
+		dEnv.FS.Iter(credsDir, false, getJWKHandler(ctx, dEnv))
 	}
 
 	return commands.HandleVErrAndExitCode(verr, usage)
 }
 
-func getJWKHandler(dEnv *env.DoltEnv) func(string, int64, bool) bool {
-	current, valid, _ := dEnv.UserRPCCreds()
+func getJWKHandler(ctx context.Context, dEnv *env.DoltEnv) func(string, int64, bool) bool {
+	current, valid, _ := dEnv.UserRPCCreds(ctx)
 	first := false
 	return func(path string, size int64, isDir bool) (stop bool) {
 		if strings.HasSuffix(path, creds.JWKFileExtension) {
@@ -77,7 +77,7 @@ func (cmd NewCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 	help, usage := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, newDocs, ap))
 	cli.ParseArgs(ap, args, help)
 
-	_, newCreds, verr := actions.NewCredsFile(dEnv)
+	_, newCreds, verr := actions.NewCredsFile(ctx, dEnv)
 
 	if verr != nil {
 		return commands.HandleVErrAndExitCode(verr, usage)
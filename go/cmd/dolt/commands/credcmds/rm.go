@@ -75,7 +75,7 @@ func (cmd RmCmd) Exec(ctx context.Context, commandStr string, args []string, dEn
 	apr := cli.ParseArgs(ap, args, help)
 	args = apr.Args()
 
-	credsDir, verr := actions.EnsureCredsDir(dEnv)
+	credsDir, verr := actions.EnsureCredsDir(ctx, dEnv)
 
 	if verr == nil {
 		for _, arg := range args {
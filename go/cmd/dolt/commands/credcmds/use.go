@@ -84,7 +84,7 @@ func (cmd UseCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: expected exactly one credential public key or key id as argument").Build(), usage)
 	}
 
-	credsDir, verr := actions.EnsureCredsDir(dEnv)
+	credsDir, verr := actions.EnsureCredsDir(ctx, dEnv)
 
 	if verr == nil {
 		jwkFilePath, err := dEnv.FindCreds(credsDir, args[0])
@@ -110,7 +110,7 @@ var openBrowserFirst loginBehavior = 1
 var checkCredentialsThenOpenBrowser loginBehavior = 2
 
 func loginWithNewCreds(ctx context.Context, dEnv *env.DoltEnv) errhand.VerboseError {
-	path, dc, err := actions.NewCredsFile(dEnv)
+	path, dc, err := actions.NewCredsFile(ctx, dEnv)
 
 	if err != nil {
 		return errhand.BuildDError("error: Unable to create credentials.").AddCause(err).Build()
@@ -125,7 +125,7 @@ func loginWithNewCreds(ctx context.Context, dEnv *env.DoltEnv) errhand.VerboseEr
 }
 
 func loginWithExistingCreds(ctx context.Context, dEnv *env.DoltEnv, idOrPubKey string) errhand.VerboseError {
-	credsDir, err := dEnv.CredsDir()
+	credsDir, err := dEnv.CredsDir(ctx)
 
 	if err != nil {
 		return errhand.BuildDError("error: could not get user home dir").Build()
@@ -147,7 +147,7 @@ func loginWithExistingCreds(ctx context.Context, dEnv *env.DoltEnv, idOrPubKey s
 }
 
 func loginWithCreds(ctx context.Context, dEnv *env.DoltEnv, dc creds.DoltCreds, behavior loginBehavior) errhand.VerboseError {
-	grpcClient, verr := getCredentialsClient(dEnv, dc)
+	grpcClient, verr := getCredentialsClient(ctx, dEnv, dc)
 	if verr != nil {
 		return verr
 	}
@@ -197,10 +197,10 @@ func openBrowserForCredsAdd(dEnv *env.DoltEnv, dc creds.DoltCreds) {
 	open.Start(url)
 }
 
-func getCredentialsClient(dEnv *env.DoltEnv, dc creds.DoltCreds) (remotesapi.CredentialsServiceClient, errhand.VerboseError) {
+func getCredentialsClient(ctx context.Context, dEnv *env.DoltEnv, dc creds.DoltCreds) (remotesapi.CredentialsServiceClient, errhand.VerboseError) {
 	host := dEnv.Config.GetStringOrDefault(env.RemotesApiHostKey, env.DefaultRemotesApiHost)
 	port := dEnv.Config.GetStringOrDefault(env.RemotesApiHostPortKey, env.DefaultRemotesApiPort)
-	endpoint, opts, err := dEnv.GetGRPCDialParams(grpcendpoint.Config{
+	endpoint, opts, err := dEnv.GetGRPCDialParams(ctx, grpcendpoint.Config{
 		Endpoint: fmt.Sprintf("%s:%s", *host, *port),
 		Creds:    dc,
 	})
@@ -107,7 +107,7 @@ func (cmd SendMetricsCmd) Exec(ctx context.Context, commandStr string, args []st
 		if apr.Contains(outputFlag) {
 			flusher = events.NewIOFlusher(dEnv.FS, root, dolt)
 		} else {
-			grpcEmitter := getGRPCEmitter(dEnv)
+			grpcEmitter := getGRPCEmitter(ctx, dEnv)
 
 			flusher = events.NewGrpcEventFlusher(dEnv.FS, root, dolt, grpcEmitter)
 		}
@@ -129,7 +129,7 @@ func (cmd SendMetricsCmd) Exec(ctx context.Context, commandStr string, args []st
 }
 
 // getGRPCEmitter gets the connection to the events grpc service
-func getGRPCEmitter(dEnv *env.DoltEnv) *events.GrpcEmitter {
+func getGRPCEmitter(ctx context.Context, dEnv *env.DoltEnv) *events.GrpcEmitter {
 	host := dEnv.Config.GetStringOrDefault(env.MetricsHost, env.DefaultMetricsHost)
 	portStr := dEnv.Config.GetStringOrDefault(env.MetricsPort, env.DefaultMetricsPort)
 	insecureStr := dEnv.Config.GetStringOrDefault(env.MetricsInsecure, "false")
@@ -148,7 +148,7 @@ func getGRPCEmitter(dEnv *env.DoltEnv) *events.GrpcEmitter {
 	}
 
 	hostAndPort := fmt.Sprintf("%s:%d", *host, port)
-	endpoint, opts, err := dEnv.GetGRPCDialParams(grpcendpoint.Config{
+	endpoint, opts, err := dEnv.GetGRPCDialParams(ctx, grpcendpoint.Config{
 		Endpoint: hostAndPort,
 		Insecure: insecure,
 	})
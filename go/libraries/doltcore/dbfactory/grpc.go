@@ -30,9 +30,10 @@ import (
 	"github.com/dolthub/dolt/go/store/types"
 )
 
-// GRPCDialProvider is an interface for getting a *grpc.ClientConn.
+// GRPCDialProvider is an interface for getting a *grpc.ClientConn.  The context is used to find the credentials of
+// configs which use the environment's credentials.
 type GRPCDialProvider interface {
-	GetGRPCDialParams(grpcendpoint.Config) (string, []grpc.DialOption, error)
+	GetGRPCDialParams(context.Context, grpcendpoint.Config) (string, []grpc.DialOption, error)
 }
 
 // DoldRemoteFactory is a DBFactory implementation for creating databases backed by a remote server that implements the
@@ -64,7 +65,7 @@ func (fact DoltRemoteFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFo
 }
 
 func (fact DoltRemoteFactory) newChunkStore(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]string) (chunks.ChunkStore, error) {
-	endpoint, opts, err := fact.dp.GetGRPCDialParams(grpcendpoint.Config{
+	endpoint, opts, err := fact.dp.GetGRPCDialParams(ctx, grpcendpoint.Config{
 		Endpoint:     urlObj.Host,
 		Insecure:     fact.insecure,
 		WithEnvCreds: true,
@@ -15,13 +15,15 @@
 package actions
 
 import (
+	"context"
+
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 )
 
-func NewCredsFile(dEnv *env.DoltEnv) (string, creds.DoltCreds, errhand.VerboseError) {
-	credsDir, verr := EnsureCredsDir(dEnv)
+func NewCredsFile(ctx context.Context, dEnv *env.DoltEnv) (string, creds.DoltCreds, errhand.VerboseError) {
+	credsDir, verr := EnsureCredsDir(ctx, dEnv)
 	if verr != nil {
 		return "", creds.EmptyCreds, verr
 	}
@@ -41,8 +43,8 @@ func NewCredsFile(dEnv *env.DoltEnv) (string, creds.DoltCreds, errhand.VerboseEr
 	return credsPath, dCreds, verr
 }
 
-func EnsureCredsDir(dEnv *env.DoltEnv) (string, errhand.VerboseError) {
-	credsPath, err := dEnv.CredsDir()
+func EnsureCredsDir(ctx context.Context, dEnv *env.DoltEnv) (string, errhand.VerboseError) {
+	credsPath, err := dEnv.CredsDir(ctx)
 	if err != nil {
 		return "", errhand.BuildDError("fatal: could not determine credentials dir").AddCause(err).Build()
 	}
@@ -15,6 +15,7 @@
 package env
 
 import (
+	"context"
 	"errors"
 	"path/filepath"
 	"strings"
@@ -84,10 +85,10 @@ type DoltCliConfig struct {
 	fs filesys.ReadWriteFS
 }
 
-func loadDoltCliConfig(hdp HomeDirProvider, fs filesys.ReadWriteFS) (*DoltCliConfig, error) {
+func loadDoltCliConfig(ctx context.Context, hdp HomeDirProvider, fs filesys.ReadWriteFS) (*DoltCliConfig, error) {
 	ch := config.NewConfigHierarchy()
 
-	gPath, err := getGlobalCfgPath(hdp)
+	gPath, err := getGlobalCfgPath(ctx, hdp)
 	lPath := getLocalConfigPath()
 
 	gCfg, err := ensureGlobalConfig(gPath, fs)
@@ -0,0 +1,95 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const (
+	// CredsEnvVarPrefix is the prefix of environment variables holding the JWK for a specific key id.  The variable for
+	// a key id is the prefix followed by the key id in upper case.
+	CredsEnvVarPrefix = "DOLT_CREDS_"
+
+	// RemoteTokenEnvVar is the environment variable holding the JWK of a single credential which is used when there
+	// is no environment variable for the specific key id requested.
+	RemoteTokenEnvVar = "DOLT_REMOTE_TOKEN"
+)
+
+// CredsSource describes where a credential was loaded from
+type CredsSource int
+
+const (
+	// CredsNotFound is returned when no credential could be found
+	CredsNotFound CredsSource = iota
+	// CredsFromKeyEnvVar is returned when the credential came from the DOLT_CREDS_<KEYID> environment variable
+	CredsFromKeyEnvVar
+	// CredsFromTokenEnvVar is returned when the credential came from the DOLT_REMOTE_TOKEN environment variable
+	CredsFromTokenEnvVar
+	// CredsFromFile is returned when the credential came from the creds directory
+	CredsFromFile
+)
+
+// CredsEnvVarForKeyID returns the name of the environment variable consulted for the key id given
+func CredsEnvVarForKeyID(kid string) string {
+	return CredsEnvVarPrefix + strings.ToUpper(kid)
+}
+
+// ResolveCreds returns the credential with the key id given regardless of where it is stored.  The environment
+// variable DOLT_CREDS_<KEYID> is consulted first, then DOLT_REMOTE_TOKEN, and finally the creds directory.  When kid is
+// empty only DOLT_REMOTE_TOKEN is consulted, and if it is not set creds.EmptyCreds and CredsNotFound are returned along
+// with a nil error.  When a specific key id cannot be found creds.ErrCredsNotFound is returned.  If ctx carries a
+// HomeDirProvider the creds directory within its home directory is used.
+func (dEnv *DoltEnv) ResolveCreds(ctx context.Context, kid string) (creds.DoltCreds, CredsSource, error) {
+	return resolveCreds(ctx, os.LookupEnv, dEnv.FS, dEnv.hdp, kid)
+}
+
+func resolveCreds(ctx context.Context, lookupEnv func(string) (string, bool), fs filesys.Filesys, hdp HomeDirProvider, kid string) (creds.DoltCreds, CredsSource, error) {
+	if kid != "" {
+		if jwk, ok := lookupEnv(CredsEnvVarForKeyID(kid)); ok && jwk != "" {
+			c, err := creds.JWKCredsDeserialize([]byte(jwk))
+			return c, CredsFromKeyEnvVar, err
+		}
+	}
+
+	if jwk, ok := lookupEnv(RemoteTokenEnvVar); ok && jwk != "" {
+		c, err := creds.JWKCredsDeserialize([]byte(jwk))
+		return c, CredsFromTokenEnvVar, err
+	}
+
+	if kid == "" {
+		return creds.EmptyCreds, CredsNotFound, nil
+	}
+
+	credsDir, err := getCredsDir(ctx, hdp)
+
+	if err != nil {
+		return creds.EmptyCreds, CredsNotFound, err
+	}
+
+	path := filepath.Join(credsDir, kid+creds.JWKFileExtension)
+	if exists, _ := fs.Exists(path); !exists {
+		return creds.EmptyCreds, CredsNotFound, creds.ErrCredsNotFound
+	}
+
+	c, err := creds.JWKCredsReadFromFile(fs, path)
+	return c, CredsFromFile, err
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func genTestCreds(t *testing.T) (creds.DoltCreds, string) {
+	c, err := creds.GenerateCredentials()
+	require.NoError(t, err)
+	jwk, err := creds.JWKCredSerialize(c)
+	require.NoError(t, err)
+	return c, string(jwk)
+}
+
+func TestResolveCreds(t *testing.T) {
+	fileCreds, _ := genTestCreds(t)
+	keyEnvCreds, keyEnvJWK := genTestCreds(t)
+	tokenCreds, tokenJWK := genTestCreds(t)
+
+	fs := filesys.NewInMemFS([]string{testHomeDir}, nil, testHomeDir)
+	credsDir, err := getCredsDir(context.Background(), testHomeDirFunc)
+	require.NoError(t, err)
+	require.NoError(t, fs.MkDirs(credsDir))
+	_, err = creds.JWKCredsWriteToDir(fs, credsDir, fileCreds)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		envVars        map[string]string
+		kid            string
+		expectedCreds  creds.DoltCreds
+		expectedSource CredsSource
+		expectedErr    error
+	}{
+		{
+			name:           "env absent reads file",
+			kid:            fileCreds.KeyIDBase32Str(),
+			expectedCreds:  fileCreds,
+			expectedSource: CredsFromFile,
+		},
+		{
+			name:           "key env var before file",
+			envVars:        map[string]string{CredsEnvVarForKeyID(fileCreds.KeyIDBase32Str()): keyEnvJWK, RemoteTokenEnvVar: tokenJWK},
+			kid:            fileCreds.KeyIDBase32Str(),
+			expectedCreds:  keyEnvCreds,
+			expectedSource: CredsFromKeyEnvVar,
+		},
+		{
+			name:           "token env var before file",
+			envVars:        map[string]string{RemoteTokenEnvVar: tokenJWK},
+			kid:            fileCreds.KeyIDBase32Str(),
+			expectedCreds:  tokenCreds,
+			expectedSource: CredsFromTokenEnvVar,
+		},
+		{
+			name:           "token env var without key id",
+			envVars:        map[string]string{RemoteTokenEnvVar: tokenJWK},
+			expectedCreds:  tokenCreds,
+			expectedSource: CredsFromTokenEnvVar,
+		},
+		{
+			name:           "nothing configured",
+			expectedCreds:  creds.EmptyCreds,
+			expectedSource: CredsNotFound,
+		},
+		{
+			name:           "missing key id",
+			kid:            keyEnvCreds.KeyIDBase32Str(),
+			expectedCreds:  creds.EmptyCreds,
+			expectedSource: CredsNotFound,
+			expectedErr:    creds.ErrCredsNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lookupEnv := func(name string) (string, bool) {
+				val, ok := test.envVars[name]
+				return val, ok
+			}
+
+			c, source, err := resolveCreds(context.Background(), lookupEnv, fs, testHomeDirFunc, test.kid)
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedSource, source)
+			assert.Equal(t, test.expectedCreds.KeyIDBase32Str(), c.KeyIDBase32Str())
+		})
+	}
+}
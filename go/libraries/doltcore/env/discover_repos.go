@@ -0,0 +1,157 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+)
+
+// DefaultDiscoverReposMaxDepth is the number of directories below the root that tools look for repos in by default
+const DefaultDiscoverReposMaxDepth = 8
+
+// DiscoverRepos walks the directory tree under root and returns the path of each dolt repo found, for tools which
+// manage several repos.  A repo is a directory with a .dolt directory holding a noms data directory, so the global
+// .dolt directory in a home directory isn't mistaken for one.  Directories nested inside a repo are not searched, and
+// neither are directories more than maxDepth levels below root.  Symlinks to directories are followed once the rest
+// of the tree has been walked, and each directory is only searched once by its real path, so symlink cycles end rather
+// than loop and a repo reachable both directly and through a symlink is returned once by its direct path.  A symlink to
+// a directory inside a repo isn't followed, so repos nested in another aren't found through one either.  Directories
+// which can't be read because of their permissions are skipped.  Each path returned begins with root.
+func DiscoverRepos(root string, maxDepth int) ([]string, error) {
+	w := &repoWalker{maxDepth: maxDepth, visited: make(map[string]struct{})}
+	err := w.walk(root, 0)
+
+	for err == nil && len(w.symlinks) > 0 {
+		link := w.symlinks[0]
+		w.symlinks = w.symlinks[1:]
+
+		var inRepo bool
+		inRepo, err = isInRepo(link.path)
+
+		if err == nil && !inRepo {
+			err = w.walk(link.path, link.depth)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return w.repos, nil
+}
+
+// repoWalker holds the state of a call to DiscoverRepos
+type repoWalker struct {
+	maxDepth int
+	visited  map[string]struct{}
+	repos    []string
+	symlinks []symlinkedDir
+}
+
+// symlinkedDir is a symlink to a directory whose walk is deferred until the directories it was found in are walked
+type symlinkedDir struct {
+	path  string
+	depth int
+}
+
+func (w *repoWalker) walk(dir string, depth int) error {
+	realPath, err := filepath.EvalSymlinks(dir)
+
+	if err != nil {
+		return err
+	}
+
+	if _, ok := w.visited[realPath]; ok {
+		return nil
+	}
+
+	w.visited[realPath] = struct{}{}
+
+	if isRepoDir(dir) {
+		w.repos = append(w.repos, dir)
+		return nil
+	}
+
+	if depth >= w.maxDepth {
+		return nil
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		if depth > 0 && os.IsPermission(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == dbfactory.DoltDir {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		if entry.Mode()&os.ModeSymlink != 0 {
+			info, err := os.Stat(path)
+
+			// dangling symlinks are skipped
+			if err == nil && info.IsDir() {
+				w.symlinks = append(w.symlinks, symlinkedDir{path, depth + 1})
+			}
+
+			continue
+		}
+
+		if !entry.IsDir() {
+			continue
+		}
+
+		err = w.walk(path, depth+1)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isInRepo returns whether the real path of dir, with its symlinks resolved, is inside a repo
+func isInRepo(dir string) (bool, error) {
+	realPath, err := filepath.EvalSymlinks(dir)
+
+	if err != nil {
+		return false, err
+	}
+
+	for parent := filepath.Dir(realPath); parent != realPath; realPath, parent = parent, filepath.Dir(parent) {
+		if isRepoDir(parent) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func isRepoDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, dbfactory.DoltDataDir))
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+)
+
+func TestDiscoverRepos(t *testing.T) {
+	root, err := ioutil.TempDir("", "discover_repos")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	mkRepo := func(path ...string) string {
+		dir := filepath.Join(append([]string{root}, path...)...)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, dbfactory.DoltDataDir), os.ModePerm))
+		return dir
+	}
+
+	a := mkRepo("a")
+	b := mkRepo("group", "b")
+	c := mkRepo("group", "c")
+	deep := mkRepo("x", "y", "z", "deep")
+
+	// a repo nested in another isn't returned
+	mkRepo("a", "nested")
+
+	// neither is a directory with a .dolt directory but no data, like a home directory
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "home", dbfactory.DoltDir), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "group", "file.txt"), []byte("not a dir"), os.ModePerm))
+
+	repos, err := DiscoverRepos(root, DefaultDiscoverReposMaxDepth)
+	require.NoError(t, err)
+	assert.Equal(t, []string{a, b, c, deep}, repos)
+
+	repos, err = DiscoverRepos(root, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{a, b, c}, repos)
+
+	// a symlink cycle is walked once, and a repo reachable by a symlink is returned once
+	err = os.Symlink(root, filepath.Join(root, "group", "loop"))
+	if err != nil {
+		t.Skip("symlinks are not supported", err)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(root, "x"), filepath.Join(root, "group", "link")))
+	require.NoError(t, os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "dangling")))
+
+	repos, err = DiscoverRepos(root, DefaultDiscoverReposMaxDepth)
+	require.NoError(t, err)
+	assert.Equal(t, []string{a, b, c, deep}, repos)
+
+	_, err = DiscoverRepos(filepath.Join(root, "missing"), DefaultDiscoverReposMaxDepth)
+	assert.Error(t, err)
+}
+
+func TestDiscoverReposThroughSymlink(t *testing.T) {
+	root, err := ioutil.TempDir("", "discover_repos")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	elsewhere, err := ioutil.TempDir("", "discover_repos_elsewhere")
+	require.NoError(t, err)
+	defer os.RemoveAll(elsewhere)
+	require.NoError(t, os.MkdirAll(filepath.Join(elsewhere, "repo", dbfactory.DoltDataDir), os.ModePerm))
+
+	err = os.Symlink(elsewhere, filepath.Join(root, "linked"))
+	if err != nil {
+		t.Skip("symlinks are not supported", err)
+	}
+
+	repos, err := DiscoverRepos(root, DefaultDiscoverReposMaxDepth)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "linked", "repo")}, repos)
+}
+
+func TestDiscoverReposSymlinkIntoRepo(t *testing.T) {
+	root, err := ioutil.TempDir("", "discover_repos")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	repo := filepath.Join(root, "repo")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, dbfactory.DoltDataDir), os.ModePerm))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "sub", "nested", dbfactory.DoltDataDir), os.ModePerm))
+
+	// the nested repo would be found through a symlink to the repo's subdirectory if it were followed
+	err = os.Symlink(filepath.Join(repo, "sub"), filepath.Join(root, "linked"))
+	if err != nil {
+		t.Skip("symlinks are not supported", err)
+	}
+
+	repos, err := DiscoverRepos(root, DefaultDiscoverReposMaxDepth)
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo}, repos)
+}
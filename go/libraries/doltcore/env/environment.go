@@ -79,7 +79,7 @@ type DoltEnv struct {
 
 // Load loads the DoltEnv for the current directory of the cli
 func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr, version string) *DoltEnv {
-	config, cfgErr := loadDoltCliConfig(hdp, fs)
+	config, cfgErr := loadDoltCliConfig(ctx, hdp, fs)
 	repoState, rsErr := LoadRepoState(fs)
 	docs, docsErr := doltdocs.LoadDocs(fs)
 	ddb, dbLoadErr := doltdb.LoadDoltDB(ctx, types.Format_Default, urlStr)
@@ -575,30 +575,32 @@ func (dEnv *DoltEnv) GetTablesWithConflicts(ctx context.Context) ([]string, erro
 	return root.TablesInConflict(ctx)
 }
 
-func (dEnv *DoltEnv) CredsDir() (string, error) {
-	return getCredsDir(dEnv.hdp)
+// CredsDir returns the directory the user's credentials are stored in, within the home directory of the
+// HomeDirProvider carried by ctx if there is one
+func (dEnv *DoltEnv) CredsDir(ctx context.Context) (string, error) {
+	return getCredsDir(ctx, dEnv.hdp)
 }
 
-func (dEnv *DoltEnv) UserRPCCreds() (creds.DoltCreds, bool, error) {
+// UserRPCCreds returns the credentials used for remote calls, resolved as ResolveCreds does for the key id of the
+// user.creds config value, and whether they are valid
+func (dEnv *DoltEnv) UserRPCCreds(ctx context.Context) (creds.DoltCreds, bool, error) {
 	kid, err := dEnv.Config.GetString(UserCreds)
 
-	if err == nil && kid != "" {
-		dir, err := dEnv.CredsDir()
+	if err != nil {
+		kid = ""
+	}
 
-		if err != nil {
-			// not sure why you wouldn't be able to get the creds dir.
-			panic(err)
-		}
+	c, source, err := dEnv.ResolveCreds(ctx, kid)
 
-		c, err := creds.JWKCredsReadFromFile(dEnv.FS, filepath.Join(dir, kid+".jwk"))
-		return c, c.IsPrivKeyValid() && c.IsPubKeyValid(), err
+	if source == CredsNotFound && err == nil {
+		return creds.EmptyCreds, false, nil
 	}
 
-	return creds.EmptyCreds, false, nil
+	return c, c.IsPrivKeyValid() && c.IsPubKeyValid(), err
 }
 
-func (dEnv *DoltEnv) getRPCCreds() (credentials.PerRPCCredentials, error) {
-	dCreds, valid, err := dEnv.UserRPCCreds()
+func (dEnv *DoltEnv) getRPCCreds(ctx context.Context) (credentials.PerRPCCredentials, error) {
+	dCreds, valid, err := dEnv.UserRPCCreds(ctx)
 	if err != nil {
 		return nil, ErrInvalidCredsFile
 	}
@@ -629,7 +631,7 @@ func (dEnv *DoltEnv) getUserAgentString() string {
 	return strings.Join(tokens, " ")
 }
 
-func (dEnv *DoltEnv) GetGRPCDialParams(config grpcendpoint.Config) (string, []grpc.DialOption, error) {
+func (dEnv *DoltEnv) GetGRPCDialParams(ctx context.Context, config grpcendpoint.Config) (string, []grpc.DialOption, error) {
 	endpoint := config.Endpoint
 	if strings.IndexRune(endpoint, ':') == -1 {
 		if config.Insecure {
@@ -653,7 +655,7 @@ func (dEnv *DoltEnv) GetGRPCDialParams(config grpcendpoint.Config) (string, []gr
 	if config.Creds != nil {
 		opts = append(opts, grpc.WithPerRPCCredentials(config.Creds))
 	} else if config.WithEnvCreds {
-		rpcCreds, err := dEnv.getRPCCreds()
+		rpcCreds, err := dEnv.getRPCCreds(ctx)
 		if err != nil {
 			return "", nil, err
 		}
@@ -0,0 +1,160 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// ErrNoDoltRootToMigrate is returned by MigrateDoltRoot when the source directory has no .dolt directory
+var ErrNoDoltRootToMigrate = errors.New("no .dolt directory to migrate")
+
+// MigrateDoltRoot moves the global dolt state, including credentials, the global config and any other files, from the
+// .dolt directory inside from to the .dolt directory inside to.  from and to are the directories that would be given
+// in DOLT_ROOT_PATH.  Files that already exist in the destination with identical contents are merged, while files with
+// different contents are a conflict and cause an error before anything is moved.  If a move fails part way through,
+// the files already moved are moved back.
+func MigrateDoltRoot(from, to string) error {
+	return migrateDoltRoot(filesys.LocalFS, from, to)
+}
+
+func migrateDoltRoot(fs filesys.Filesys, from, to string) error {
+	srcDir, err := fs.Abs(filepath.Join(from, dbfactory.DoltDir))
+
+	if err != nil {
+		return err
+	}
+
+	destDir, err := fs.Abs(filepath.Join(to, dbfactory.DoltDir))
+
+	if err != nil {
+		return err
+	}
+
+	if srcDir == destDir {
+		return nil
+	}
+
+	if exists, isDir := fs.Exists(srcDir); !exists || !isDir {
+		return ErrNoDoltRootToMigrate
+	}
+
+	var relPaths []string
+	var iterErr error
+	err = fs.Iter(srcDir, true, func(path string, size int64, isDir bool) (stop bool) {
+		if isDir {
+			return false
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+
+		if err != nil {
+			iterErr = err
+			return true
+		}
+
+		relPaths = append(relPaths, relPath)
+		return false
+	})
+
+	if err != nil {
+		return err
+	} else if iterErr != nil {
+		return iterErr
+	}
+
+	// check every file for conflicts before modifying anything
+	var toMove []string
+	for _, relPath := range relPaths {
+		destPath := filepath.Join(destDir, relPath)
+		exists, isDir := fs.Exists(destPath)
+
+		if !exists {
+			toMove = append(toMove, relPath)
+			continue
+		} else if isDir {
+			return fmt.Errorf("cannot migrate '%s': destination '%s' is a directory", relPath, destPath)
+		}
+
+		same, err := sameFileContents(fs, filepath.Join(srcDir, relPath), destPath)
+
+		if err != nil {
+			return err
+		} else if !same {
+			return fmt.Errorf("cannot migrate '%s': '%s' already exists with different contents", relPath, destPath)
+		}
+	}
+
+	var moved []string
+	for _, relPath := range toMove {
+		err = moveFileMakingDirs(fs, filepath.Join(srcDir, relPath), filepath.Join(destDir, relPath))
+
+		if err != nil {
+			return rollbackMigration(fs, srcDir, destDir, moved, err)
+		}
+
+		moved = append(moved, relPath)
+	}
+
+	return fs.Delete(srcDir, true)
+}
+
+// moveFileMakingDirs moves the file at src to dest, first creating the directory dest is in if it doesn't exist, which
+// MoveFile does not do on every filesystem
+func moveFileMakingDirs(fs filesys.Filesys, src, dest string) error {
+	err := fs.MkDirs(filepath.Dir(dest))
+
+	if err != nil {
+		return err
+	}
+
+	return fs.MoveFile(src, dest)
+}
+
+func sameFileContents(fs filesys.ReadableFS, path1, path2 string) (bool, error) {
+	data1, err := fs.ReadFile(path1)
+
+	if err != nil {
+		return false, err
+	}
+
+	data2, err := fs.ReadFile(path2)
+
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(data1, data2), nil
+}
+
+// rollbackMigration moves the files already migrated back to the source directory and returns the error which caused
+// the migration to fail along with any error encountered while rolling back.
+func rollbackMigration(fs filesys.Filesys, srcDir, destDir string, moved []string, cause error) error {
+	for i := len(moved) - 1; i >= 0; i-- {
+		err := moveFileMakingDirs(fs, filepath.Join(destDir, moved[i]), filepath.Join(srcDir, moved[i]))
+
+		if err != nil {
+			return fmt.Errorf("migration failed: %v; rollback failed, '%s' remains in '%s': %v", cause, moved[i], destDir, err)
+		}
+	}
+
+	return cause
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const (
+	migrateFromDir = "/user/bheni"
+	migrateToDir   = "/data/dolt_root"
+)
+
+func migrateTestFS(destFiles map[string][]byte) *filesys.InMemFS {
+	srcDoltDir := filepath.Join(migrateFromDir, dbfactory.DoltDir)
+	files := map[string][]byte{
+		filepath.Join(srcDoltDir, globalConfig):            []byte(`{"user.name":"bheni"}`),
+		filepath.Join(srcDoltDir, credsDir, "abcdefg.jwk"): []byte("creds"),
+	}
+
+	for path, data := range destFiles {
+		files[filepath.Join(migrateToDir, dbfactory.DoltDir, path)] = data
+	}
+
+	return filesys.NewInMemFS([]string{migrateFromDir, migrateToDir}, files, migrateFromDir)
+}
+
+func TestMigrateDoltRoot(t *testing.T) {
+	srcDoltDir := filepath.Join(migrateFromDir, dbfactory.DoltDir)
+	destDoltDir := filepath.Join(migrateToDir, dbfactory.DoltDir)
+
+	t.Run("clean move", func(t *testing.T) {
+		fs := migrateTestFS(nil)
+		require.NoError(t, migrateDoltRoot(fs, migrateFromDir, migrateToDir))
+
+		exists, _ := fs.Exists(srcDoltDir)
+		assert.False(t, exists)
+
+		data, err := fs.ReadFile(filepath.Join(destDoltDir, globalConfig))
+		require.NoError(t, err)
+		assert.Equal(t, `{"user.name":"bheni"}`, string(data))
+
+		data, err = fs.ReadFile(filepath.Join(destDoltDir, credsDir, "abcdefg.jwk"))
+		require.NoError(t, err)
+		assert.Equal(t, "creds", string(data))
+	})
+
+	t.Run("identical file merged", func(t *testing.T) {
+		fs := migrateTestFS(map[string][]byte{
+			globalConfig:     []byte(`{"user.name":"bheni"}`),
+			"other_file.txt": []byte("other"),
+		})
+		require.NoError(t, migrateDoltRoot(fs, migrateFromDir, migrateToDir))
+
+		exists, _ := fs.Exists(filepath.Join(destDoltDir, credsDir, "abcdefg.jwk"))
+		assert.True(t, exists)
+		exists, _ = fs.Exists(filepath.Join(destDoltDir, "other_file.txt"))
+		assert.True(t, exists)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		fs := migrateTestFS(map[string][]byte{
+			globalConfig: []byte(`{"user.name":"someone_else"}`),
+		})
+		err := migrateDoltRoot(fs, migrateFromDir, migrateToDir)
+		assert.Error(t, err)
+
+		exists, _ := fs.Exists(filepath.Join(srcDoltDir, credsDir, "abcdefg.jwk"))
+		assert.True(t, exists)
+		exists, _ = fs.Exists(filepath.Join(destDoltDir, credsDir, "abcdefg.jwk"))
+		assert.False(t, exists)
+
+		data, err := fs.ReadFile(filepath.Join(destDoltDir, globalConfig))
+		require.NoError(t, err)
+		assert.Equal(t, `{"user.name":"someone_else"}`, string(data))
+	})
+
+	t.Run("nothing to migrate", func(t *testing.T) {
+		fs := filesys.NewInMemFS([]string{migrateFromDir, migrateToDir}, nil, migrateFromDir)
+		assert.Equal(t, ErrNoDoltRootToMigrate, migrateDoltRoot(fs, migrateFromDir, migrateToDir))
+	})
+}
+
+func TestMigrateDoltRootLocalFS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate_dolt_root")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	from := filepath.Join(dir, "from")
+	to := filepath.Join(dir, "to")
+	srcDoltDir := filepath.Join(from, dbfactory.DoltDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDoltDir, credsDir), os.ModePerm))
+	require.NoError(t, os.MkdirAll(to, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcDoltDir, globalConfig), []byte(`{"user.name":"bheni"}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcDoltDir, credsDir, "abcdefg.jwk"), []byte("creds"), 0644))
+
+	// the destination's .dolt and creds directories don't exist, and must be created by the migration
+	require.NoError(t, MigrateDoltRoot(from, to))
+
+	_, err = os.Stat(srcDoltDir)
+	assert.True(t, os.IsNotExist(err))
+
+	data, err := ioutil.ReadFile(filepath.Join(to, dbfactory.DoltDir, globalConfig))
+	require.NoError(t, err)
+	assert.Equal(t, `{"user.name":"bheni"}`, string(data))
+
+	data, err = ioutil.ReadFile(filepath.Join(to, dbfactory.DoltDir, credsDir, "abcdefg.jwk"))
+	require.NoError(t, err)
+	assert.Equal(t, "creds", string(data))
+}
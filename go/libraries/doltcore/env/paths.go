@@ -15,11 +15,13 @@
 package env
 
 import (
+	"context"
 	"os"
 	"os/user"
 	"path/filepath"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 )
 
 const (
@@ -30,13 +32,34 @@ const (
 	configFile   = "config.json"
 	globalConfig = "config_global.json"
 
-	repoStateFile = "repo_state.json"
+	repoStateFile     = "repo_state.json"
+	repoStateLockFile = "repo_state.lock"
+
+	scratchDir = "tmp"
 )
 
 // HomeDirProvider is a function that returns the users home directory.  This is where global dolt state is stored for
 // the current user
 type HomeDirProvider func() (string, error)
 
+type homeDirProviderKey struct{}
+
+// WithHomeDirProvider returns a copy of ctx carrying hdp.  When locating the creds directory or the global config the
+// provider carried by the context is used in place of the environment's, which lets a sql-server give each connection
+// its own home directory and credentials.
+func WithHomeDirProvider(ctx context.Context, hdp HomeDirProvider) context.Context {
+	return context.WithValue(ctx, homeDirProviderKey{}, hdp)
+}
+
+// HomeDirProviderFromContext returns the HomeDirProvider carried by ctx, or defaultHdp if ctx doesn't carry one
+func HomeDirProviderFromContext(ctx context.Context, defaultHdp HomeDirProvider) HomeDirProvider {
+	if hdp, ok := ctx.Value(homeDirProviderKey{}).(HomeDirProvider); ok && hdp != nil {
+		return hdp
+	}
+
+	return defaultHdp
+}
+
 // GetCurrentUserHomeDir will return the current user's home directory by default.  This directory is where global dolt
 // state will be stored inside of the .dolt directory.  The environment variable DOLT_ROOT_PATH can be used to
 // provide a different directory where the root .dolt directory should be located and global state will be stored there.
@@ -54,8 +77,8 @@ func GetCurrentUserHomeDir() (string, error) {
 	}
 }
 
-func getCredsDir(hdp HomeDirProvider) (string, error) {
-	homeDir, err := hdp()
+func getCredsDir(ctx context.Context, hdp HomeDirProvider) (string, error) {
+	homeDir, err := HomeDirProviderFromContext(ctx, hdp)()
 	if err != nil {
 		return "", err
 	}
@@ -63,8 +86,8 @@ func getCredsDir(hdp HomeDirProvider) (string, error) {
 	return filepath.Join(homeDir, dbfactory.DoltDir, credsDir), nil
 }
 
-func getGlobalCfgPath(hdp HomeDirProvider) (string, error) {
-	homeDir, err := hdp()
+func getGlobalCfgPath(ctx context.Context, hdp HomeDirProvider) (string, error) {
+	homeDir, err := HomeDirProviderFromContext(ctx, hdp)()
 	if err != nil {
 		return "", err
 	}
@@ -87,3 +110,40 @@ func getHomeDir(hdp HomeDirProvider) (string, error) {
 	}
 	return homeDir, nil
 }
+
+// ScratchDir returns the absolute path of the directory inside the .dolt directory of the repo at repoRoot in fs where
+// intermediate files, such as sort runs or files that are written and then renamed into place, should be created.
+// Keeping these files within the repo ensures they are on the same filesystem as their final location so renames are
+// atomic.  The directory is created if it doesn't exist, with permissions 0700 when fs is the local filesystem.  Its
+// contents are only needed while the process that created them is running and can be removed with CleanScratchDir.
+func ScratchDir(fs filesys.Filesys, repoRoot string) (string, error) {
+	dir, err := fs.Abs(filepath.Join(repoRoot, dbfactory.DoltDir, scratchDir))
+
+	if err != nil {
+		return "", err
+	}
+
+	if filesys.IsLocalFS(fs) {
+		// MkDirs creates directories which anyone can read
+		err = os.MkdirAll(dir, 0700)
+	} else {
+		err = fs.MkDirs(dir)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// CleanScratchDir removes the scratch directory of the repo at repoRoot in fs along with everything in it
+func CleanScratchDir(fs filesys.Filesys, repoRoot string) error {
+	dir := filepath.Join(repoRoot, dbfactory.DoltDir, scratchDir)
+
+	if exists, _ := fs.Exists(dir); !exists {
+		return nil
+	}
+
+	return fs.Delete(dir, true)
+}
@@ -15,16 +15,25 @@
 package env
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 )
 
 func TestGetGlobalCfgPath(t *testing.T) {
 	homeDir := "/user/bheni"
 	expected := filepath.Join(homeDir, dbfactory.DoltDir, globalConfig)
-	actual, _ := getGlobalCfgPath(func() (string, error) {
+	actual, _ := getGlobalCfgPath(context.Background(), func() (string, error) {
 		return homeDir, nil
 	})
 
@@ -32,3 +41,146 @@ func TestGetGlobalCfgPath(t *testing.T) {
 		t.Error(actual, "!=", expected)
 	}
 }
+
+func TestHomeDirProviderFromContext(t *testing.T) {
+	tenantHomeDir := "/tenants/acme"
+	tenantHomeDirFunc := func() (string, error) {
+		return tenantHomeDir, nil
+	}
+
+	ctx := context.Background()
+	tenantCtx := WithHomeDirProvider(ctx, tenantHomeDirFunc)
+
+	dir, err := getCredsDir(ctx, testHomeDirFunc)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(testHomeDir, dbfactory.DoltDir, credsDir), dir)
+
+	dir, err = getCredsDir(tenantCtx, testHomeDirFunc)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tenantHomeDir, dbfactory.DoltDir, credsDir), dir)
+
+	cfgPath, err := getGlobalCfgPath(tenantCtx, testHomeDirFunc)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tenantHomeDir, dbfactory.DoltDir, globalConfig), cfgPath)
+
+	// a tenant's creds file isn't visible to another context
+	fileCreds, _ := genTestCreds(t)
+	fs := filesys.NewInMemFS([]string{testHomeDir, tenantHomeDir}, nil, testHomeDir)
+	require.NoError(t, fs.MkDirs(dir))
+	_, err = creds.JWKCredsWriteToDir(fs, dir, fileCreds)
+	require.NoError(t, err)
+
+	noEnv := func(string) (string, bool) { return "", false }
+	c, source, err := resolveCreds(tenantCtx, noEnv, fs, testHomeDirFunc, fileCreds.KeyIDBase32Str())
+	require.NoError(t, err)
+	assert.Equal(t, CredsFromFile, source)
+	assert.Equal(t, fileCreds.KeyIDBase32Str(), c.KeyIDBase32Str())
+
+	_, source, err = resolveCreds(ctx, noEnv, fs, testHomeDirFunc, fileCreds.KeyIDBase32Str())
+	assert.Equal(t, creds.ErrCredsNotFound, err)
+	assert.Equal(t, CredsNotFound, source)
+}
+
+func TestDoltEnvCredsUseContextHomeDir(t *testing.T) {
+	tenantHomeDir := "/tenants/acme"
+	tenantCtx := WithHomeDirProvider(context.Background(), func() (string, error) {
+		return tenantHomeDir, nil
+	})
+
+	dEnv := createTestEnv(true, true)
+	dir, err := dEnv.CredsDir(tenantCtx)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tenantHomeDir, dbfactory.DoltDir, credsDir), dir)
+
+	fileCreds, _ := genTestCreds(t)
+	require.NoError(t, dEnv.FS.MkDirs(dir))
+	_, err = creds.JWKCredsWriteToDir(dEnv.FS, dir, fileCreds)
+	require.NoError(t, err)
+
+	localCfg, ok := dEnv.Config.GetConfig(LocalConfig)
+	require.True(t, ok)
+	require.NoError(t, localCfg.SetStrings(map[string]string{UserCreds: fileCreds.KeyIDBase32Str()}))
+
+	c, valid, err := dEnv.UserRPCCreds(tenantCtx)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, fileCreds.KeyIDBase32Str(), c.KeyIDBase32Str())
+
+	// the tenant's creds aren't in the environment's own home directory
+	_, valid, err = dEnv.UserRPCCreds(context.Background())
+	assert.Equal(t, creds.ErrCredsNotFound, err)
+	assert.False(t, valid)
+}
+
+func TestScratchDir(t *testing.T) {
+	repoRoot, err := ioutil.TempDir("", "scratch_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(repoRoot)
+
+	dir, err := ScratchDir(filesys.LocalFS, repoRoot)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repoRoot, dbfactory.DoltDir, scratchDir), dir)
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	// calling again returns the existing directory
+	again, err := ScratchDir(filesys.LocalFS, repoRoot)
+	require.NoError(t, err)
+	assert.Equal(t, dir, again)
+
+	// the scratch dir is within the repo so files written there can be renamed into place
+	rel, err := filepath.Rel(repoRoot, dir)
+	require.NoError(t, err)
+	assert.False(t, strings.HasPrefix(rel, ".."))
+
+	f, err := ioutil.TempFile(dir, "write_")
+	require.NoError(t, err)
+	_, err = f.WriteString("data")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	dest := filepath.Join(repoRoot, dbfactory.DoltDir, "renamed")
+	require.NoError(t, os.Rename(f.Name(), dest))
+	data, err := ioutil.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	require.NoError(t, CleanScratchDir(filesys.LocalFS, repoRoot))
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	// cleaning a repo without a scratch dir does nothing
+	require.NoError(t, CleanScratchDir(filesys.LocalFS, repoRoot))
+}
+
+func TestScratchDirInMemFS(t *testing.T) {
+	fs := filesys.EmptyInMemFS("/repo")
+
+	dir, err := ScratchDir(fs, ".")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/repo", dbfactory.DoltDir, scratchDir), dir)
+
+	exists, isDir := fs.Exists(dir)
+	assert.True(t, exists)
+	assert.True(t, isDir)
+
+	// nothing is created on the local filesystem
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	fp := filepath.Join(dir, "write_0")
+	require.NoError(t, fs.WriteFile(fp, []byte("data")))
+	dest := filepath.Join("/repo", dbfactory.DoltDir, "renamed")
+	require.NoError(t, fs.MoveFile(fp, dest))
+	data, err := fs.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	require.NoError(t, CleanScratchDir(fs, "."))
+	exists, _ = fs.Exists(dir)
+	assert.False(t, exists)
+	require.NoError(t, CleanScratchDir(fs, "."))
+}
@@ -146,13 +146,29 @@ func CreateRepoState(fs filesys.ReadWriteFS, br string, rootHash hash.Hash) (*Re
 	return rs, nil
 }
 
-func (rs *RepoState) Save(fs filesys.ReadWriteFS) error {
+// Save writes the repo state to the repo state file.  On the local filesystem the repo state lock is held while the
+// file is written, so that concurrent dolt processes saving the repo state don't corrupt it.
+func (rs *RepoState) Save(fs filesys.ReadWriteFS) (err error) {
 	data, err := json.MarshalIndent(rs, "", "  ")
 
 	if err != nil {
 		return err
 	}
 
+	unlock, err := lockRepoStateFile(fs, DefaultRepoStateLockTimeout)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		unlockErr := unlock()
+
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
 	path := getRepoStateFile()
 
 	return fs.WriteFile(path, data)
@@ -0,0 +1,78 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dolthub/fslock"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// ErrRepoStateLocked is returned by AcquireRepoStateLock when another process holds the lock for longer than the
+// timeout given
+var ErrRepoStateLocked = errors.New("repo is locked by another process")
+
+// DefaultRepoStateLockTimeout is how long RepoState.Save waits for another process to release the lock
+const DefaultRepoStateLockTimeout = 5 * time.Second
+
+// AcquireRepoStateLock takes an exclusive advisory lock guarding the repo state file of the repo at repoRoot, so that
+// concurrent dolt processes writing the file don't corrupt it.  The lock is held on a sidecar file in the .dolt
+// directory using flock, or LockFileEx on Windows, so it is released by the OS if the process exits.  Writers should
+// hold the lock for the duration of each write and release it by calling the returned unlock function.  If the lock
+// can't be acquired within timeout ErrRepoStateLocked is returned.  Because the lock is advisory it only excludes other
+// callers of AcquireRepoStateLock, which include RepoState.Save.
+func AcquireRepoStateLock(repoRoot string, timeout time.Duration) (unlock func() error, err error) {
+	doltDir := filepath.Join(repoRoot, dbfactory.DoltDir)
+	err = os.MkdirAll(doltDir, os.ModePerm)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lck := fslock.New(filepath.Join(doltDir, repoStateLockFile))
+	err = lck.LockWithTimeout(timeout)
+
+	if err == fslock.ErrTimeout {
+		return nil, ErrRepoStateLocked
+	} else if err != nil {
+		return nil, err
+	}
+
+	return lck.Unlock, nil
+}
+
+// lockRepoStateFile takes the repo state lock of the repo whose state file is written through fs, returning an unlock
+// function which does nothing for filesystems not backed by the local filesystem, such as those of tests, as no other
+// process can write to them
+func lockRepoStateFile(fs filesys.ReadableFS, timeout time.Duration) (unlock func() error, err error) {
+	if !filesys.IsLocalFS(fs) {
+		return func() error { return nil }, nil
+	}
+
+	path, err := fs.Abs(getRepoStateFile())
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the state file is in the .dolt directory at the root of the repo
+	return AcquireRepoStateLock(filepath.Dir(filepath.Dir(path)), timeout)
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestAcquireRepoStateLock(t *testing.T) {
+	// flock and LockFileEx locks taken through separate file handles exclude each other within a process on linux and
+	// darwin, which lets a second lock stand in for another process.  Elsewhere the behavior isn't guaranteed.
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("in process lock contention is not supported on " + runtime.GOOS)
+	}
+
+	repoRoot, err := ioutil.TempDir("", "repo_state_lock")
+	require.NoError(t, err)
+	defer os.RemoveAll(repoRoot)
+
+	unlock, err := AcquireRepoStateLock(repoRoot, 50*time.Millisecond)
+	require.NoError(t, err)
+
+	_, err = AcquireRepoStateLock(repoRoot, 50*time.Millisecond)
+	assert.Equal(t, ErrRepoStateLocked, err)
+
+	// a waiting writer acquires the lock once it's released
+	acquired := make(chan error)
+	go func() {
+		unlockWaiter, err := AcquireRepoStateLock(repoRoot, 5*time.Second)
+
+		if err == nil {
+			err = unlockWaiter()
+		}
+
+		acquired <- err
+	}()
+
+	select {
+	case err := <-acquired:
+		t.Fatal("lock acquired while held", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, unlock())
+	require.NoError(t, <-acquired)
+
+	unlock, err = AcquireRepoStateLock(repoRoot, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+}
+
+func TestRepoStateSaveTakesLock(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("in process lock contention is not supported on " + runtime.GOOS)
+	}
+
+	repoRoot, err := ioutil.TempDir("", "repo_state_save_lock")
+	require.NoError(t, err)
+	defer os.RemoveAll(repoRoot)
+
+	fs, err := filesys.LocalFilesysWithWorkingDir(repoRoot)
+	require.NoError(t, err)
+
+	unlock, err := AcquireRepoStateLock(repoRoot, 50*time.Millisecond)
+	require.NoError(t, err)
+
+	rs := &RepoState{Head: ref.MarshalableRef{Ref: ref.NewBranchRef("master")}, Staged: "staged", Working: "working"}
+	saved := make(chan error)
+	go func() {
+		saved <- rs.Save(fs)
+	}()
+
+	select {
+	case err := <-saved:
+		t.Fatal("repo state saved while locked", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	exists, _ := fs.Exists(getRepoStateFile())
+	assert.False(t, exists)
+
+	require.NoError(t, unlock())
+	require.NoError(t, <-saved)
+
+	loaded, err := LoadRepoState(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "working", loaded.Working)
+
+	// the lock is released once the state is saved
+	unlock, err = AcquireRepoStateLock(repoRoot, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+}
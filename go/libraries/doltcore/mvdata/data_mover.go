@@ -95,11 +95,23 @@ type GCTableWriteCloser interface {
 	GC(ctx context.Context) error
 }
 
+// AbortableTableWriteCloser is a table.TableWriteCloser whose output can be abandoned when a move fails, rather than
+// closed as though it were complete
+type AbortableTableWriteCloser interface {
+	table.TableWriteCloser
+	Abort(ctx context.Context) error
+}
+
 // Move is the method that executes the pipeline which will move data from the pipeline's source DataLocation to it's
 // dest DataLocation.  It returns the number of bad rows encountered during import, and an error.
 func (imp *DataMover) Move(ctx context.Context) (badRowCount int64, err error) {
 	defer imp.Rd.Close(ctx)
 	defer func() {
+		if abortWr, ok := imp.Wr.(AbortableTableWriteCloser); ok && err != nil {
+			_ = abortWr.Abort(ctx)
+			return
+		}
+
 		closeErr := imp.Wr.Close(ctx)
 		if err == nil {
 			err = closeErr
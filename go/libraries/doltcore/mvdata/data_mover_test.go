@@ -0,0 +1,113 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// failingReader returns its rows and then err, or io.EOF if err is nil
+type failingReader struct {
+	sch  schema.Schema
+	rows []row.Row
+	err  error
+}
+
+func (rd *failingReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+func (rd *failingReader) ReadRow(ctx context.Context) (row.Row, error) {
+	if len(rd.rows) > 0 {
+		r := rd.rows[0]
+		rd.rows = rd.rows[1:]
+		return r, nil
+	}
+
+	if rd.err != nil {
+		return nil, rd.err
+	}
+
+	return nil, io.EOF
+}
+
+func (rd *failingReader) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestMoveAbortsWriterOnError(t *testing.T) {
+	_, sch := untyped.NewUntypedSchema("a", "b")
+
+	var rows []row.Row
+	for _, vals := range [][]string{{"1", "one"}, {"2", "two"}} {
+		r, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.String(vals[0]), 1: types.String(vals[1])})
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+
+	move := func(readErr error) ([]byte, error) {
+		var compressed bytes.Buffer
+		gzw, err := iohelp.NewGzipWriteCloser(iohelp.NopWrCloser(&compressed))
+		require.NoError(t, err)
+		wr, err := json.NewJSONWriter(gzw, sch)
+		require.NoError(t, err)
+
+		mover := &DataMover{
+			Rd:         &failingReader{sch: sch, rows: rows, err: readErr},
+			Transforms: pipeline.NewTransformCollection(),
+			Wr:         wr,
+		}
+
+		_, err = mover.Move(context.Background())
+		return compressed.Bytes(), err
+	}
+
+	compressed, err := move(nil)
+	require.NoError(t, err)
+	rd, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(rd)
+	require.NoError(t, err)
+	assert.Equal(t, `{"rows": [{"a":"1","b":"one"},{"a":"2","b":"two"}]}`, string(data))
+
+	// a failed export isn't left as a valid gzip file
+	readErr := errors.New("read failed")
+	compressed, err = move(readErr)
+	require.Error(t, err)
+	rd, err = gzip.NewReader(bytes.NewReader(compressed))
+
+	if err == nil {
+		_, err = ioutil.ReadAll(rd)
+	}
+
+	assert.Error(t, err)
+}
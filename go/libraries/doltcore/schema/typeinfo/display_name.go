@@ -0,0 +1,48 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import "strings"
+
+// TypeInfoDisplayName returns a concise, lower case label for the type given along with its parameters, such as "int",
+// "varchar(255)", or "decimal(10,2)", for use in headers and metadata.  Character sets and collations are left out.
+// Types without a SQL equivalent, and a nil TypeInfo, are labeled with their identifier.
+func TypeInfoDisplayName(ti TypeInfo) string {
+	if ti == nil {
+		return string(UnknownTypeIdentifier)
+	}
+
+	switch id := ti.GetTypeIdentifier(); id {
+	case UnknownTypeIdentifier, TupleTypeIdentifier, BoolTypeIdentifier, UuidTypeIdentifier:
+		return string(id)
+	}
+
+	name := ti.ToSqlType().String()
+
+	// enum and set members come before the character set and collation, so only search after them
+	paramsEnd := strings.LastIndex(name, ")") + 1
+	for _, clause := range []string{" CHARACTER SET ", " COLLATE "} {
+		if pos := strings.Index(name[paramsEnd:], clause); pos != -1 {
+			name = name[:paramsEnd+pos]
+		}
+	}
+
+	// lower case the name of the type, but not the members of an enum or set
+	if pos := strings.Index(name, "("); pos != -1 {
+		return strings.ToLower(name[:pos]) + name[pos:]
+	}
+
+	return strings.ToLower(name)
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeInfoDisplayName(t *testing.T) {
+	tests := []struct {
+		ti       TypeInfo
+		expected string
+	}{
+		{Int32Type, "int"},
+		{Int64Type, "bigint"},
+		{Uint8Type, "tinyint unsigned"},
+		{Float64Type, "double"},
+		{BoolType, "bool"},
+		{UuidType, "uuid"},
+		{DatetimeType, "datetime"},
+		{&bitType{sql.MustCreateBitType(7)}, "bit(7)"},
+		{&decimalType{sql.MustCreateDecimalType(10, 2)}, "decimal(10,2)"},
+		{&varStringType{sql.MustCreateStringWithDefaults(sqltypes.VarChar, 255)}, "varchar(255)"},
+		{&varStringType{sql.MustCreateString(sqltypes.Char, 10, sql.Collation_utf8mb4_general_ci)}, "char(10)"},
+		{&varStringType{sql.CreateText(sql.Collation_Default)}, "text"},
+		{&varBinaryType{sql.LongBlob}, "longblob"},
+		{&enumType{sql.MustCreateEnumType([]string{"Red", "green"}, sql.Collation_utf8mb4_general_ci)}, "enum('Red','green')"},
+		{&setType{sql.MustCreateSetType([]string{"A", "b"}, sql.Collation_Default)}, "set('A','b')"},
+		{UnknownType, "unknown"},
+		{nil, "unknown"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, TypeInfoDisplayName(test.ti))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"math"
+)
+
+// FloatSpecialRendering holds the representations written in place of the special float values NaN, +Inf and -Inf.
+// A nil representation writes the value as NULL.
+type FloatSpecialRendering struct {
+	NaN    *string
+	PosInf *string
+	NegInf *string
+}
+
+var (
+	nanStr    = "NaN"
+	posInfStr = "+Inf"
+	negInfStr = "-Inf"
+)
+
+// DefaultFloatSpecialRendering renders the special values the same way FormatValue does
+var DefaultFloatSpecialRendering = FloatSpecialRendering{NaN: &nanStr, PosInf: &posInfStr, NegInf: &negInfStr}
+
+// NullFloatSpecialRendering writes every special value as NULL.  Formats without a representation for the special
+// values, such as JSON, use this by default.
+var NullFloatSpecialRendering = FloatSpecialRendering{}
+
+// Render returns the representation of f along with true if f is NaN or infinite.  For any other value it returns
+// nil and false.
+func (fsr FloatSpecialRendering) Render(f float64) (*string, bool) {
+	switch {
+	case math.IsNaN(f):
+		return fsr.NaN, true
+	case math.IsInf(f, 1):
+		return fsr.PosInf, true
+	case math.IsInf(f, -1):
+		return fsr.NegInf, true
+	default:
+		return nil, false
+	}
+}
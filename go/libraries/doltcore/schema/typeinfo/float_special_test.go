@@ -0,0 +1,45 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestFloatSpecialRendering(t *testing.T) {
+	// the default rendering matches FormatValue
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		formatted, err := Float64Type.FormatValue(types.Float(f))
+		require.NoError(t, err)
+
+		rendered, ok := DefaultFloatSpecialRendering.Render(f)
+		assert.True(t, ok)
+		assert.Equal(t, *formatted, *rendered)
+
+		rendered, ok = NullFloatSpecialRendering.Render(f)
+		assert.True(t, ok)
+		assert.Nil(t, rendered)
+	}
+
+	rendered, ok := DefaultFloatSpecialRendering.Render(1.5)
+	assert.False(t, ok)
+	assert.Nil(t, rendered)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"unsafe"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -239,6 +240,23 @@ func fromBlobLength(b types.Blob) (uint64, error) {
 	return binary.LittleEndian.Uint64(countBytes), nil
 }
 
+// VarBinaryReader returns a reader over the data of a types.Blob written by a varbinary column, along with the length of
+// the data, without reading the data into memory.
+func VarBinaryReader(ctx context.Context, b types.Blob) (io.Reader, uint64, error) {
+	length, err := fromBlobLength(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	if length == 0 {
+		return strings.NewReader(""), 0, nil
+	}
+	rd := b.Reader(ctx)
+	if _, err = rd.Seek(8, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	return io.LimitReader(rd, int64(length)), length, nil
+}
+
 // toBlob returns a types.Blob from a string.
 func toBlob(ctx context.Context, vrw types.ValueReadWriter, s string) (types.Blob, error) {
 	data := make([]byte, 8+len(s))
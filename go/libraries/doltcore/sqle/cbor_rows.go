@@ -0,0 +1,287 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+// CBOR major types, RFC 8949 section 3.1
+const (
+	cborUint   byte = 0 << 5
+	cborNegInt byte = 1 << 5
+	cborBytes  byte = 2 << 5
+	cborText   byte = 3 << 5
+	cborMap    byte = 5 << 5
+	cborTag    byte = 6 << 5
+)
+
+// CBOR simple values and the headers of floats, RFC 8949 section 3.3
+const (
+	cborFalse   byte = 0xf4
+	cborTrue    byte = 0xf5
+	cborNull    byte = 0xf6
+	cborFloat32 byte = 0xfa
+	cborFloat64 byte = 0xfb
+)
+
+// cborDateTimeTag is the tag of a date and time in RFC 3339 text form
+const cborDateTimeTag = 0
+
+// CBORRowWriter writes the rows produced by a KVToSqlRowConverter to an io.Writer as a CBOR sequence (RFC 8742), one
+// CBOR map from column name to value per row.  Unlike JSON, binary values are written as they are stored, which makes
+// it a compact format for pipelines that handle binary data.
+//
+// Values are written as the following CBOR items:
+//   - NULL as null
+//   - varbinary and inlineblob columns, including LazyBlobs, as byte strings
+//   - ints, uints and bools as integers and booleans
+//   - float columns as single or double precision floats
+//   - datetimes as RFC 3339 strings with tag 0
+//   - all other columns as text strings, with decimals and other values in their textual form
+type CBORRowWriter struct {
+	wr     io.Writer
+	names  []string
+	binary []bool
+	numCol int
+	buf    []byte
+}
+
+// NewCBORRowWriter returns a CBORRowWriter which writes the rows produced by conv to wr.  Positions in the rows which
+// no column maps to are left out of the written maps.
+func NewCBORRowWriter(wr io.Writer, conv *KVToSqlRowConverter) *CBORRowWriter {
+	cw := &CBORRowWriter{
+		wr:     wr,
+		names:  make([]string, conv.rowSize),
+		binary: make([]bool, conv.rowSize),
+		numCol: len(conv.tagToSqlColIdx),
+	}
+
+	for _, idx := range conv.tagToSqlColIdx {
+		col := conv.cols[idx]
+		cw.names[idx] = col.Name
+
+		switch col.TypeInfo.GetTypeIdentifier() {
+		case typeinfo.VarBinaryTypeIdentifier, typeinfo.InlineBlobTypeIdentifier:
+			cw.binary[idx] = true
+		}
+	}
+
+	return cw
+}
+
+// WriteRow writes the row given as a CBOR map.  The row must have been produced by the converter the writer was created
+// with.
+func (cw *CBORRowWriter) WriteRow(ctx context.Context, r sql.Row) error {
+	cw.buf = appendCBORHeader(cw.buf[:0], cborMap, uint64(cw.numCol))
+
+	for idx, name := range cw.names {
+		if name == "" {
+			continue
+		}
+
+		cw.buf = appendCBORHeader(cw.buf, cborText, uint64(len(name)))
+		cw.buf = append(cw.buf, name...)
+
+		val := SqlRowValue(r, idx)
+
+		if lb, ok := val.(*LazyBlob); ok {
+			err := cw.writeLazyBlob(ctx, lb)
+
+			if err != nil {
+				return fmt.Errorf("failed to write column '%s': %w", name, err)
+			}
+
+			continue
+		}
+
+		var err error
+		cw.buf, err = appendCBORValue(cw.buf, val, cw.binary[idx])
+
+		if err != nil {
+			return fmt.Errorf("failed to write column '%s': %w", name, err)
+		}
+	}
+
+	_, err := cw.wr.Write(cw.buf)
+	return err
+}
+
+// WriteRows writes every row of itr, returning the number of rows written.  The iterator is not closed.
+func (cw *CBORRowWriter) WriteRows(ctx *sql.Context, itr sql.RowIter) (int64, error) {
+	var numRows int64
+	for {
+		r, err := itr.Next()
+
+		if err == io.EOF {
+			return numRows, nil
+		} else if err != nil {
+			return numRows, err
+		}
+
+		err = cw.WriteRow(ctx, r)
+
+		if err != nil {
+			return numRows, err
+		}
+
+		numRows++
+	}
+}
+
+// writeLazyBlob flushes the buffered part of the row and then streams the blob's data to the writer as a byte string
+func (cw *CBORRowWriter) writeLazyBlob(ctx context.Context, lb *LazyBlob) error {
+	length, err := lb.Len()
+
+	if err != nil {
+		return err
+	}
+
+	cw.buf = appendCBORHeader(cw.buf, cborBytes, length)
+	_, err = cw.wr.Write(cw.buf)
+
+	if err != nil {
+		return err
+	}
+
+	cw.buf = cw.buf[:0]
+	rd, err := lb.NewReader(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(cw.wr, rd)
+
+	if err != nil {
+		return err
+	} else if uint64(n) != length {
+		return fmt.Errorf("blob of length %d returned %d bytes", length, n)
+	}
+
+	return nil
+}
+
+// appendCBORValue appends the CBOR encoding of a converted value.  Strings are written as byte strings if binary is
+// true.
+func appendCBORValue(buf []byte, val interface{}, binary bool) ([]byte, error) {
+	switch v := val.(type) {
+	case nil:
+		return append(buf, cborNull), nil
+	case bool:
+		if v {
+			return append(buf, cborTrue), nil
+		}
+
+		return append(buf, cborFalse), nil
+	case int8:
+		return appendCBORInt(buf, int64(v)), nil
+	case int16:
+		return appendCBORInt(buf, int64(v)), nil
+	case int32:
+		return appendCBORInt(buf, int64(v)), nil
+	case int64:
+		return appendCBORInt(buf, v), nil
+	case int:
+		return appendCBORInt(buf, int64(v)), nil
+	case uint8:
+		return appendCBORHeader(buf, cborUint, uint64(v)), nil
+	case uint16:
+		return appendCBORHeader(buf, cborUint, uint64(v)), nil
+	case uint32:
+		return appendCBORHeader(buf, cborUint, uint64(v)), nil
+	case uint64:
+		return appendCBORHeader(buf, cborUint, v), nil
+	case uint:
+		return appendCBORHeader(buf, cborUint, uint64(v)), nil
+	case float32:
+		buf = append(buf, cborFloat32)
+		return appendUint32(buf, math.Float32bits(v)), nil
+	case float64:
+		buf = append(buf, cborFloat64)
+		return appendUint64(buf, math.Float64bits(v)), nil
+	case string:
+		if binary {
+			buf = appendCBORHeader(buf, cborBytes, uint64(len(v)))
+		} else {
+			buf = appendCBORHeader(buf, cborText, uint64(len(v)))
+		}
+
+		return append(buf, v...), nil
+	case []byte:
+		buf = appendCBORHeader(buf, cborBytes, uint64(len(v)))
+		return append(buf, v...), nil
+	case time.Time:
+		str := v.Format(time.RFC3339Nano)
+		buf = appendCBORHeader(buf, cborTag, cborDateTimeTag)
+		buf = appendCBORHeader(buf, cborText, uint64(len(str)))
+		return append(buf, str...), nil
+	case fmt.Stringer:
+		str := v.String()
+		buf = appendCBORHeader(buf, cborText, uint64(len(str)))
+		return append(buf, str...), nil
+	default:
+		return buf, fmt.Errorf("cannot encode value of type %T as CBOR", val)
+	}
+}
+
+// appendCBORInt appends a signed integer, which CBOR stores as an unsigned integer or as a negative integer of -1 - n
+func appendCBORInt(buf []byte, n int64) []byte {
+	if n >= 0 {
+		return appendCBORHeader(buf, cborUint, uint64(n))
+	}
+
+	return appendCBORHeader(buf, cborNegInt, uint64(-1-n))
+}
+
+// appendCBORHeader appends the initial byte of an item of the major type given along with its argument, which is the
+// value of an integer or the length of a string or map, in the fewest bytes possible
+func appendCBORHeader(buf []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(buf, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(buf, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		buf = append(buf, major|25, 0, 0)
+		binary.BigEndian.PutUint16(buf[len(buf)-2:], uint16(arg))
+		return buf
+	case arg <= math.MaxUint32:
+		return appendUint32(append(buf, major|26), uint32(arg))
+	default:
+		return appendUint64(append(buf, major|27), arg)
+	}
+}
+
+func appendUint32(buf []byte, n uint32) []byte {
+	buf = append(buf, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(buf[len(buf)-4:], n)
+	return buf
+}
+
+func appendUint64(buf []byte, n uint64) []byte {
+	buf = append(buf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(buf[len(buf)-8:], n)
+	return buf
+}
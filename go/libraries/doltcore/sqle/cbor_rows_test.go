@@ -0,0 +1,242 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCBORRowWriter(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	dataTI, err := typeinfo.FromSqlType(sql.LongBlob)
+	require.NoError(t, err)
+	dataCol, err := schema.NewColumnWithTypeInfo("data", 2, dataTI, false, "", false, "")
+	require.NoError(t, err)
+	cols := []schema.Column{
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		dataCol,
+		schema.NewColumn("score", 3, types.FloatKind, false),
+		schema.NewColumn("created", 4, types.TimestampKind, false),
+		schema.NewColumn("count", 5, types.UintKind, false),
+	}
+
+	created := time.Date(2021, 3, 14, 15, 9, 26, 535000000, time.UTC)
+	blob := string([]byte{0, 1, 0xfe, 0xff, '"'})
+	blobVal, err := dataCol.TypeInfo.ConvertValueToNomsValue(ctx, vrw, blob)
+	require.NoError(t, err)
+
+	var kvs []types.Tuple
+	for _, vals := range [][]types.Value{
+		{types.Int(1), types.String("bill"), blobVal, types.Float(2.5), types.Timestamp(created), types.Uint(300)},
+		{types.Int(-70000), nil, nil, nil, nil, nil},
+	} {
+		k, err := types.NewTuple(vrw.Format(), types.Uint(0), vals[0])
+		require.NoError(t, err)
+
+		var taggedVals []types.Value
+		for i := 1; i < len(vals); i++ {
+			if vals[i] != nil {
+				taggedVals = append(taggedVals, types.Uint(cols[i].Tag), vals[i])
+			}
+		}
+
+		v, err := types.NewTuple(vrw.Format(), taggedVals...)
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	// CBOR doesn't distinguish signed from unsigned integers, so non-negative values decode as uint64
+	expected := []map[string]interface{}{
+		{"id": uint64(1), "name": "bill", "data": []byte(blob), "score": 2.5, "created": created, "count": uint64(300)},
+		{"id": int64(-70000), "name": nil, "data": nil, "score": nil, "created": nil, "count": nil},
+	}
+
+	for _, lazy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("lazy blobs %t", lazy), func(t *testing.T) {
+			var opts []KVToSqlRowConverterOption
+			if lazy {
+				opts = append(opts, WithLazyBlobs())
+			}
+
+			conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), cols, opts...)
+			require.NoError(t, err)
+
+			buf := &bytes.Buffer{}
+			cw := NewCBORRowWriter(buf, conv)
+			dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+			numRows, err := cw.WriteRows(sql.NewEmptyContext(), dmi)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), numRows)
+
+			var rows []map[string]interface{}
+			rd := bytes.NewReader(buf.Bytes())
+			for rd.Len() > 0 {
+				item, err := decodeCBORItem(rd)
+				require.NoError(t, err)
+				rows = append(rows, item.(map[string]interface{}))
+			}
+
+			assert.Equal(t, expected, rows)
+		})
+	}
+}
+
+func TestAppendCBORValue(t *testing.T) {
+	tests := []struct {
+		val      interface{}
+		binary   bool
+		expected []byte
+	}{
+		{nil, false, []byte{0xf6}},
+		{true, false, []byte{0xf5}},
+		{int64(10), false, []byte{0x0a}},
+		{int64(-1), false, []byte{0x20}},
+		{int8(-100), false, []byte{0x38, 0x63}},
+		{uint64(1000), false, []byte{0x19, 0x03, 0xe8}},
+		{uint64(math.MaxUint64), false, []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{float64(1.1), false, []byte{0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}},
+		{"a", false, []byte{0x61, 'a'}},
+		{"a", true, []byte{0x41, 'a'}},
+	}
+
+	for _, test := range tests {
+		buf, err := appendCBORValue(nil, test.val, test.binary)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, buf, "%v", test.val)
+	}
+
+	_, err := appendCBORValue(nil, struct{}{}, false)
+	assert.Error(t, err)
+}
+
+// decodeCBORItem decodes the subset of CBOR written by a CBORRowWriter
+func decodeCBORItem(rd *bytes.Reader) (interface{}, error) {
+	initial, err := rd.ReadByte()
+
+	if err != nil {
+		return nil, err
+	}
+
+	major, info := initial&0xe0, initial&0x1f
+
+	switch initial {
+	case cborFalse:
+		return false, nil
+	case cborTrue:
+		return true, nil
+	case cborNull:
+		return nil, nil
+	case cborFloat32:
+		var bits uint32
+		err = binary.Read(rd, binary.BigEndian, &bits)
+		return math.Float32frombits(bits), err
+	case cborFloat64:
+		var bits uint64
+		err = binary.Read(rd, binary.BigEndian, &bits)
+		return math.Float64frombits(bits), err
+	}
+
+	var arg uint64
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info == 24:
+		var n uint8
+		err = binary.Read(rd, binary.BigEndian, &n)
+		arg = uint64(n)
+	case info == 25:
+		var n uint16
+		err = binary.Read(rd, binary.BigEndian, &n)
+		arg = uint64(n)
+	case info == 26:
+		var n uint32
+		err = binary.Read(rd, binary.BigEndian, &n)
+		arg = uint64(n)
+	case info == 27:
+		err = binary.Read(rd, binary.BigEndian, &arg)
+	default:
+		return nil, fmt.Errorf("unsupported additional info %d", info)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUint:
+		return arg, nil
+	case cborNegInt:
+		return -1 - int64(arg), nil
+	case cborBytes, cborText:
+		data := make([]byte, arg)
+		_, err = io.ReadFull(rd, data)
+
+		if major == cborText {
+			return string(data), err
+		}
+
+		return data, err
+	case cborMap:
+		m := make(map[string]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, err := decodeCBORItem(rd)
+
+			if err != nil {
+				return nil, err
+			}
+
+			val, err := decodeCBORItem(rd)
+
+			if err != nil {
+				return nil, err
+			}
+
+			m[key.(string)] = val
+		}
+
+		return m, nil
+	case cborTag:
+		item, err := decodeCBORItem(rd)
+
+		if err != nil {
+			return nil, err
+		} else if arg != cborDateTimeTag {
+			return nil, fmt.Errorf("unsupported tag %d", arg)
+		}
+
+		return time.Parse(time.RFC3339Nano, item.(string))
+	default:
+		return nil, errors.New("unsupported major type")
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"reflect"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// hllPrecision is the number of hash bits used to pick a HyperLogLog register.  2^14 one byte registers give a
+// standard error of about 0.8% for 16KB per column.
+const hllPrecision = 14
+
+// ColumnStats are the statistics accumulated for a column by a DoltMapIter as its rows are read
+type ColumnStats struct {
+	// Name is the name of the column
+	Name string
+	// Tag is the tag of the column
+	Tag uint64
+	// Min is the smallest non-NULL value read, or nil if every value was NULL
+	Min interface{}
+	// Max is the largest non-NULL value read, or nil if every value was NULL
+	Max interface{}
+	// NullCount is the number of NULL values read
+	NullCount uint64
+	// RowCount is the number of rows read
+	RowCount uint64
+	// DistinctEstimate is an estimate of the number of distinct non-NULL values read, accurate to within a few percent
+	DistinctEstimate uint64
+}
+
+// columnStatsCollector accumulates the statistics of a single column
+type columnStatsCollector struct {
+	idx   int
+	typ   sql.Type
+	stats ColumnStats
+	hll   *hyperLogLog
+}
+
+// reset discards the statistics accumulated so far
+func (c *columnStatsCollector) reset() {
+	c.stats = ColumnStats{Name: c.stats.Name, Tag: c.stats.Tag}
+	c.hll = newHyperLogLog()
+}
+
+func (c *columnStatsCollector) update(r sql.Row) error {
+	c.stats.RowCount++
+
+	val := SqlRowValue(r, c.idx)
+
+	if val == nil {
+		c.stats.NullCount++
+		return nil
+	}
+
+	if _, ok := val.(*LazyBlob); ok {
+		// reading a lazy blob's contents would defeat the point of it
+		return nil
+	}
+
+	c.hll.add(hashStatsVal(val))
+
+	if c.stats.Min == nil {
+		c.stats.Min, c.stats.Max = val, val
+		return nil
+	}
+
+	cmp, err := c.typ.Compare(val, c.stats.Min)
+
+	if err != nil {
+		return fmt.Errorf("cannot compare values of column '%s': %w", c.stats.Name, err)
+	}
+
+	if cmp < 0 {
+		c.stats.Min = val
+	}
+
+	cmp, err = c.typ.Compare(val, c.stats.Max)
+
+	if err != nil {
+		return fmt.Errorf("cannot compare values of column '%s': %w", c.stats.Name, err)
+	}
+
+	if cmp > 0 {
+		c.stats.Max = val
+	}
+
+	return nil
+}
+
+// CollectStats causes the iterator to accumulate statistics for each of the columns with the tags given as rows are
+// read by Next and NextWithRawTuples.  Statistics are opt-in per column as each collected column adds a comparison and a
+// hash to every row read.  Min and max are compared using the column's sql type, so enums are ordered by member, and
+// decimals and datetimes by value.  Only the NULL and row counts of varbinary columns read as LazyBlobs are collected.
+// Calling CollectStats again discards the statistics collected so far, as does Reset, after which statistics are
+// collected for the same columns from the new source.
+func (dmi *DoltMapIter) CollectStats(tags ...uint64) error {
+	collectors := make([]*columnStatsCollector, 0, len(tags))
+	for _, tag := range tags {
+		idx, ok := dmi.conv.tagToSqlColIdx[tag]
+
+		if !ok {
+			return fmt.Errorf("cannot collect statistics for tag %d which is not being converted", tag)
+		}
+
+		col := dmi.conv.cols[idx]
+		collectors = append(collectors, &columnStatsCollector{
+			idx:   idx,
+			typ:   col.TypeInfo.ToSqlType(),
+			stats: ColumnStats{Name: col.Name, Tag: tag},
+			hll:   newHyperLogLog(),
+		})
+	}
+
+	dmi.stats = collectors
+	return nil
+}
+
+// Stats returns the statistics collected so far for each of the columns passed to CollectStats, in the order they were
+// given.
+func (dmi *DoltMapIter) Stats() []ColumnStats {
+	stats := make([]ColumnStats, len(dmi.stats))
+	for i, c := range dmi.stats {
+		stats[i] = c.stats
+		stats[i].DistinctEstimate = c.hll.estimate()
+	}
+
+	return stats
+}
+
+func (dmi *DoltMapIter) updateStats(r sql.Row) error {
+	for _, c := range dmi.stats {
+		err := c.update(r)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hyperLogLog estimates the number of distinct hashes added to it
+type hyperLogLog struct {
+	registers []uint8
+}
+
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{registers: make([]uint8, 1<<hllPrecision)}
+}
+
+func (h *hyperLogLog) add(hash uint64) {
+	idx := hash >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1))) + 1
+
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *hyperLogLog) estimate() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	est := 0.7213 / (1 + 1.079/m) * m * m / sum
+
+	// small cardinalities are estimated more accurately by linear counting
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(est + 0.5)
+}
+
+// hashStatsVal returns a well distributed 64 bit hash of a sql value
+func hashStatsVal(val interface{}) uint64 {
+	hsh := fnv.New64a()
+	var buf [8]byte
+
+	rv := reflect.ValueOf(val)
+	switch {
+	case isIntKind(rv.Kind()):
+		binary.LittleEndian.PutUint64(buf[:], uint64(rv.Int()))
+		_, _ = hsh.Write(buf[:])
+	case isUintKind(rv.Kind()):
+		binary.LittleEndian.PutUint64(buf[:], rv.Uint())
+		_, _ = hsh.Write(buf[:])
+	case isFloatKind(rv.Kind()):
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(rv.Float()))
+		_, _ = hsh.Write(buf[:])
+	case rv.Kind() == reflect.String:
+		_, _ = hsh.Write([]byte(rv.String()))
+	default:
+		if t, ok := val.(time.Time); ok {
+			binary.LittleEndian.PutUint64(buf[:], uint64(t.UnixNano()))
+			_, _ = hsh.Write(buf[:])
+		} else {
+			_, _ = fmt.Fprintf(hsh, "%v", val)
+		}
+	}
+
+	// fnv's output isn't well distributed in its high bits, which pick the register, so it is mixed using the
+	// splitmix64 finalizer
+	x := hsh.Sum64()
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDoltMapIterStats(t *testing.T) {
+	ctx := context.Background()
+	early := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	var kvs []types.Tuple
+	for _, vals := range [][]types.Value{
+		{types.String("bob"), types.Uint(40), types.Float(-1.5), types.Timestamp(late)},
+		{types.String("alice"), nil, types.Float(3), nil},
+		{types.String("carl"), types.Uint(7), nil, types.Timestamp(early)},
+		{nil, nil, types.Float(0), nil},
+	} {
+		k, v := mapIterTestTuples(t, int64(len(kvs)), vals...)
+		kvs = append(kvs, k, v)
+	}
+
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	assert.Error(t, dmi.CollectStats(mapIterNameTag, 100))
+	require.NoError(t, dmi.CollectStats(mapIterNameTag, mapIterAgeTag, mapIterScoreTag, mapIterCreatedTag))
+
+	// the first two rows are read with NextWithRawTuples to verify it also collects statistics
+	for i := 0; i < 2; i++ {
+		_, _, _, err = dmi.NextWithRawTuples()
+		require.NoError(t, err)
+	}
+	drainRowIter(t, dmi)
+
+	assert.Equal(t, []ColumnStats{
+		{Name: "name", Tag: mapIterNameTag, Min: "alice", Max: "carl", NullCount: 1, RowCount: 4, DistinctEstimate: 3},
+		{Name: "age", Tag: mapIterAgeTag, Min: uint64(7), Max: uint64(40), NullCount: 2, RowCount: 4, DistinctEstimate: 2},
+		{Name: "score", Tag: mapIterScoreTag, Min: -1.5, Max: float64(3), NullCount: 1, RowCount: 4, DistinctEstimate: 3},
+		{Name: "created", Tag: mapIterCreatedTag, Min: early, Max: late, NullCount: 2, RowCount: 4, DistinctEstimate: 2},
+	}, dmi.Stats())
+}
+
+func TestDoltMapIterStatsOrderedBySqlType(t *testing.T) {
+	ctx := context.Background()
+	enumType, err := sql.CreateEnumType([]string{"small", "medium", "large"}, sql.Collation_Default)
+	require.NoError(t, err)
+	enumTI, err := typeinfo.FromSqlType(enumType)
+	require.NoError(t, err)
+	decimalTI, err := typeinfo.FromSqlType(sql.MustCreateDecimalType(10, 2))
+	require.NoError(t, err)
+
+	enumCol, err := schema.NewColumnWithTypeInfo("size", 1, enumTI, false, "", false, "")
+	require.NoError(t, err)
+	decimalCol, err := schema.NewColumnWithTypeInfo("price", 2, decimalTI, false, "", false, "")
+	require.NoError(t, err)
+	cols := []schema.Column{schema.NewColumn("id", 0, types.IntKind, true), enumCol, decimalCol}
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithEnumSetMemberNames(nil))
+	require.NoError(t, err)
+
+	var kvs []types.Tuple
+	for i, vals := range [][2]string{{"medium", "9.50"}, {"large", "10.00"}, {"small", "100.25"}} {
+		enumVal, err := enumTI.ConvertValueToNomsValue(ctx, nil, vals[0])
+		require.NoError(t, err)
+		decimalVal, err := decimalTI.ConvertValueToNomsValue(ctx, nil, vals[1])
+		require.NoError(t, err)
+
+		k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(i))
+		require.NoError(t, err)
+		v, err := types.NewTuple(types.Format_Default, types.Uint(1), enumVal, types.Uint(2), decimalVal)
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	require.NoError(t, dmi.CollectStats(1, 2))
+	drainRowIter(t, dmi)
+
+	stats := dmi.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "small", stats[0].Min)
+	assert.Equal(t, "large", stats[0].Max)
+	assert.Equal(t, "9.50", stats[1].Min)
+	assert.Equal(t, "100.25", stats[1].Max)
+}
+
+func TestDoltMapIterStatsDistinctEstimate(t *testing.T) {
+	const numRows = 20000
+	const numDistinct = 5000
+
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	i := 0
+	get := func(ctx context.Context) (types.Tuple, types.Tuple, error) {
+		if i >= numRows {
+			return types.Tuple{}, types.Tuple{}, io.EOF
+		}
+
+		k, v := mapIterTestTuples(t, int64(i), types.String(fmt.Sprintf("name%d", i%numDistinct)))
+		i++
+		return k, v, nil
+	}
+
+	dmi := NewDoltMapIter(ctx, get, nil, conv)
+	require.NoError(t, dmi.CollectStats(mapIterPKTag, mapIterNameTag))
+	drainRowIter(t, dmi)
+
+	stats := dmi.Stats()
+	assert.InEpsilon(t, numRows, stats[0].DistinctEstimate, 0.05)
+	assert.InEpsilon(t, numDistinct, stats[1].DistinctEstimate, 0.05)
+	assert.Equal(t, int64(0), stats[0].Min)
+	assert.Equal(t, int64(numRows-1), stats[0].Max)
+}
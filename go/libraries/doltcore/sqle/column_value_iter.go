@@ -0,0 +1,87 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ColumnValueIter reads the value of a single column from each row of a source of key value pairs, for operations such
+// as SELECT DISTINCT which only need one column.  Only the column is decoded: value tuples are read up to its tag and
+// the rest of each tuple is skipped, and no row is allocated, so it is cheaper than converting full rows and projecting
+// the column out of them.
+type ColumnValueIter struct {
+	ctx           context.Context
+	kvGet         KVGetFunc
+	closeKVGetter func() error
+	conv          *KVToSqlRowConverter
+	vals          []interface{}
+}
+
+// NewColumnValueIter returns a ColumnValueIter which reads the values of col from the key value pairs returned by
+// keyValGet.  The options are applied to the converter used to read the column, so values can be read lazily or
+// transformed as they are with a KVToSqlRowConverter.
+func NewColumnValueIter(ctx context.Context, nbf *types.NomsBinFormat, col schema.Column, keyValGet KVGetFunc, closeKVGetter func() error, opts ...KVToSqlRowConverterOption) (*ColumnValueIter, error) {
+	conv, err := NewKVToSqlRowConverterForCols(nbf, []schema.Column{col}, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ColumnValueIter{
+		ctx:           ctx,
+		kvGet:         keyValGet,
+		closeKVGetter: closeKVGetter,
+		conv:          conv,
+		vals:          make([]interface{}, 1),
+	}, nil
+}
+
+// Next returns the value of the column for the next row, which is nil if the column is NULL, until all rows are
+// returned at which point (nil, io.EOF) is returned.
+func (itr *ColumnValueIter) Next() (interface{}, error) {
+	for {
+		k, v, err := itr.kvGet(itr.ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		itr.vals[0] = nil
+		err = itr.conv.readKVTuples(itr.vals, k, v, nil)
+
+		if err == ErrFilteredByKey {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		return itr.vals[0], nil
+	}
+}
+
+// Close closes the source of key value pairs
+func (itr *ColumnValueIter) Close(*sql.Context) error {
+	if itr.closeKVGetter != nil {
+		return itr.closeKVGetter()
+	}
+
+	return nil
+}
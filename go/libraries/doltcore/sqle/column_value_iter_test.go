@@ -0,0 +1,134 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// columnValueTestKVs returns the key and value tuples of numRows rows of the mapIterTestCols schema.  Every third row
+// has a NULL name.
+func columnValueTestKVs(tb testing.TB, numRows int) []types.Tuple {
+	created := types.Timestamp(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var kvs []types.Tuple
+	for i := 0; i < numRows; i++ {
+		k, err := types.NewTuple(types.Format_Default, types.Uint(mapIterPKTag), types.Int(i))
+		require.NoError(tb, err)
+
+		var vals []types.Value
+		if i%3 != 0 {
+			vals = append(vals, types.Uint(mapIterNameTag), types.String("name"))
+		}
+
+		vals = append(vals, types.Uint(mapIterAgeTag), types.Uint(i), types.Uint(mapIterScoreTag), types.Float(float64(i)/2), types.Uint(mapIterCreatedTag), created)
+		v, err := types.NewTuple(types.Format_Default, vals...)
+		require.NoError(tb, err)
+
+		kvs = append(kvs, k, v)
+	}
+
+	return kvs
+}
+
+func TestColumnValueIter(t *testing.T) {
+	ctx := context.Background()
+	kvs := columnValueTestKVs(t, 4)
+
+	for _, test := range []struct {
+		colIdx   int
+		expected []interface{}
+	}{
+		{0, []interface{}{int64(0), int64(1), int64(2), int64(3)}},
+		{1, []interface{}{nil, "name", "name", nil}},
+		{3, []interface{}{0.0, 0.5, 1.0, 1.5}},
+	} {
+		col := mapIterTestCols[test.colIdx]
+		t.Run(col.Name, func(t *testing.T) {
+			closed := false
+			itr, err := NewColumnValueIter(ctx, types.Format_Default, col, kvGetFuncForTuples(kvs...), func() error { closed = true; return nil })
+			require.NoError(t, err)
+
+			var vals []interface{}
+			for {
+				val, err := itr.Next()
+
+				if err == io.EOF {
+					break
+				}
+
+				require.NoError(t, err)
+				vals = append(vals, val)
+			}
+
+			assert.Equal(t, test.expected, vals)
+			assert.NoError(t, itr.Close(nil))
+			assert.True(t, closed)
+		})
+	}
+}
+
+func BenchmarkColumnValueIter(b *testing.B) {
+	ctx := context.Background()
+	kvs := columnValueTestKVs(b, 10000)
+	nameCol := mapIterTestCols[1]
+
+	b.Run("single column", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			itr, err := NewColumnValueIter(ctx, types.Format_Default, nameCol, kvGetFuncForTuples(kvs...), nil)
+			require.NoError(b, err)
+
+			for {
+				_, err := itr.Next()
+
+				if err == io.EOF {
+					break
+				}
+
+				require.NoError(b, err)
+			}
+		}
+	})
+
+	b.Run("full row projection", func(b *testing.B) {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+		require.NoError(b, err)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+
+			for {
+				r, err := dmi.Next()
+
+				if err == io.EOF {
+					break
+				}
+
+				require.NoError(b, err)
+				_ = r[1]
+			}
+		}
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// ColumnVectorKind is the kind of the slice a ColumnVector stores its values in
+type ColumnVectorKind int
+
+const (
+	// NullColumnVector is the kind of a column for which only NULLs have been read
+	NullColumnVector ColumnVectorKind = iota
+	// IntColumnVector stores values of any signed integer type in Ints
+	IntColumnVector
+	// UintColumnVector stores values of any unsigned integer type in Uints
+	UintColumnVector
+	// FloatColumnVector stores float32 and float64 values in Floats
+	FloatColumnVector
+	// BoolColumnVector stores bool values in Bools
+	BoolColumnVector
+	// StringColumnVector stores string values in Strings
+	StringColumnVector
+	// TimeColumnVector stores time.Time values in Times
+	TimeColumnVector
+	// ValueColumnVector stores values of every other type, such as decimals, in Values
+	ValueColumnVector
+)
+
+// ColumnVector holds the values of a single column of a ColumnarBatch.  Only the slice for the vector's kind is used,
+// and it has an entry for every row of the batch.  Valid is false for the rows where the column is NULL, and the entry
+// for those rows in the slice of values is the zero value.
+type ColumnVector struct {
+	Tag   uint64
+	Name  string
+	Kind  ColumnVectorKind
+	Valid []bool
+
+	Ints    []int64
+	Uints   []uint64
+	Floats  []float64
+	Bools   []bool
+	Strings []string
+	Times   []time.Time
+	Values  []interface{}
+}
+
+// Len returns the number of rows in the vector
+func (cv *ColumnVector) Len() int {
+	return len(cv.Valid)
+}
+
+// Value returns the value of the column for row i, or nil if it is NULL.  Integers are returned as int64 or uint64 and
+// floats as float64 regardless of the type they were converted to.
+func (cv *ColumnVector) Value(i int) interface{} {
+	if !cv.Valid[i] {
+		return nil
+	}
+
+	switch cv.Kind {
+	case IntColumnVector:
+		return cv.Ints[i]
+	case UintColumnVector:
+		return cv.Uints[i]
+	case FloatColumnVector:
+		return cv.Floats[i]
+	case BoolColumnVector:
+		return cv.Bools[i]
+	case StringColumnVector:
+		return cv.Strings[i]
+	case TimeColumnVector:
+		return cv.Times[i]
+	default:
+		return cv.Values[i]
+	}
+}
+
+// columnVectorKindOf returns the kind of vector the value given is stored in
+func columnVectorKindOf(val interface{}) ColumnVectorKind {
+	switch val.(type) {
+	case int8, int16, int32, int64, int:
+		return IntColumnVector
+	case uint8, uint16, uint32, uint64, uint:
+		return UintColumnVector
+	case float32, float64:
+		return FloatColumnVector
+	case bool:
+		return BoolColumnVector
+	case string:
+		return StringColumnVector
+	case time.Time:
+		return TimeColumnVector
+	default:
+		return ValueColumnVector
+	}
+}
+
+// setKind sets the kind of a vector of NULLs, filling the slice for the kind with a zero value for each NULL
+func (cv *ColumnVector) setKind(kind ColumnVectorKind) {
+	cv.Kind = kind
+	n := len(cv.Valid)
+
+	switch kind {
+	case IntColumnVector:
+		cv.Ints = make([]int64, n)
+	case UintColumnVector:
+		cv.Uints = make([]uint64, n)
+	case FloatColumnVector:
+		cv.Floats = make([]float64, n)
+	case BoolColumnVector:
+		cv.Bools = make([]bool, n)
+	case StringColumnVector:
+		cv.Strings = make([]string, n)
+	case TimeColumnVector:
+		cv.Times = make([]time.Time, n)
+	default:
+		cv.Values = make([]interface{}, n)
+	}
+}
+
+// appendZero appends the zero value of the vector's kind
+func (cv *ColumnVector) appendZero() {
+	switch cv.Kind {
+	case IntColumnVector:
+		cv.Ints = append(cv.Ints, 0)
+	case UintColumnVector:
+		cv.Uints = append(cv.Uints, 0)
+	case FloatColumnVector:
+		cv.Floats = append(cv.Floats, 0)
+	case BoolColumnVector:
+		cv.Bools = append(cv.Bools, false)
+	case StringColumnVector:
+		cv.Strings = append(cv.Strings, "")
+	case TimeColumnVector:
+		cv.Times = append(cv.Times, time.Time{})
+	case ValueColumnVector:
+		cv.Values = append(cv.Values, nil)
+	}
+}
+
+// append appends a value, which must be NULL or of the vector's kind if it has one
+func (cv *ColumnVector) append(val interface{}) {
+	if val == nil {
+		cv.appendZero()
+		cv.Valid = append(cv.Valid, false)
+		return
+	}
+
+	if cv.Kind == NullColumnVector {
+		cv.setKind(columnVectorKindOf(val))
+	}
+
+	switch v := val.(type) {
+	case int8:
+		cv.Ints = append(cv.Ints, int64(v))
+	case int16:
+		cv.Ints = append(cv.Ints, int64(v))
+	case int32:
+		cv.Ints = append(cv.Ints, int64(v))
+	case int64:
+		cv.Ints = append(cv.Ints, v)
+	case int:
+		cv.Ints = append(cv.Ints, int64(v))
+	case uint8:
+		cv.Uints = append(cv.Uints, uint64(v))
+	case uint16:
+		cv.Uints = append(cv.Uints, uint64(v))
+	case uint32:
+		cv.Uints = append(cv.Uints, uint64(v))
+	case uint64:
+		cv.Uints = append(cv.Uints, v)
+	case uint:
+		cv.Uints = append(cv.Uints, uint64(v))
+	case float32:
+		cv.Floats = append(cv.Floats, float64(v))
+	case float64:
+		cv.Floats = append(cv.Floats, v)
+	case bool:
+		cv.Bools = append(cv.Bools, v)
+	case string:
+		cv.Strings = append(cv.Strings, v)
+	case time.Time:
+		cv.Times = append(cv.Times, v)
+	default:
+		cv.Values = append(cv.Values, v)
+	}
+
+	cv.Valid = append(cv.Valid, true)
+}
+
+// ColumnarBatch holds the rows produced by a KVToSqlRowConverter in column major order, with the values of each column
+// in a ColumnVector of its own, for operations which work a column at a time.  The kind of each vector is chosen from
+// the first non-NULL value read for the column, so it reflects options which change the types of converted values.
+// Every later value of the column must be stored in the same kind of vector.
+type ColumnarBatch struct {
+	// Columns holds the vector of each converted column in the order of the columns in the converted rows
+	Columns []*ColumnVector
+	byTag   map[uint64]*ColumnVector
+	rowIdx  []int
+	rowSize int
+	numRows int
+}
+
+// NewColumnarBatch returns an empty ColumnarBatch for the rows produced by conv
+func NewColumnarBatch(conv *KVToSqlRowConverter) *ColumnarBatch {
+	b := &ColumnarBatch{byTag: make(map[uint64]*ColumnVector, len(conv.tagToSqlColIdx)), rowSize: conv.rowSize}
+
+	for idx, meta := range conv.ColumnMetas() {
+		if meta.Name == "" {
+			continue
+		}
+
+		cv := &ColumnVector{Tag: meta.Tag, Name: meta.Name}
+		b.Columns = append(b.Columns, cv)
+		b.byTag[meta.Tag] = cv
+		b.rowIdx = append(b.rowIdx, idx)
+	}
+
+	return b
+}
+
+// Append adds a row produced by the batch's converter to the end of the batch.  If a value can't be stored in its
+// column's vector an error is returned and the batch is left unchanged.
+func (b *ColumnarBatch) Append(r sql.Row) error {
+	for i, cv := range b.Columns {
+		if val := b.rowVal(r, i); val != nil && cv.Kind != NullColumnVector && columnVectorKindOf(val) != cv.Kind {
+			return fmt.Errorf("row %d: column '%s' has a value of type %T which can't be stored with its earlier values", b.numRows, cv.Name, val)
+		}
+	}
+
+	for i, cv := range b.Columns {
+		cv.append(b.rowVal(r, i))
+	}
+
+	b.numRows++
+	return nil
+}
+
+// rowVal returns the value of the i'th column of the batch in the row given
+func (b *ColumnarBatch) rowVal(r sql.Row, i int) interface{} {
+	return SqlRowValue(r, b.rowIdx[i])
+}
+
+// NumRows returns the number of rows in the batch
+func (b *ColumnarBatch) NumRows() int {
+	return b.numRows
+}
+
+// Column returns the vector of the column with the tag given
+func (b *ColumnarBatch) Column(tag uint64) (*ColumnVector, bool) {
+	cv, ok := b.byTag[tag]
+	return cv, ok
+}
+
+// Row returns row i of the batch laid out as it was produced by the converter.  Values are returned as described by
+// ColumnVector.Value.
+func (b *ColumnarBatch) Row(i int) sql.Row {
+	r := make(sql.Row, b.rowSize)
+	for j, cv := range b.Columns {
+		r[b.rowIdx[j]] = cv.Value(i)
+	}
+
+	return r
+}
+
+// ReadColumnarBatch reads every row from itr, which must produce the rows of conv, into a ColumnarBatch.  itr is closed
+// once it has been read.
+func ReadColumnarBatch(ctx *sql.Context, itr sql.RowIter, conv *KVToSqlRowConverter) (b *ColumnarBatch, err error) {
+	defer func() {
+		closeErr := itr.Close(ctx)
+
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	b = NewColumnarBatch(conv)
+	for {
+		r, err := itr.Next()
+
+		if err == io.EOF {
+			return b, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		err = b.Append(r)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestColumnarBatch(t *testing.T) {
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var kvs []types.Tuple
+	for _, kv := range [][2]types.Tuple{
+		tuplePair(mapIterTestTuples(t, 1, types.String("bill"), nil, types.Float(2.5), types.Timestamp(created))),
+		tuplePair(mapIterTestTuples(t, 2, nil, nil, types.Float(-1), nil)),
+		tuplePair(mapIterTestTuples(t, 3, types.String("jo"), types.Uint(40), nil, types.Timestamp(created))),
+	} {
+		kvs = append(kvs, kv[0], kv[1])
+	}
+
+	dmi := NewDoltMapIter(context.Background(), kvGetFuncForTuples(kvs...), nil, conv)
+	b, err := ReadColumnarBatch(sql.NewEmptyContext(), dmi, conv)
+	require.NoError(t, err)
+	require.Equal(t, 3, b.NumRows())
+	require.Len(t, b.Columns, len(mapIterTestCols))
+
+	ids, ok := b.Column(mapIterPKTag)
+	require.True(t, ok)
+	assert.Equal(t, IntColumnVector, ids.Kind)
+	assert.Equal(t, []int64{1, 2, 3}, ids.Ints)
+	assert.Equal(t, []bool{true, true, true}, ids.Valid)
+
+	names, _ := b.Column(mapIterNameTag)
+	assert.Equal(t, StringColumnVector, names.Kind)
+	assert.Equal(t, []string{"bill", "", "jo"}, names.Strings)
+	assert.Equal(t, []bool{true, false, true}, names.Valid)
+
+	// the vector's kind is set by its first non-NULL value, and the earlier NULLs are filled in
+	ages, _ := b.Column(mapIterAgeTag)
+	assert.Equal(t, UintColumnVector, ages.Kind)
+	assert.Equal(t, []uint64{0, 0, 40}, ages.Uints)
+	assert.Equal(t, []bool{false, false, true}, ages.Valid)
+
+	scores, _ := b.Column(mapIterScoreTag)
+	assert.Equal(t, []float64{2.5, -1, 0}, scores.Floats)
+
+	times, _ := b.Column(mapIterCreatedTag)
+	assert.Equal(t, TimeColumnVector, times.Kind)
+	assert.Equal(t, 3, times.Len())
+
+	assert.Equal(t, sql.Row{int64(1), "bill", nil, 2.5, created}, b.Row(0))
+	assert.Equal(t, sql.Row{int64(2), nil, nil, -1.0, nil}, b.Row(1))
+	assert.Equal(t, sql.Row{int64(3), "jo", uint64(40), nil, created}, b.Row(2))
+
+	_, ok = b.Column(100)
+	assert.False(t, ok)
+
+	// a value of another type is rejected without changing the batch
+	err = b.Append(sql.Row{int64(4), int64(5), nil, nil, nil})
+	assert.Error(t, err)
+	assert.Equal(t, 3, b.NumRows())
+	assert.Equal(t, 3, ids.Len())
+
+	// a column of only NULLs has no values
+	b = NewColumnarBatch(conv)
+	require.NoError(t, b.Append(sql.Row{int64(1), nil, nil, nil, nil}))
+	names, _ = b.Column(mapIterNameTag)
+	assert.Equal(t, NullColumnVector, names.Kind)
+	assert.Equal(t, sql.Row{int64(1), nil, nil, nil, nil}, b.Row(0))
+}
+
+func tuplePair(k, v types.Tuple) [2]types.Tuple {
+	return [2]types.Tuple{k, v}
+}
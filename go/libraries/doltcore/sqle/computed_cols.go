@@ -0,0 +1,156 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// ComputedColumn describes a column of the converted rows, such as the column of a view, whose value is derived from
+// other values of the row rather than read from the key or value tuple
+type ComputedColumn struct {
+	// Idx is the position of the row the computed value is written to
+	Idx int
+	// DependsOn are the positions of the row Compute reads.  Computed columns are filled after the columns read from
+	// the tuples, and each after the computed columns it depends on.
+	DependsOn []int
+	// Compute returns the value of the column from the row being converted
+	Compute func(r sql.Row) (interface{}, error)
+}
+
+// WithComputedColumns adds computed columns to the rows of the converter.  Their values are computed once every column
+// being read from the key and value tuples has been filled, in an order where each computed column follows the computed
+// columns it depends on, so Compute can read the value of any position it declares a dependency on.  An error is
+// returned if a computed column's index or one of its dependencies is out of range for the converter's rows, if its
+// index is one a column is read to or another computed column is written to, or if the dependencies of the computed
+// columns are cyclic.  Rows filtered out by WithKeyPredicate are not computed.
+func WithComputedColumns(computed ...ComputedColumn) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		byIdx := make(map[int]ComputedColumn, len(conv.computed)+len(computed))
+		for _, cc := range conv.computed {
+			byIdx[cc.Idx] = cc
+		}
+
+		for _, cc := range computed {
+			if cc.Idx < 0 || cc.Idx >= conv.rowSize {
+				return fmt.Errorf("computed column index %d is out of range for a row of size %d", cc.Idx, conv.rowSize)
+			}
+
+			if cc.Compute == nil {
+				return fmt.Errorf("computed column at index %d has no Compute function", cc.Idx)
+			}
+
+			for tag, colIdx := range conv.tagToSqlColIdx {
+				if colIdx == cc.Idx {
+					return fmt.Errorf("computed column index %d already holds the column with tag %d", cc.Idx, tag)
+				}
+			}
+
+			if conv.keyBucket != nil && conv.keyBucket.idx == cc.Idx {
+				return fmt.Errorf("computed column index %d already holds the key bucket", cc.Idx)
+			}
+
+			if _, ok := byIdx[cc.Idx]; ok {
+				return fmt.Errorf("index %d has more than one computed column", cc.Idx)
+			}
+
+			for _, dep := range cc.DependsOn {
+				if dep < 0 || dep >= conv.rowSize {
+					return fmt.Errorf("computed column at index %d depends on index %d which is out of range for a row of size %d", cc.Idx, dep, conv.rowSize)
+				}
+			}
+
+			byIdx[cc.Idx] = cc
+		}
+
+		ordered, err := orderComputedColumns(append(conv.computed, computed...), byIdx)
+
+		if err != nil {
+			return err
+		}
+
+		conv.computed = ordered
+		return nil
+	}
+}
+
+// orderComputedColumns returns the computed columns given ordered so that each follows the computed columns it depends
+// on, keeping the order they were given in where dependencies allow, or an error if their dependencies are cyclic
+func orderComputedColumns(computed []ComputedColumn, byIdx map[int]ComputedColumn) ([]ComputedColumn, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[int]int, len(computed))
+	ordered := make([]ComputedColumn, 0, len(computed))
+
+	var visit func(cc ComputedColumn) error
+	visit = func(cc ComputedColumn) error {
+		switch state[cc.Idx] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("computed column at index %d depends on itself through its dependencies", cc.Idx)
+		}
+
+		state[cc.Idx] = visiting
+		for _, dep := range cc.DependsOn {
+			if depCC, ok := byIdx[dep]; ok {
+				err := visit(depCC)
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		state[cc.Idx] = visited
+		ordered = append(ordered, cc)
+		return nil
+	}
+
+	for _, cc := range computed {
+		err := visit(cc)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// fillComputedColumns writes the value of each computed column to cols
+func (conv *KVToSqlRowConverter) fillComputedColumns(cols []interface{}, size *int64) error {
+	for _, cc := range conv.computed {
+		val, err := cc.Compute(cols)
+
+		if err != nil {
+			return fmt.Errorf("failed to compute the column at index %d: %w", cc.Idx, err)
+		}
+
+		cols[cc.Idx] = val
+
+		if size != nil {
+			*size += estimateValSize(val)
+		}
+	}
+
+	return nil
+}
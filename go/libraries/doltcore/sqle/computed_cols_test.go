@@ -0,0 +1,107 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWithComputedColumns(t *testing.T) {
+	ctx := context.Background()
+	tagToSqlColIdx := map[uint64]int{0: 0, 1: 1, 2: 2}
+	cols := append([]schema.Column{}, mergeTestCols...)
+
+	// label concatenates b and a, and shout depends on label, but is given first
+	label := ComputedColumn{
+		Idx:       4,
+		DependsOn: []int{1, 2},
+		Compute: func(r sql.Row) (interface{}, error) {
+			if r[1] == nil || r[2] == nil {
+				return nil, nil
+			}
+
+			return fmt.Sprintf("%s:%d", r[2], r[1]), nil
+		},
+	}
+	shout := ComputedColumn{
+		Idx:       3,
+		DependsOn: []int{4},
+		Compute: func(r sql.Row) (interface{}, error) {
+			if r[4] == nil {
+				return nil, nil
+			}
+
+			return strings.ToUpper(r[4].(string)) + "!", nil
+		},
+	}
+
+	conv, err := NewKVToSqlRowConverter(types.Format_Default, tagToSqlColIdx, cols, 5, WithComputedColumns(shout, label))
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t,
+		[]interface{}{1, 10, "ten"},
+		[]interface{}{2, nil, "none"},
+		[]interface{}{3, 30, "thirty"},
+	)
+
+	rows := drainRowIter(t, NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv))
+	assert.Equal(t, []sql.Row{
+		{int64(1), int64(10), "ten", "TEN:10!", "ten:10"},
+		{int64(2), nil, "none", nil, nil},
+		{int64(3), int64(30), "thirty", "THIRTY:30!", "thirty:30"},
+	}, rows)
+
+	t.Run("errors", func(t *testing.T) {
+		compute := func(r sql.Row) (interface{}, error) { return nil, nil }
+		invalid := map[string][]ComputedColumn{
+			"out of range":            {{Idx: 5, Compute: compute}},
+			"dependency out of range": {{Idx: 3, DependsOn: []int{-1}, Compute: compute}},
+			"base column index":       {{Idx: 2, Compute: compute}},
+			"duplicate index":         {{Idx: 3, Compute: compute}, {Idx: 3, Compute: compute}},
+			"no compute function":     {{Idx: 3}},
+			"self dependency":         {{Idx: 3, DependsOn: []int{3}, Compute: compute}},
+			"cycle": {
+				{Idx: 3, DependsOn: []int{4}, Compute: compute},
+				{Idx: 4, DependsOn: []int{0, 3}, Compute: compute},
+			},
+		}
+
+		for name, computed := range invalid {
+			_, err := NewKVToSqlRowConverter(types.Format_Default, tagToSqlColIdx, cols, 5, WithComputedColumns(computed...))
+			assert.Error(t, err, name)
+		}
+
+		errCompute := errors.New("compute failed")
+		conv, err := NewKVToSqlRowConverter(types.Format_Default, tagToSqlColIdx, cols, 5, WithComputedColumns(ComputedColumn{
+			Idx:     3,
+			Compute: func(r sql.Row) (interface{}, error) { return nil, errCompute },
+		}))
+		require.NoError(t, err)
+
+		_, err = conv.ConvertKVTuplesToSqlRow(kvs[0], kvs[1])
+		assert.True(t, errors.Is(err, errCompute))
+	})
+}
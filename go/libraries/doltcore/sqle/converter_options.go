@@ -0,0 +1,788 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// KVToSqlRowConverterOption configures optional behavior of a KVToSqlRowConverter.  Options are applied once the
+// converter has been validated and may return an error if they are misconfigured.
+type KVToSqlRowConverterOption func(conv *KVToSqlRowConverter) error
+
+// valTransform modifies a value after it has been read from a tuple
+type valTransform func(val interface{}) (interface{}, error)
+
+// addValTransform registers a transform for the column with the given tag.  When a column already has a transform the
+// new transform is applied to the result of the existing one.
+func (conv *KVToSqlRowConverter) addValTransform(tag uint64, transform valTransform) {
+	if conv.valTransforms == nil {
+		conv.valTransforms = make(map[uint64]valTransform)
+	}
+
+	if prev, ok := conv.valTransforms[tag]; ok {
+		conv.valTransforms[tag] = func(val interface{}) (interface{}, error) {
+			val, err := prev(val)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return transform(val)
+		}
+	} else {
+		conv.valTransforms[tag] = transform
+	}
+}
+
+// colForTag returns the column being converted for the given tag
+func (conv *KVToSqlRowConverter) colForTag(tag uint64) (schema.Column, bool) {
+	idx, ok := conv.tagToSqlColIdx[tag]
+
+	if !ok || idx >= len(conv.cols) {
+		return schema.Column{}, false
+	}
+
+	return conv.cols[idx], true
+}
+
+// WithValTupleEncoding overrides the ValTupleEncoding determined from the converter's format.  Use
+// UnsortedTagsValTupleEncoding for value tuples whose tags may not be in ascending order.  An error is returned for an
+// unrecognized encoding.
+func WithValTupleEncoding(enc ValTupleEncoding) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		err := validateValTupleEncoding(enc)
+
+		if err != nil {
+			return err
+		}
+
+		conv.valEncoding = enc
+		return nil
+	}
+}
+
+// ErrValTupleTagsOutOfOrder is returned by a converter created with WithValTupleTagOrderCheck when a value tuple's tags
+// are not strictly increasing
+var ErrValTupleTagsOutOfOrder = errors.New("value tuple tags are not strictly increasing")
+
+// WithValTupleTagOrderCheck causes the converter to verify that the tags of every value tuple are strictly increasing,
+// which the early exit taken when reading value tuples relies on.  A corrupt tuple, or one written by a foreign
+// producer, with out of order tags would otherwise have the values after the early exit silently dropped.  An error
+// wrapping ErrValTupleTagsOutOfOrder identifying the first out of order tag is returned.  Checking requires reading
+// whole value tuples, so it is off by default.  It has no effect with UnsortedTagsValTupleEncoding.
+func WithValTupleTagOrderCheck() KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		conv.checkTagOrder = true
+		return nil
+	}
+}
+
+// ErrKVSwapped is returned by a converter created with WithSwappedKVCheck when the key and value tuples it is given
+// appear to have been passed in each other's place
+var ErrKVSwapped = errors.New("key and value appear swapped")
+
+// WithSwappedKVCheck causes the converter to verify that the key and value tuples of each row have not been passed in
+// each other's place, which would otherwise convert to a row of garbage.  The tuples are taken to be swapped when the
+// first tag of the key tuple is not a primary key tag of sch while the first tag of the value tuple is, and an error
+// wrapping ErrKVSwapped is returned.  Only the first field of each tuple is read, and rows with an empty key or value
+// tuple are not checked.
+func WithSwappedKVCheck(sch schema.Schema) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		pkTags := make(map[uint64]struct{}, sch.GetPKCols().Size()+1)
+		_ = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+			pkTags[tag] = struct{}{}
+			return false, nil
+		})
+
+		if schema.IsKeyless(sch) {
+			pkTags[schema.KeylessRowIdTag] = struct{}{}
+		}
+
+		conv.swapCheckPKTags = pkTags
+		return nil
+	}
+}
+
+// checkSwapped returns an error wrapping ErrKVSwapped if k and v appear swapped
+func (conv *KVToSqlRowConverter) checkSwapped(k, v types.Tuple) error {
+	keyTagIsPK, ok, err := conv.firstTagIsPK(k)
+
+	if err != nil || !ok || keyTagIsPK {
+		return err
+	}
+
+	valTagIsPK, ok, err := conv.firstTagIsPK(v)
+
+	if err != nil || !ok || !valTagIsPK {
+		return err
+	}
+
+	return fmt.Errorf("%w: the key tuple starts with a value tag and the value tuple with a primary key tag", ErrKVSwapped)
+}
+
+// firstTagIsPK returns whether the first tag of tup is a primary key tag for WithSwappedKVCheck, and false for ok when
+// tup is empty
+func (conv *KVToSqlRowConverter) firstTagIsPK(tup types.Tuple) (isPK bool, ok bool, err error) {
+	if tup.Empty() {
+		return false, false, nil
+	}
+
+	first, err := tup.Get(0)
+
+	if err != nil {
+		return false, false, err
+	}
+
+	tag, isUint := first.(types.Uint)
+
+	if !isUint {
+		return false, false, errors.New("Encountered unexpected kind while attempting to read tag")
+	}
+
+	_, isPK = conv.swapCheckPKTags[uint64(tag)]
+	return isPK, true, nil
+}
+
+// ErrRowTooLarge is returned by a converter created with WithMaxRowSize for rows whose estimated size exceeds the
+// maximum
+var ErrRowTooLarge = errors.New("row exceeds the maximum row size")
+
+// maxRowSizeCulprits is the number of the largest columns named in errors wrapping ErrRowTooLarge
+const maxRowSizeCulprits = 3
+
+// WithMaxRowSize causes the converter to return an error wrapping ErrRowTooLarge for rows whose size, as estimated by
+// EstimateSqlRowSize, is more than maxBytes, for callers sending rows over protocols which limit the size of a message.
+// The error names the columns which contributed the most to the row's size.  Sizes are estimated from the converted
+// values, so a row is only rejected once it has been converted.  It is off by default.
+func WithMaxRowSize(maxBytes int64) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		if maxBytes < 1 {
+			return fmt.Errorf("maximum row size must be at least 1 byte but was %d", maxBytes)
+		}
+
+		conv.maxRowSize = maxBytes
+		return nil
+	}
+}
+
+// checkRowSize returns an error wrapping ErrRowTooLarge if the estimated size of the converted values in cols is more
+// than the converter's maximum row size
+func (conv *KVToSqlRowConverter) checkRowSize(cols []interface{}) error {
+	type colSize struct {
+		idx  int
+		size int64
+	}
+
+	var total int64
+	sizes := make([]colSize, 0, len(cols))
+	for idx, val := range cols {
+		size := estimateValSize(val)
+		total += size
+
+		if size > 0 {
+			sizes = append(sizes, colSize{idx: idx, size: size})
+		}
+	}
+
+	if total <= conv.maxRowSize {
+		return nil
+	}
+
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return sizes[i].size > sizes[j].size
+	})
+
+	if len(sizes) > maxRowSizeCulprits {
+		sizes = sizes[:maxRowSizeCulprits]
+	}
+
+	idxToTag := make(map[int]uint64, len(conv.tagToSqlColIdx))
+	for tag, idx := range conv.tagToSqlColIdx {
+		idxToTag[idx] = tag
+	}
+
+	culprits := make([]string, len(sizes))
+	for i, cs := range sizes {
+		if _, ok := idxToTag[cs.idx]; ok {
+			culprits[i] = fmt.Sprintf("%s (%d bytes)", conv.cols[cs.idx].Name, cs.size)
+		} else {
+			culprits[i] = fmt.Sprintf("index %d (%d bytes)", cs.idx, cs.size)
+		}
+	}
+
+	return fmt.Errorf("%w: the row's estimated size of %d bytes is more than the maximum of %d bytes, largest columns: %s", ErrRowTooLarge, total, conv.maxRowSize, strings.Join(culprits, ", "))
+}
+
+// ConversionTimingHook receives the tag of a column and the time taken to convert one of its values
+type ConversionTimingHook func(tag uint64, elapsed time.Duration)
+
+// WithConversionTimingHook causes the converter to time one out of every sampleEvery value conversions and report the
+// tag of the column converted along with the elapsed time to hook.  The hook is called synchronously during
+// conversion so it should be cheap, for example recording to a histogram which is aggregated elsewhere.  When no hook
+// is set conversions are not timed.
+func WithConversionTimingHook(hook ConversionTimingHook, sampleEvery uint64) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		if sampleEvery == 0 {
+			return errors.New("conversion timing sample rate must be greater than 0")
+		}
+
+		conv.timingHook = hook
+		conv.timingSampleEvery = sampleEvery
+		return nil
+	}
+}
+
+// timedReadFrom reads a value for the column given, timing the read if it is one of the sampled conversions
+func (conv *KVToSqlRowConverter) timedReadFrom(tag uint64, col schema.Column, nbf *types.NomsBinFormat, reader types.CodecReader) (interface{}, error) {
+	if (atomic.AddUint64(&conv.numConversions, 1)-1)%conv.timingSampleEvery != 0 {
+		return col.TypeInfo.ReadFrom(nbf, reader)
+	}
+
+	start := time.Now()
+	val, err := col.TypeInfo.ReadFrom(nbf, reader)
+	conv.timingHook(tag, time.Since(start))
+
+	return val, err
+}
+
+// StringNormalizer rewrites a string value read from a string column
+type StringNormalizer func(string) string
+
+// NormalizeNFC is a StringNormalizer that converts strings to Unicode Normalization Form C
+func NormalizeNFC(str string) string {
+	return norm.NFC.String(str)
+}
+
+// CaseFold is a StringNormalizer that folds the case of strings for case-insensitive comparison and display
+func CaseFold(str string) string {
+	return cases.Fold().String(str)
+}
+
+// WithStringNormalizers applies the StringNormalizer for each tag to the values of that column as they are read.
+// Only columns with a string type are normalized.  Normalizers for tags that are not string columns, or that are not
+// being converted, are ignored.  Because values are normalized before they leave the converter, anything measuring
+// the converted rows, such as the fwt width sampler, sees the normalized values.
+func WithStringNormalizers(normalizers map[uint64]StringNormalizer) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		for tag, normalizer := range normalizers {
+			col, ok := conv.colForTag(tag)
+
+			if !ok || col.TypeInfo.GetTypeIdentifier() != typeinfo.VarStringTypeIdentifier {
+				continue
+			}
+
+			normalizer := normalizer
+			conv.addValTransform(tag, func(val interface{}) (interface{}, error) {
+				if str, ok := val.(string); ok {
+					return normalizer(str), nil
+				}
+
+				return val, nil
+			})
+		}
+
+		return nil
+	}
+}
+
+// WithTimeZone causes the values of datetime and timestamp columns, which are stored in UTC, to be emitted as times in
+// loc, such as the session's time zone, so that they are displayed and exported consistently.  Date columns are not
+// affected as they have no time of day.  NULLs and zero times are emitted unchanged.
+func WithTimeZone(loc *time.Location) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		if loc == nil {
+			return errors.New("time zone location cannot be nil")
+		}
+
+		for tag := range conv.tagToSqlColIdx {
+			col, ok := conv.colForTag(tag)
+
+			if !ok || col.TypeInfo.GetTypeIdentifier() != typeinfo.DatetimeTypeIdentifier || col.TypeInfo.Equals(typeinfo.DateType) {
+				continue
+			}
+
+			conv.addValTransform(tag, func(val interface{}) (interface{}, error) {
+				if t, ok := val.(time.Time); ok && !t.IsZero() {
+					return t.In(loc), nil
+				}
+
+				return val, nil
+			})
+		}
+
+		return nil
+	}
+}
+
+// OrdinalFallback returns the value emitted for an enum or set column whose stored ordinal does not correspond to any
+// of the column's declared members.  For set columns the ordinal is the bit field of the members in the set.
+type OrdinalFallback func(col schema.Column, ordinal uint64) (interface{}, error)
+
+// WithEnumSetMemberNames causes enum and set columns to be emitted as the names of their declared members.  Enums are
+// stored as 1-based ordinals, with 0 being the empty string, and sets as a bit field of members which is emitted as
+// a comma separated list.  Ordinals which do not match the declared members are passed to fallback, or produce an
+// error naming the column when fallback is nil.
+func WithEnumSetMemberNames(fallback OrdinalFallback) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		for tag, idx := range conv.tagToSqlColIdx {
+			col := conv.cols[idx]
+
+			var unmarshal func(ordinal uint64) (string, error)
+			switch sqlType := col.TypeInfo.ToSqlType().(type) {
+			case sql.EnumType:
+				unmarshal = func(ordinal uint64) (string, error) {
+					if ordinal == 0 {
+						return "", nil
+					}
+
+					return sqlType.Unmarshal(int64(ordinal))
+				}
+			case sql.SetType:
+				unmarshal = sqlType.Unmarshal
+			default:
+				continue
+			}
+
+			if conv.valReaders == nil {
+				conv.valReaders = make(map[uint64]valReader)
+			}
+
+			conv.valReaders[tag] = memberNameReader(col, unmarshal, fallback)
+		}
+
+		return nil
+	}
+}
+
+func memberNameReader(col schema.Column, unmarshal func(uint64) (string, error), fallback OrdinalFallback) valReader {
+	return func(_ *types.NomsBinFormat, reader types.CodecReader) (interface{}, error) {
+		switch k := reader.ReadKind(); k {
+		case types.UintKind:
+			ordinal := reader.ReadUint()
+			name, err := unmarshal(ordinal)
+
+			if err == nil {
+				return name, nil
+			}
+
+			if fallback == nil {
+				return nil, fmt.Errorf("column '%s' has ordinal %d which is not a declared member of %s", col.Name, ordinal, col.TypeInfo.String())
+			}
+
+			return fallback(col, ordinal)
+		case types.NullKind:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("column '%s' cannot convert NomsKind %v to a member name", col.Name, k)
+		}
+	}
+}
+
+// WithRawInts causes the columns with the tags given to be emitted as the integers backing their stored values rather
+// than as SQL values, for uses such as checksums and bucketing which don't need the values materialized.  Integer,
+// enum, set, and bit columns are emitted as their stored int64 or uint64, so enums give their 1-based ordinal and sets
+// their bit field.  Boolean columns are emitted as an int64 of 0 or 1, and date, datetime, and timestamp columns as the
+// int64 number of microseconds since the Unix epoch.  An error is returned if a column has no integer backing.  Tags
+// that are not being converted are ignored.
+func WithRawInts(tags ...uint64) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		for _, tag := range tags {
+			col, ok := conv.colForTag(tag)
+
+			if !ok {
+				continue
+			}
+
+			switch col.TypeInfo.NomsKind() {
+			case types.IntKind, types.UintKind, types.BoolKind, types.TimestampKind:
+			default:
+				return fmt.Errorf("column '%s' of type %s is not stored as an integer", col.Name, col.TypeInfo.String())
+			}
+
+			if conv.valReaders == nil {
+				conv.valReaders = make(map[uint64]valReader)
+			}
+
+			conv.valReaders[tag] = rawIntReader(col)
+		}
+
+		return nil
+	}
+}
+
+func rawIntReader(col schema.Column) valReader {
+	return func(_ *types.NomsBinFormat, reader types.CodecReader) (interface{}, error) {
+		switch k := reader.ReadKind(); k {
+		case types.IntKind:
+			return reader.ReadInt(), nil
+		case types.UintKind:
+			return reader.ReadUint(), nil
+		case types.BoolKind:
+			if reader.ReadBool() {
+				return int64(1), nil
+			}
+
+			return int64(0), nil
+		case types.TimestampKind:
+			t, err := reader.ReadTimestamp()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return t.Unix()*1000000 + int64(t.Nanosecond()/1000), nil
+		case types.NullKind:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("column '%s' cannot convert NomsKind %v to a raw integer", col.Name, k)
+		}
+	}
+}
+
+// TimeRepresentation is the Go representation of the values of a TIME column
+type TimeRepresentation int
+
+const (
+	// TimeAsString represents a TIME as a string such as "-12:30:00" or "100:00:00.5", as it is emitted by default
+	TimeAsString TimeRepresentation = iota
+	// TimeAsDuration represents a TIME as a time.Duration
+	TimeAsDuration
+	// TimeAsSeconds represents a TIME as the float64 number of seconds it spans, including fractional seconds
+	TimeAsSeconds
+)
+
+// TimeOverflow determines what is emitted for TIME values which are 24 hours or longer, or are negative
+type TimeOverflow int
+
+const (
+	// TimeOverflowAllow emits TIME values of any length unchanged, so strings may have more than 23 hours
+	TimeOverflowAllow TimeOverflow = iota
+	// TimeOverflowWrap emits TIME values as a time of day, wrapping them into the range [00:00:00, 24:00:00).  A value
+	// of 25 hours is emitted as 1 hour and a value of -1 hour as 23 hours.
+	TimeOverflowWrap
+	// TimeOverflowError returns an error naming the column for TIME values whose magnitude is 24 hours or longer
+	TimeOverflowError
+)
+
+const microsecondsPerDay = int64(24 * time.Hour / time.Microsecond)
+
+// WithTimeRepresentations causes the values of each of the TIME columns in reps to be emitted using the representation
+// given for its tag rather than as a string.  TIME values may span up to 838 hours in either direction, and overflow
+// determines how values outside of a single day are handled.  Tags which are not TIME columns, or that are not being
+// converted, are ignored.
+func WithTimeRepresentations(reps map[uint64]TimeRepresentation, overflow TimeOverflow) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		if overflow < TimeOverflowAllow || overflow > TimeOverflowError {
+			return fmt.Errorf("unknown TIME overflow handling %d", int(overflow))
+		}
+
+		for tag, rep := range reps {
+			if rep < TimeAsString || rep > TimeAsSeconds {
+				return fmt.Errorf("unknown TIME representation %d", int(rep))
+			}
+
+			col, ok := conv.colForTag(tag)
+
+			if !ok || col.TypeInfo.GetTypeIdentifier() != typeinfo.TimeTypeIdentifier {
+				continue
+			}
+
+			if conv.valReaders == nil {
+				conv.valReaders = make(map[uint64]valReader)
+			}
+
+			conv.valReaders[tag] = timeReader(col, rep, overflow)
+		}
+
+		return nil
+	}
+}
+
+func timeReader(col schema.Column, rep TimeRepresentation, overflow TimeOverflow) valReader {
+	return func(_ *types.NomsBinFormat, reader types.CodecReader) (interface{}, error) {
+		switch k := reader.ReadKind(); k {
+		case types.IntKind:
+			// TIME values are stored as microseconds
+			micros := reader.ReadInt()
+
+			switch overflow {
+			case TimeOverflowWrap:
+				micros = ((micros % microsecondsPerDay) + microsecondsPerDay) % microsecondsPerDay
+			case TimeOverflowError:
+				if micros >= microsecondsPerDay || micros <= -microsecondsPerDay {
+					return nil, fmt.Errorf("column '%s' has TIME value %s which is 24 hours or longer", col.Name, sql.Time.Unmarshal(micros))
+				}
+			}
+
+			switch rep {
+			case TimeAsDuration:
+				return time.Duration(micros) * time.Microsecond, nil
+			case TimeAsSeconds:
+				return float64(micros) / float64(time.Second/time.Microsecond), nil
+			default:
+				return sql.Time.Unmarshal(micros), nil
+			}
+		case types.NullKind:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("column '%s' cannot convert NomsKind %v to a TIME", col.Name, k)
+		}
+	}
+}
+
+// ValTupleDriftStats describes the values found in value tuples whose tags are not columns of the schema
+type ValTupleDriftStats struct {
+	// NumUnknownVals is the number of values read with a tag that is not in the schema
+	NumUnknownVals uint64
+	// UnknownTags holds each tag that is not in the schema in ascending order
+	UnknownTags []uint64
+}
+
+type valTupleDrift struct {
+	knownTags   map[uint64]struct{}
+	mu          sync.Mutex
+	numUnknown  uint64
+	unknownTags map[uint64]struct{}
+}
+
+// check records tag if it is not a known tag
+func (drift *valTupleDrift) check(tag uint64) {
+	if _, ok := drift.knownTags[tag]; ok {
+		return
+	}
+
+	drift.mu.Lock()
+	defer drift.mu.Unlock()
+
+	drift.numUnknown++
+	drift.unknownTags[tag] = struct{}{}
+}
+
+// WithValTupleDriftCheck causes the converter to read every value tuple to its end and record each value whose tag is
+// not a column of sch, which indicates that the data was written with a schema the converter does not know about.
+// Unknown values are skipped as usual and are reported by ValTupleDrift.  Reading whole tuples gives up the early
+// exit the converter otherwise takes once the values it needs are read.
+func WithValTupleDriftCheck(sch schema.Schema) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		knownTags := make(map[uint64]struct{}, sch.GetAllCols().Size()+2)
+		_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+			knownTags[tag] = struct{}{}
+			return false, nil
+		})
+
+		if schema.IsKeyless(sch) {
+			knownTags[schema.KeylessRowCardinalityTag] = struct{}{}
+		}
+
+		conv.drift = &valTupleDrift{
+			knownTags:   knownTags,
+			unknownTags: make(map[uint64]struct{}),
+		}
+
+		return nil
+	}
+}
+
+// WithTrailingNullsTrimmed causes the NULLs at the end of each converted row to be removed, so rows are returned with
+// fewer columns than the converter's row size whenever their last columns are NULL.  Leading and interior NULLs are
+// kept so every value remains at its column's index.  This saves space when exporting sparse wide tables, but callers
+// must be prepared to treat a missing column as NULL, which SqlRowValue does.
+func WithTrailingNullsTrimmed() KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		conv.trimTrailingNulls = true
+		return nil
+	}
+}
+
+// SqlRowValue returns the value at the index idx of a row produced by a KVToSqlRowConverter, which is NULL for indexes
+// past the end of rows shortened by WithTrailingNullsTrimmed.  Consumers of converted rows should read values with it
+// rather than indexing rows directly.
+func SqlRowValue(r sql.Row, idx int) interface{} {
+	if idx < len(r) {
+		return r[idx]
+	}
+
+	return nil
+}
+
+// WithMaskedColumns causes the converter to emit a fixed value, such as "***", in place of the value of each column
+// whose tag is a key of masks, for exports which must redact sensitive columns.  Masked values are skipped without
+// being decoded, so no reader or transform registered for the column is applied.  Key and value columns may both be
+// masked, and NULLs are left as NULL.  Tags that are not being converted are ignored.
+func WithMaskedColumns(masks map[uint64]interface{}) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		for tag, mask := range masks {
+			if _, ok := conv.colForTag(tag); !ok {
+				continue
+			}
+
+			if conv.masks == nil {
+				conv.masks = make(map[uint64]interface{})
+			}
+
+			conv.masks[tag] = mask
+		}
+
+		return nil
+	}
+}
+
+// WithMissingValuesAsZero causes the converter to emit the zero value of a column's SQL type, such as "" for strings,
+// 0 for integers, and false for booleans, when the column is missing from its tuple, for consumers which can't handle
+// nil.  Dolt leaves NULL values out of value tuples, so this applies to NULLs stored that way, while NULLs written into
+// a tuple explicitly are still emitted as nil.  The option applies to the columns with the tags given, or to every
+// column being converted when no tags are given.  Tags that are not being converted are ignored.
+func WithMissingValuesAsZero(tags ...uint64) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		zeroTags := tags
+		if len(zeroTags) == 0 {
+			for tag := range conv.tagToSqlColIdx {
+				zeroTags = append(zeroTags, tag)
+			}
+		}
+
+		for _, tag := range zeroTags {
+			col, ok := conv.colForTag(tag)
+
+			if !ok {
+				continue
+			}
+
+			if conv.missingZeros == nil {
+				conv.missingZeros = make(map[int]interface{})
+			}
+
+			conv.missingZeros[conv.tagToSqlColIdx[tag]] = col.TypeInfo.ToSqlType().Zero()
+		}
+
+		return nil
+	}
+}
+
+// BinaryEncoding is the text encoding the values of binary columns are emitted with
+type BinaryEncoding int
+
+const (
+	// RawBinaryEncoding emits the bytes of binary values unchanged
+	RawBinaryEncoding BinaryEncoding = iota
+	// HexBinaryEncoding emits binary values as upper case hexadecimal, as MySQL's HEX function does
+	HexBinaryEncoding
+	// Base64BinaryEncoding emits binary values as standard, padded base64
+	Base64BinaryEncoding
+)
+
+// isBinaryCol returns true if the values of the column given are stored as bytes
+func isBinaryCol(col schema.Column) bool {
+	switch col.TypeInfo.GetTypeIdentifier() {
+	case typeinfo.VarBinaryTypeIdentifier, typeinfo.InlineBlobTypeIdentifier:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithBinaryEncoding causes the values of binary, varbinary, and blob columns to be emitted as strings in the encoding
+// given rather than as raw bytes, which render poorly as text.  The encoding applies to the columns with the tags
+// given, or to every binary column being converted when no tags are given.  An error is returned if a tag given is
+// not a binary column, while tags that are not being converted are ignored.  Because values are encoded before they
+// leave the converter, the fwt width sampler and RenderFixedWidth measure the encoded values they print.  NULLs are
+// emitted unchanged, as are values read as *LazyBlob by WithLazyBlobs.
+func WithBinaryEncoding(enc BinaryEncoding, tags ...uint64) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		var encode func([]byte) string
+		switch enc {
+		case RawBinaryEncoding:
+			return nil
+		case HexBinaryEncoding:
+			encode = func(b []byte) string {
+				return strings.ToUpper(hex.EncodeToString(b))
+			}
+		case Base64BinaryEncoding:
+			encode = base64.StdEncoding.EncodeToString
+		default:
+			return fmt.Errorf("unknown binary encoding %d", int(enc))
+		}
+
+		binTags := tags
+		if len(binTags) == 0 {
+			for tag, idx := range conv.tagToSqlColIdx {
+				if isBinaryCol(conv.cols[idx]) {
+					binTags = append(binTags, tag)
+				}
+			}
+		}
+
+		for _, tag := range binTags {
+			col, ok := conv.colForTag(tag)
+
+			if !ok {
+				continue
+			}
+
+			if !isBinaryCol(col) {
+				return fmt.Errorf("column '%s' of type %s is not a binary column", col.Name, col.TypeInfo.String())
+			}
+
+			conv.addValTransform(tag, func(val interface{}) (interface{}, error) {
+				if str, ok := val.(string); ok {
+					return encode([]byte(str)), nil
+				}
+
+				return val, nil
+			})
+		}
+
+		return nil
+	}
+}
+
+// ValTupleDrift returns the unknown values found so far by a converter created with WithValTupleDriftCheck.  false is
+// returned if the check is not enabled.
+func (conv *KVToSqlRowConverter) ValTupleDrift() (ValTupleDriftStats, bool) {
+	if conv.drift == nil {
+		return ValTupleDriftStats{}, false
+	}
+
+	conv.drift.mu.Lock()
+	defer conv.drift.mu.Unlock()
+
+	stats := ValTupleDriftStats{NumUnknownVals: conv.drift.numUnknown}
+	for tag := range conv.drift.unknownTags {
+		stats.UnknownTags = append(stats.UnknownTags, tag)
+	}
+
+	sort.Slice(stats.UnknownTags, func(i, j int) bool {
+		return stats.UnknownTags[i] < stats.UnknownTags[j]
+	})
+
+	return stats, true
+}
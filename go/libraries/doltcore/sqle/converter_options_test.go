@@ -0,0 +1,812 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWithStringNormalizers(t *testing.T) {
+	tests := []struct {
+		name         string
+		normalizers  map[uint64]StringNormalizer
+		input        string
+		expectedName string
+	}{
+		{
+			name:         "none",
+			input:        "e\u0301",
+			expectedName: "e\u0301",
+		},
+		{
+			name:         "nfc",
+			normalizers:  map[uint64]StringNormalizer{mapIterNameTag: NormalizeNFC},
+			input:        "e\u0301",
+			expectedName: "\u00e9",
+		},
+		{
+			name:         "case fold",
+			normalizers:  map[uint64]StringNormalizer{mapIterNameTag: CaseFold},
+			input:        "HeLLo Straße",
+			expectedName: "hello strasse",
+		},
+		{
+			name:         "non string column ignored",
+			normalizers:  map[uint64]StringNormalizer{mapIterPKTag: CaseFold, mapIterAgeTag: CaseFold},
+			input:        "ABC",
+			expectedName: "ABC",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithStringNormalizers(test.normalizers))
+			require.NoError(t, err)
+			k, v := mapIterTestTuples(t, 1, types.String(test.input), types.Uint(7))
+			r, size, err := conv.ConvertKVToSqlRowWithSize(k, v)
+			require.NoError(t, err)
+			assert.Equal(t, sql.Row{int64(1), test.expectedName, uint64(7), nil, nil}, r)
+			assert.Equal(t, int64(8+len(test.expectedName)+8), size)
+		})
+	}
+}
+
+func TestWithTimeZone(t *testing.T) {
+	const (
+		pkTag = iota
+		dateTag
+		datetimeTag
+		timestampTag
+	)
+
+	dateCol, err := schema.NewColumnWithTypeInfo("d", dateTag, typeinfo.DateType, false, "", false, "")
+	require.NoError(t, err)
+	datetimeCol, err := schema.NewColumnWithTypeInfo("dt", datetimeTag, typeinfo.DatetimeType, false, "", false, "")
+	require.NoError(t, err)
+	timestampCol, err := schema.NewColumnWithTypeInfo("ts", timestampTag, typeinfo.TimestampType, false, "", false, "")
+	require.NoError(t, err)
+	cols := []schema.Column{schema.NewColumn("id", pkTag, types.IntKind, true), dateCol, datetimeCol, timestampCol}
+
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithTimeZone(nil))
+	assert.Error(t, err)
+
+	stored := time.Date(2021, 3, 14, 23, 30, 0, 0, time.UTC)
+	day := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	tuples := func(dt, ts types.Value) (types.Tuple, types.Tuple) {
+		k, err := types.NewTuple(types.Format_Default, types.Uint(pkTag), types.Int(1))
+		require.NoError(t, err)
+
+		vals := []types.Value{types.Uint(dateTag), types.Timestamp(day)}
+		if dt != nil {
+			vals = append(vals, types.Uint(datetimeTag), dt)
+		}
+		if ts != nil {
+			vals = append(vals, types.Uint(timestampTag), ts)
+		}
+
+		v, err := types.NewTuple(types.Format_Default, vals...)
+		require.NoError(t, err)
+		return k, v
+	}
+
+	for _, zone := range []string{"UTC", "America/New_York", "Asia/Kolkata", "Pacific/Chatham"} {
+		t.Run(zone, func(t *testing.T) {
+			loc, err := time.LoadLocation(zone)
+			require.NoError(t, err)
+			conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithTimeZone(loc))
+			require.NoError(t, err)
+
+			k, v := tuples(types.Timestamp(stored), types.Timestamp(stored))
+			r, err := conv.ConvertKVToSqlRow(k, v)
+			require.NoError(t, err)
+
+			assert.Equal(t, day, r[1], "date columns should not be converted")
+			for _, val := range r[2:] {
+				converted := val.(time.Time)
+				assert.Equal(t, loc, converted.Location())
+				assert.True(t, stored.Equal(converted), "%v is not the same instant as %v", converted, stored)
+				assert.Equal(t, stored.In(loc).Format(time.RFC3339), converted.Format(time.RFC3339))
+			}
+
+			k, v = tuples(types.Timestamp(time.Time{}), nil)
+			r, err = conv.ConvertKVToSqlRow(k, v)
+			require.NoError(t, err)
+			assert.Equal(t, time.Time{}, r[2])
+			assert.Nil(t, r[3])
+		})
+	}
+}
+
+func TestWithValTupleEncoding(t *testing.T) {
+	cols := []schema.Column{mapIterTestCols[mapIterPKTag], mapIterTestCols[mapIterNameTag]}
+
+	k, err := types.NewTuple(types.Format_Default, types.Uint(mapIterPKTag), types.Int(1))
+	require.NoError(t, err)
+	// tags written in descending order
+	v, err := types.NewTuple(types.Format_Default, types.Uint(mapIterAgeTag), types.Uint(32), types.Uint(mapIterNameTag), types.String("bill"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		opts        []KVToSqlRowConverterOption
+		expectedRow sql.Row
+	}{
+		{
+			name:        "sorted encoding from format stops at larger tag",
+			expectedRow: sql.Row{int64(1), nil},
+		},
+		{
+			name:        "unsorted encoding scans full tuple",
+			opts:        []KVToSqlRowConverterOption{WithValTupleEncoding(UnsortedTagsValTupleEncoding)},
+			expectedRow: sql.Row{int64(1), "bill"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, test.opts...)
+			require.NoError(t, err)
+			r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRow, r)
+		})
+	}
+
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithValTupleEncoding(UnknownValTupleEncoding))
+	assert.Error(t, err)
+	_, err = NewKVToSqlRowConverterForCols(&types.NomsBinFormat{}, cols)
+	assert.Error(t, err)
+}
+
+func TestWithConversionTimingHook(t *testing.T) {
+	k, v := mapIterTestTuples(t, 1, types.String("bill"), types.Uint(32))
+
+	var tags []uint64
+	hook := func(tag uint64, elapsed time.Duration) {
+		assert.True(t, elapsed >= 0)
+		tags = append(tags, tag)
+	}
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithConversionTimingHook(hook, 1))
+	require.NoError(t, err)
+	_, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{mapIterPKTag, mapIterNameTag, mapIterAgeTag}, tags)
+
+	tags = nil
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithConversionTimingHook(hook, 2))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []uint64{mapIterPKTag, mapIterAgeTag, mapIterNameTag}, tags)
+
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithConversionTimingHook(hook, 0))
+	assert.Error(t, err)
+}
+
+func TestWithEnumSetMemberNames(t *testing.T) {
+	const (
+		pkTag   = 0
+		enumTag = 1
+		setTag  = 2
+	)
+
+	enumType, err := sql.CreateEnumType([]string{"small", "medium", "large"}, sql.Collation_Default)
+	require.NoError(t, err)
+	setType, err := sql.CreateSetType([]string{"red", "green", "blue"}, sql.Collation_Default)
+	require.NoError(t, err)
+
+	enumTI, err := typeinfo.FromSqlType(enumType)
+	require.NoError(t, err)
+	setTI, err := typeinfo.FromSqlType(setType)
+	require.NoError(t, err)
+
+	enumCol, err := schema.NewColumnWithTypeInfo("size", enumTag, enumTI, false, "", false, "")
+	require.NoError(t, err)
+	setCol, err := schema.NewColumnWithTypeInfo("colors", setTag, setTI, false, "", false, "")
+	require.NoError(t, err)
+	cols := []schema.Column{schema.NewColumn("id", pkTag, types.IntKind, true), enumCol, setCol}
+
+	tuples := func(enumVal, setVal types.Value) (types.Tuple, types.Tuple) {
+		k, err := types.NewTuple(types.Format_Default, types.Uint(pkTag), types.Int(1))
+		require.NoError(t, err)
+		v, err := types.NewTuple(types.Format_Default, types.Uint(enumTag), enumVal, types.Uint(setTag), setVal)
+		require.NoError(t, err)
+		return k, v
+	}
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithEnumSetMemberNames(nil))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		enumVal     types.Value
+		setVal      types.Value
+		expectedRow sql.Row
+	}{
+		{"members", types.Uint(2), types.Uint(5), sql.Row{int64(1), "medium", "red,blue"}},
+		{"empty", types.Uint(0), types.Uint(0), sql.Row{int64(1), "", ""}},
+		{"null", types.NullValue, types.NullValue, sql.Row{int64(1), nil, nil}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := conv.ConvertKVTuplesToSqlRow(tuples(test.enumVal, test.setVal))
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRow, r)
+		})
+	}
+
+	t.Run("out of range", func(t *testing.T) {
+		_, err := conv.ConvertKVTuplesToSqlRow(tuples(types.Uint(4), types.Uint(1)))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "'size' has ordinal 4")
+
+		_, err = conv.ConvertKVTuplesToSqlRow(tuples(types.Uint(1), types.Uint(8)))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "'colors' has ordinal 8")
+	})
+
+	t.Run("out of range with fallback", func(t *testing.T) {
+		fallback := func(col schema.Column, ordinal uint64) (interface{}, error) {
+			return fmt.Sprintf("<%s:%d>", col.Name, ordinal), nil
+		}
+
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithEnumSetMemberNames(fallback))
+		require.NoError(t, err)
+		r, err := conv.ConvertKVTuplesToSqlRow(tuples(types.Uint(4), types.Uint(8)))
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{int64(1), "<size:4>", "<colors:8>"}, r)
+	})
+}
+
+func TestWithValTupleDriftCheck(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...))
+	cols := []schema.Column{mapIterTestCols[mapIterPKTag], mapIterTestCols[mapIterNameTag]}
+
+	k, err := types.NewTuple(types.Format_Default, types.Uint(mapIterPKTag), types.Int(1))
+	require.NoError(t, err)
+	// a value for age, which is in the schema but not converted, and a trailing value for a column the schema lacks
+	v, err := types.NewTuple(types.Format_Default,
+		types.Uint(mapIterNameTag), types.String("bill"),
+		types.Uint(mapIterAgeTag), types.Uint(32),
+		types.Uint(100), types.String("extra"))
+	require.NoError(t, err)
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+	require.NoError(t, err)
+	_, ok := conv.ValTupleDrift()
+	assert.False(t, ok)
+
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithValTupleDriftCheck(sch))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{int64(1), "bill"}, r)
+	}
+
+	stats, ok := conv.ValTupleDrift()
+	assert.True(t, ok)
+	assert.Equal(t, ValTupleDriftStats{NumUnknownVals: 2, UnknownTags: []uint64{100}}, stats)
+
+	// tuples matching the schema do not add to the stats
+	_, matching := mapIterTestTuples(t, 2, types.String("rob"), types.Uint(25))
+	_, err = conv.ConvertKVTuplesToSqlRow(k, matching)
+	require.NoError(t, err)
+	stats, _ = conv.ValTupleDrift()
+	assert.Equal(t, uint64(2), stats.NumUnknownVals)
+}
+
+func TestWithTrailingNullsTrimmed(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	valCols := mapIterTestCols[1:]
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, valCols, WithTrailingNullsTrimmed())
+	require.NoError(t, err)
+	untrimmed, err := NewKVToSqlRowConverterForCols(types.Format_Default, valCols)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		vals     []types.Value
+		expected sql.Row
+	}{
+		{
+			name:     "no nulls",
+			vals:     []types.Value{types.String("bill"), types.Uint(32), types.Float(2.5), types.Timestamp(created)},
+			expected: sql.Row{"bill", uint64(32), 2.5, created},
+		},
+		{
+			name:     "leading and interior nulls",
+			vals:     []types.Value{nil, types.Uint(32), nil, types.Timestamp(created)},
+			expected: sql.Row{nil, uint64(32), nil, created},
+		},
+		{
+			name:     "trailing nulls",
+			vals:     []types.Value{types.String("bill"), nil, types.Float(2.5), nil},
+			expected: sql.Row{"bill", nil, 2.5},
+		},
+		{
+			name:     "leading, interior, and trailing nulls",
+			vals:     []types.Value{nil, types.Uint(32), nil, nil},
+			expected: sql.Row{nil, uint64(32)},
+		},
+		{
+			name:     "all nulls",
+			expected: sql.Row{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k, v := mapIterTestTuples(t, 1, test.vals...)
+			r, err := conv.ConvertKVToSqlRow(k, v)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, r)
+
+			// the option is opt in
+			r, err = untrimmed.ConvertKVToSqlRow(k, v)
+			require.NoError(t, err)
+			assert.Len(t, r, len(valCols))
+		})
+	}
+
+	// deltas treat columns missing from trimmed rows as NULL
+	k, v := mapIterTestTuples(t, 1, types.String("bill"))
+	prev, changed, err := conv.ConvertKVToSqlRowDelta(k, v, nil)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{"bill"}, prev)
+	assert.Equal(t, []uint64{mapIterNameTag}, changed)
+
+	k, v = mapIterTestTuples(t, 1, types.String("bill"), nil, types.Float(2.5))
+	_, changed, err = conv.ConvertKVToSqlRowDelta(k, v, prev)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{mapIterScoreTag}, changed)
+}
+
+func TestWithRawInts(t *testing.T) {
+	const (
+		pkTag = iota
+		enumTag
+		boolTag
+		dateTag
+		nameTag
+	)
+
+	enumType, err := sql.CreateEnumType([]string{"small", "medium", "large"}, sql.Collation_Default)
+	require.NoError(t, err)
+	enumTI, err := typeinfo.FromSqlType(enumType)
+	require.NoError(t, err)
+
+	enumCol, err := schema.NewColumnWithTypeInfo("size", enumTag, enumTI, false, "", false, "")
+	require.NoError(t, err)
+	boolCol, err := schema.NewColumnWithTypeInfo("flag", boolTag, typeinfo.BoolType, false, "", false, "")
+	require.NoError(t, err)
+	dateCol, err := schema.NewColumnWithTypeInfo("d", dateTag, typeinfo.DateType, false, "", false, "")
+	require.NoError(t, err)
+	cols := []schema.Column{schema.NewColumn("id", pkTag, types.IntKind, true), enumCol, boolCol, dateCol, schema.NewColumn("name", nameTag, types.StringKind, false)}
+
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithRawInts(nameTag))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'name'")
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithRawInts(pkTag, enumTag, boolTag, dateTag, 99))
+	require.NoError(t, err)
+
+	day := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	k, err := types.NewTuple(types.Format_Default, types.Uint(pkTag), types.Int(7))
+	require.NoError(t, err)
+	v, err := types.NewTuple(types.Format_Default, types.Uint(enumTag), types.Uint(2), types.Uint(boolTag), types.Bool(true), types.Uint(dateTag), types.Timestamp(day), types.Uint(nameTag), types.String("bill"))
+	require.NoError(t, err)
+
+	r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(7), uint64(2), int64(1), day.Unix() * 1000000, "bill"}, r)
+
+	v, err = types.NewTuple(types.Format_Default, types.Uint(boolTag), types.Bool(false), types.Uint(dateTag), types.Timestamp(time.Unix(0, 1500).UTC()))
+	require.NoError(t, err)
+
+	r, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(7), nil, int64(0), int64(1), nil}, r)
+}
+
+func TestWithTimeRepresentations(t *testing.T) {
+	const (
+		pkTag = iota
+		timeTag
+		nameTag
+	)
+
+	timeCol, err := schema.NewColumnWithTypeInfo("t", timeTag, typeinfo.TimeType, false, "", false, "")
+	require.NoError(t, err)
+	cols := []schema.Column{schema.NewColumn("id", pkTag, types.IntKind, true), timeCol, schema.NewColumn("name", nameTag, types.StringKind, false)}
+
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithTimeRepresentations(map[uint64]TimeRepresentation{timeTag: 7}, TimeOverflowAllow))
+	assert.Error(t, err)
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithTimeRepresentations(nil, 7))
+	assert.Error(t, err)
+
+	convertTime := func(t *testing.T, conv *KVToSqlRowConverter, stored time.Duration) (interface{}, error) {
+		k, err := types.NewTuple(types.Format_Default, types.Uint(pkTag), types.Int(1))
+		require.NoError(t, err)
+		v, err := types.NewTuple(types.Format_Default, types.Uint(timeTag), types.Int(int64(stored/time.Microsecond)), types.Uint(nameTag), types.String("x"))
+		require.NoError(t, err)
+
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		if err != nil {
+			return nil, err
+		}
+
+		// non TIME columns are unaffected
+		assert.Equal(t, "x", r[2])
+		return r[1], nil
+	}
+
+	const hour = time.Hour
+	tests := []struct {
+		name     string
+		stored   time.Duration
+		rep      TimeRepresentation
+		overflow TimeOverflow
+		expected interface{}
+	}{
+		{"string", 12*hour + 30*time.Minute, TimeAsString, TimeOverflowAllow, "12:30:00"},
+		{"string fraction", 90*time.Second + 500*time.Millisecond, TimeAsString, TimeOverflowAllow, "00:01:30.500000"},
+		{"string negative", -2 * hour, TimeAsString, TimeOverflowAllow, "-02:00:00"},
+		{"string over 24h", 100 * hour, TimeAsString, TimeOverflowAllow, "100:00:00"},
+		{"string over 24h wrapped", 100 * hour, TimeAsString, TimeOverflowWrap, "04:00:00"},
+		{"string negative wrapped", -2 * hour, TimeAsString, TimeOverflowWrap, "22:00:00"},
+		{"duration", 12*hour + 30*time.Minute, TimeAsDuration, TimeOverflowAllow, 12*hour + 30*time.Minute},
+		{"duration negative", -2 * hour, TimeAsDuration, TimeOverflowAllow, -2 * hour},
+		{"duration over 24h", 100 * hour, TimeAsDuration, TimeOverflowAllow, 100 * hour},
+		{"duration over 24h wrapped", 100 * hour, TimeAsDuration, TimeOverflowWrap, 4 * hour},
+		{"seconds", 90*time.Second + 500*time.Millisecond, TimeAsSeconds, TimeOverflowAllow, 90.5},
+		{"seconds negative", -2 * hour, TimeAsSeconds, TimeOverflowAllow, -7200.0},
+		{"seconds over 24h", 100 * hour, TimeAsSeconds, TimeOverflowAllow, 360000.0},
+		{"negative within a day", -2 * hour, TimeAsDuration, TimeOverflowError, -2 * hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithTimeRepresentations(map[uint64]TimeRepresentation{timeTag: test.rep, nameTag: TimeAsSeconds}, test.overflow))
+			require.NoError(t, err)
+
+			val, err := convertTime(t, conv, test.stored)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, val)
+		})
+	}
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithTimeRepresentations(map[uint64]TimeRepresentation{timeTag: TimeAsDuration}, TimeOverflowError))
+	require.NoError(t, err)
+
+	for _, stored := range []time.Duration{24 * hour, -100 * hour} {
+		_, err = convertTime(t, conv, stored)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "'t'")
+	}
+}
+
+func TestWithValTupleTagOrderCheck(t *testing.T) {
+	cols := []schema.Column{mapIterTestCols[mapIterPKTag], mapIterTestCols[mapIterNameTag], mapIterTestCols[mapIterAgeTag]}
+
+	k, err := types.NewTuple(types.Format_Default, types.Uint(mapIterPKTag), types.Int(1))
+	require.NoError(t, err)
+	// score is past the largest tag being converted, so age is dropped by the early exit unless the order is checked
+	v, err := types.NewTuple(types.Format_Default,
+		types.Uint(mapIterNameTag), types.String("bill"),
+		types.Uint(mapIterScoreTag), types.Float(2.5),
+		types.Uint(mapIterAgeTag), types.Uint(32))
+	require.NoError(t, err)
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+	require.NoError(t, err)
+	r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "bill", nil}, r)
+
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithValTupleTagOrderCheck())
+	require.NoError(t, err)
+	assert.Equal(t, GenericDecodeStrategy, conv.DecodeStrategy())
+
+	_, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrValTupleTagsOutOfOrder))
+	assert.Contains(t, err.Error(), "tag 2 at position 2 follows tag 3")
+
+	// repeated tags are out of order too
+	repeated, err := types.NewTuple(types.Format_Default, types.Uint(mapIterNameTag), types.String("bill"), types.Uint(mapIterNameTag), types.String("rob"))
+	require.NoError(t, err)
+	_, err = conv.ConvertKVTuplesToSqlRow(k, repeated)
+	assert.True(t, errors.Is(err, ErrValTupleTagsOutOfOrder))
+
+	_, sorted := mapIterTestTuples(t, 1, types.String("bill"), types.Uint(32), types.Float(2.5))
+	r, err = conv.ConvertKVTuplesToSqlRow(k, sorted)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "bill", uint64(32)}, r)
+
+	// tuples whose tags may be unsorted aren't checked
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithValTupleTagOrderCheck(), WithValTupleEncoding(UnsortedTagsValTupleEncoding))
+	require.NoError(t, err)
+	r, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "bill", uint64(32)}, r)
+}
+
+func TestWithSwappedKVCheck(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...))
+	k, v := mapIterTestTuples(t, 1, types.String("bill"), types.Uint(32), types.Float(2.5), nil)
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithSwappedKVCheck(sch))
+	require.NoError(t, err)
+
+	r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "bill", uint64(32), 2.5, nil}, r)
+
+	_, err = conv.ConvertKVTuplesToSqlRow(v, k)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrKVSwapped))
+
+	_, err = conv.ConvertKVToSqlRow(v, k)
+	assert.True(t, errors.Is(err, ErrKVSwapped))
+
+	// a row whose values are all NULL has an empty value tuple, which can't be told apart from a swapped key
+	empty := types.EmptyTuple(types.Format_Default)
+	r, err = conv.ConvertKVTuplesToSqlRow(k, empty)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), nil, nil, nil, nil}, r)
+
+	// swapped tuples aren't detected unless the check is enabled
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+	_, err = conv.ConvertKVTuplesToSqlRow(v, k)
+	assert.False(t, errors.Is(err, ErrKVSwapped))
+}
+
+func TestWithMaxRowSize(t *testing.T) {
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithMaxRowSize(64))
+	require.NoError(t, err)
+
+	// the id, age and score are 8 bytes each, so the name can be up to 40 bytes
+	convert := func(nameLen int) error {
+		k, v := mapIterTestTuples(t, 1, types.String(strings.Repeat("n", nameLen)), types.Uint(32), types.Float(2.5), nil)
+		_, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		return err
+	}
+
+	assert.NoError(t, convert(39))
+	assert.NoError(t, convert(40))
+
+	err = convert(41)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrRowTooLarge))
+	assert.Contains(t, err.Error(), "65 bytes")
+	assert.Contains(t, err.Error(), "largest columns: name (41 bytes), id (8 bytes), age (8 bytes)")
+
+	// rows of any size are converted without the option
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+	assert.NoError(t, convert(1000))
+
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithMaxRowSize(0))
+	assert.Error(t, err)
+}
+
+func TestWithMaskedColumns(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols,
+		WithMaskedColumns(map[uint64]interface{}{mapIterNameTag: "***", 99: "***"}),
+		WithStringNormalizers(map[uint64]StringNormalizer{mapIterNameTag: strings.ToUpper}))
+	require.NoError(t, err)
+
+	k, v := mapIterTestTuples(t, 7, types.String("bill"), types.Uint(32), types.Float(2.5), types.Timestamp(created))
+	r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(7), "***", uint64(32), 2.5, created}, r)
+
+	// NULLs stay NULL
+	k, v = mapIterTestTuples(t, 7, nil, types.Uint(32))
+	r, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(7), nil, uint64(32), nil, nil}, r)
+
+	// key columns can be masked too, and the row keeps its arity
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols,
+		WithMaskedColumns(map[uint64]interface{}{mapIterPKTag: int64(-1), mapIterScoreTag: "***"}))
+	require.NoError(t, err)
+	assert.Equal(t, SortedTagsDecodeStrategy, conv.DecodeStrategy())
+
+	k, v = mapIterTestTuples(t, 7, types.String("bill"), types.Uint(32), types.Float(2.5), types.Timestamp(created))
+	r, size, err := conv.ConvertKVToSqlRowWithSize(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(-1), "bill", uint64(32), "***", created}, r)
+	assert.Equal(t, EstimateSqlRowSize(r), size)
+}
+
+func TestWithMissingValuesAsZero(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithMissingValuesAsZero(mapIterNameTag, mapIterAgeTag, 99))
+	require.NoError(t, err)
+
+	k, v := mapIterTestTuples(t, 1, nil, nil, nil, types.Timestamp(created))
+	r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "", uint64(0), nil, created}, r)
+
+	k, v = mapIterTestTuples(t, 1, types.String("bill"), types.Uint(32))
+	r, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "bill", uint64(32), nil, nil}, r)
+
+	// NULLs written into the tuple stay nil
+	v, err = types.NewTuple(types.Format_Default, types.Uint(mapIterNameTag), types.NullValue, types.Uint(mapIterAgeTag), types.NullValue)
+	require.NoError(t, err)
+	r, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), nil, nil, nil, nil}, r)
+
+	// with no tags every column is zeroed
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithMissingValuesAsZero())
+	require.NoError(t, err)
+	k, v = mapIterTestTuples(t, 1)
+	r, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "", uint64(0), float64(0), sql.Timestamp.Zero()}, r)
+
+	// an option without tags zeroes every column of each converter it is used with
+	allCols := WithMissingValuesAsZero()
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols[:2], allCols)
+	require.NoError(t, err)
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, allCols)
+	require.NoError(t, err)
+	r, err = conv.ConvertKVTuplesToSqlRow(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "", uint64(0), float64(0), sql.Timestamp.Zero()}, r)
+}
+
+func TestWithBinaryEncoding(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	binTI, err := typeinfo.FromSqlType(sql.MustCreateBinary(sqltypes.VarBinary, 16))
+	require.NoError(t, err)
+	binCol, err := schema.NewColumnWithTypeInfo("bin", 1, binTI, false, "", false, "")
+	require.NoError(t, err)
+	blobTI, err := typeinfo.FromSqlType(sql.LongBlob)
+	require.NoError(t, err)
+	blobCol, err := schema.NewColumnWithTypeInfo("data", 2, blobTI, false, "", false, "")
+	require.NoError(t, err)
+	cols := []schema.Column{schema.NewColumn("id", 0, types.IntKind, true), binCol, blobCol}
+
+	tuples := func(id int64, bin, blob string) (types.Tuple, types.Tuple) {
+		binVal, err := binCol.TypeInfo.ConvertValueToNomsValue(ctx, vrw, bin)
+		require.NoError(t, err)
+		blobVal, err := blobCol.TypeInfo.ConvertValueToNomsValue(ctx, vrw, blob)
+		require.NoError(t, err)
+		k, err := types.NewTuple(vrw.Format(), types.Uint(0), types.Int(id))
+		require.NoError(t, err)
+		v, err := types.NewTuple(vrw.Format(), types.Uint(1), binVal, types.Uint(2), blobVal)
+		require.NoError(t, err)
+		return k, v
+	}
+
+	emptyK, emptyV := tuples(1, "", "")
+	multiK, multiV := tuples(2, "\x00\xff日", "日本")
+
+	tests := []struct {
+		name          string
+		opts          []KVToSqlRowConverterOption
+		empty, multi  sql.Row
+		expectedError bool
+	}{
+		{
+			name:  "default",
+			empty: sql.Row{int64(1), "", ""},
+			multi: sql.Row{int64(2), "\x00\xff日", "日本"},
+		},
+		{
+			name:  "hex",
+			opts:  []KVToSqlRowConverterOption{WithBinaryEncoding(HexBinaryEncoding)},
+			empty: sql.Row{int64(1), "", ""},
+			multi: sql.Row{int64(2), "00FFE697A5", "E697A5E69CAC"},
+		},
+		{
+			name:  "base64",
+			opts:  []KVToSqlRowConverterOption{WithBinaryEncoding(Base64BinaryEncoding)},
+			empty: sql.Row{int64(1), "", ""},
+			multi: sql.Row{int64(2), "AP/ml6U=", "5pel5pys"},
+		},
+		{
+			name: "per column",
+			opts: []KVToSqlRowConverterOption{
+				WithBinaryEncoding(HexBinaryEncoding, binCol.Tag),
+				WithBinaryEncoding(Base64BinaryEncoding, blobCol.Tag, 99),
+			},
+			empty: sql.Row{int64(1), "", ""},
+			multi: sql.Row{int64(2), "00FFE697A5", "5pel5pys"},
+		},
+		{
+			name:          "not a binary column",
+			opts:          []KVToSqlRowConverterOption{WithBinaryEncoding(HexBinaryEncoding, 0)},
+			expectedError: true,
+		},
+		{
+			name:          "unknown encoding",
+			opts:          []KVToSqlRowConverterOption{WithBinaryEncoding(BinaryEncoding(42))},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), cols, test.opts...)
+
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			r, err := conv.ConvertKVTuplesToSqlRow(emptyK, emptyV)
+			require.NoError(t, err)
+			assert.Equal(t, test.empty, r)
+
+			r, err = conv.ConvertKVTuplesToSqlRow(multiK, multiV)
+			require.NoError(t, err)
+			assert.Equal(t, test.multi, r)
+		})
+	}
+
+	t.Run("fixed width", func(t *testing.T) {
+		conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), cols, WithBinaryEncoding(Base64BinaryEncoding))
+		require.NoError(t, err)
+		dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(emptyK, emptyV, multiK, multiV), nil, conv)
+
+		var buf bytes.Buffer
+		err = RenderFixedWidth(&buf, dmi, schema.MustSchemaFromCols(schema.NewColCollection(cols...)))
+		require.NoError(t, err)
+
+		expected := "" +
+			"+----+----------+----------+\n" +
+			"| id | bin      | data     |\n" +
+			"+----+----------+----------+\n" +
+			"| 1  |          |          |\n" +
+			"| 2  | AP/ml6U= | 5pel5pys |\n" +
+			"+----+----------+----------+\n"
+		assert.Equal(t, expected, buf.String())
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ConverterForKey returns the converter used for the row with the key k and every row after it.  k is the first key
+// read at or past the boundary the function was registered with, and can be used to look up the schema version the
+// rows from that point on were written with.
+type ConverterForKey func(k types.Tuple) (*KVToSqlRowConverter, error)
+
+// converterSwitch is a pending change of converter
+type converterSwitch struct {
+	boundary   types.Tuple
+	convForKey ConverterForKey
+}
+
+// SwitchConverterAt causes the iterator to replace its converter, once it reads the first key which is not less than
+// boundary, with the converter returned by convForKey for that key.  That row and all rows after it are converted with
+// the new converter, and rows before it with the current one.
+//
+// This is an advanced feature for the rare case where the layout of the tuples being read changes part way through the
+// source, such as a schema migration which runs during a long operation, and the caller knows the key at which it
+// happens.  The new converter must produce rows with the same number of columns as the current one, and must convert
+// every column statistics are being collected for, matched by name, so the statistics carry across the boundary.
+// Several switches may be pending at once but their boundaries must be registered in increasing order.  Pending
+// switches are discarded by Reset.
+func (dmi *DoltMapIter) SwitchConverterAt(boundary types.Tuple, convForKey ConverterForKey) error {
+	if n := len(dmi.switches); n > 0 {
+		isLess, err := dmi.switches[n-1].boundary.Less(boundary.Format(), boundary)
+
+		if err != nil {
+			return err
+		}
+
+		if !isLess {
+			return fmt.Errorf("converter switch boundaries must be registered in increasing order")
+		}
+	}
+
+	dmi.switches = append(dmi.switches, converterSwitch{boundary: boundary, convForKey: convForKey})
+	return nil
+}
+
+// switchConverter replaces the iterator's converter for each pending switch whose boundary the key given has reached
+func (dmi *DoltMapIter) switchConverter(k types.Tuple) error {
+	for len(dmi.switches) > 0 {
+		sw := dmi.switches[0]
+		isLess, err := k.Less(k.Format(), sw.boundary)
+
+		if err != nil {
+			return err
+		}
+
+		if isLess {
+			return nil
+		}
+
+		dmi.switches = dmi.switches[1:]
+		conv, err := sw.convForKey(k)
+
+		if err != nil {
+			return err
+		}
+
+		if conv.rowSize != dmi.conv.rowSize {
+			return fmt.Errorf("cannot switch from a converter with %d columns to one with %d columns", dmi.conv.rowSize, conv.rowSize)
+		}
+
+		statIdxs, err := dmi.statIdxsForConv(conv)
+
+		if err != nil {
+			return err
+		}
+
+		for i, c := range dmi.stats {
+			c.idx = statIdxs[i]
+		}
+
+		dmi.conv = conv
+	}
+
+	return nil
+}
+
+// statIdxsForConv returns the index in the rows produced by conv of each column statistics are being collected for
+func (dmi *DoltMapIter) statIdxsForConv(conv *KVToSqlRowConverter) ([]int, error) {
+	idxs := make([]int, len(dmi.stats))
+	for i, c := range dmi.stats {
+		idxs[i] = sqlColIdxByName(conv, c.stats.Name)
+
+		if idxs[i] < 0 {
+			return nil, fmt.Errorf("cannot switch to a converter which does not convert column '%s' whose statistics are being collected", c.stats.Name)
+		}
+	}
+
+	return idxs, nil
+}
+
+// sqlColIdxByName returns the index in the rows produced by conv of the column with the name given, or -1 if conv
+// doesn't convert it.  Migrations may change a column's tag, but not its name.
+func sqlColIdxByName(conv *KVToSqlRowConverter, name string) int {
+	for idx, col := range conv.cols {
+		if col.Name == name {
+			return idx
+		}
+	}
+
+	return -1
+}
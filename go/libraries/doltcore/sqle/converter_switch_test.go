@@ -0,0 +1,163 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// migratedTestCols is the layout of mergeTestCols after a migration which moved column a from tag 1 to tag 3
+var migratedTestCols = []schema.Column{
+	schema.NewColumn("pk", 0, types.IntKind, true),
+	schema.NewColumn("a", 3, types.IntKind, false),
+	schema.NewColumn("b", 2, types.StringKind, false),
+}
+
+func migratedTestKV(t *testing.T, pk int, a int, b string) []types.Tuple {
+	k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(pk))
+	require.NoError(t, err)
+	v, err := types.NewTuple(types.Format_Default, types.Uint(2), types.String(b), types.Uint(3), types.Int(a))
+	require.NoError(t, err)
+
+	return []types.Tuple{k, v}
+}
+
+func intKey(t *testing.T, pk int) types.Tuple {
+	k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(pk))
+	require.NoError(t, err)
+
+	return k
+}
+
+func TestDoltMapIterSwitchConverterAt(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+	migratedConv, err := NewKVToSqlRowConverterForCols(types.Format_Default, migratedTestCols)
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t, []interface{}{1, 10, "a"}, []interface{}{2, 20, "b"})
+	kvs = append(kvs, migratedTestKV(t, 4, 40, "d")...)
+	kvs = append(kvs, migratedTestKV(t, 5, 50, "e")...)
+
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	require.NoError(t, dmi.CollectStats(1))
+
+	var switchedAt []types.Tuple
+	require.NoError(t, dmi.SwitchConverterAt(intKey(t, 3), func(k types.Tuple) (*KVToSqlRowConverter, error) {
+		switchedAt = append(switchedAt, k)
+		return migratedConv, nil
+	}))
+	assert.Error(t, dmi.SwitchConverterAt(intKey(t, 3), nil))
+
+	expected := []sql.Row{
+		{int64(1), int64(10), "a"},
+		{int64(2), int64(20), "b"},
+		{int64(4), int64(40), "d"},
+		{int64(5), int64(50), "e"},
+	}
+	assert.Equal(t, expected, drainRowIter(t, dmi))
+	require.Len(t, switchedAt, 1)
+	assert.True(t, switchedAt[0].Equals(intKey(t, 4)))
+
+	// statistics follow column a to its new tag
+	stats := dmi.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(4), stats[0].RowCount)
+	assert.Equal(t, int64(10), stats[0].Min)
+	assert.Equal(t, int64(50), stats[0].Max)
+
+	// without the switch the migrated rows lose column a
+	dmi = NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	assert.Equal(t, sql.Row{int64(4), nil, "d"}, drainRowIter(t, dmi)[2])
+
+	// switching to a converter with a different number of columns fails at the boundary
+	narrowConv, err := NewKVToSqlRowConverterForCols(types.Format_Default, migratedTestCols[:2])
+	require.NoError(t, err)
+
+	dmi = NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	require.NoError(t, dmi.SwitchConverterAt(intKey(t, 4), func(k types.Tuple) (*KVToSqlRowConverter, error) {
+		return narrowConv, nil
+	}))
+
+	for i := 0; i < 2; i++ {
+		_, err = dmi.Next()
+		require.NoError(t, err)
+	}
+
+	_, err = dmi.Next()
+	assert.Error(t, err)
+}
+
+func TestDoltMapIterSwitchConverterRowSize(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	// a converter which leaves column b out of rows of the same size can be switched to
+	partialConv, err := NewKVToSqlRowConverter(types.Format_Default, map[uint64]int{0: 0, 3: 1}, migratedTestCols[:2], 3)
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t, []interface{}{1, 10, "a"})
+	kvs = append(kvs, migratedTestKV(t, 4, 40, "d")...)
+
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	require.NoError(t, dmi.SwitchConverterAt(intKey(t, 2), func(k types.Tuple) (*KVToSqlRowConverter, error) {
+		return partialConv, nil
+	}))
+
+	expected := []sql.Row{
+		{int64(1), int64(10), "a"},
+		{int64(4), int64(40), nil},
+	}
+	assert.Equal(t, expected, drainRowIter(t, dmi))
+}
+
+func TestDoltMapIterResetRestoresConverter(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+	migratedConv, err := NewKVToSqlRowConverterForCols(types.Format_Default, migratedTestCols)
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t, []interface{}{1, 10, "a"})
+	kvs = append(kvs, migratedTestKV(t, 4, 40, "d")...)
+
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	require.NoError(t, dmi.CollectStats(1))
+	require.NoError(t, dmi.SwitchConverterAt(intKey(t, 2), func(k types.Tuple) (*KVToSqlRowConverter, error) {
+		return migratedConv, nil
+	}))
+	require.Len(t, drainRowIter(t, dmi), 2)
+
+	// rows written before the migration are read with the original converter once more
+	unmigrated := mergeTestKVs(t, []interface{}{2, 20, "b"})
+	require.NoError(t, dmi.Reset(kvGetFuncForTuples(unmigrated...), nil))
+	assert.Equal(t, []sql.Row{{int64(2), int64(20), "b"}}, drainRowIter(t, dmi))
+
+	stats := dmi.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(1), stats[0].RowCount)
+	assert.Equal(t, int64(20), stats[0].Min)
+	assert.Equal(t, int64(20), stats[0].Max)
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DecodeStrategy is the approach a KVToSqlRowConverter uses to read values from key and value tuples.  The strategy is
+// selected when the converter is constructed from the layout of the columns being converted and the options given, so
+// callers get the fastest strategy which produces the same rows without choosing it themselves.
+type DecodeStrategy int
+
+const (
+	// GenericDecodeStrategy scans each value tuple in full looking up every tag.  It is used when value tuple tags may
+	// be unsorted or when every tag must be seen, as when checking for value tuple drift or verifying the tag order.
+	GenericDecodeStrategy DecodeStrategy = iota
+	// SortedTagsDecodeStrategy stops reading a value tuple once the largest tag being converted has been passed
+	SortedTagsDecodeStrategy
+	// SingleIntPKDecodeStrategy reads the one primary key column being converted, a BIGINT, directly from the key tuple
+	// without looking up tags or going through its TypeInfo.  Value tuples are read as with SortedTagsDecodeStrategy.
+	SingleIntPKDecodeStrategy
+)
+
+// String returns the name of the strategy
+func (s DecodeStrategy) String() string {
+	switch s {
+	case GenericDecodeStrategy:
+		return "generic"
+	case SortedTagsDecodeStrategy:
+		return "sorted tags"
+	case SingleIntPKDecodeStrategy:
+		return "single int pk"
+	default:
+		return fmt.Sprintf("unknown decode strategy %d", int(s))
+	}
+}
+
+// DecodeStrategy returns the strategy the converter selected for reading tuples
+func (conv *KVToSqlRowConverter) DecodeStrategy() DecodeStrategy {
+	return conv.strategy
+}
+
+// selectDecodeStrategy picks the fastest strategy which can be used for the columns being converted once all options
+// have been applied.  For SingleIntPKDecodeStrategy the tag of the primary key column is also returned.
+func (conv *KVToSqlRowConverter) selectDecodeStrategy() (DecodeStrategy, uint64) {
+	if conv.valEncoding != SortedTagsValTupleEncoding || conv.drift != nil || conv.checkTagOrder {
+		return GenericDecodeStrategy, 0
+	}
+
+	if conv.valsFromKey != 1 || conv.timingHook != nil {
+		return SortedTagsDecodeStrategy, 0
+	}
+
+	for tag, idx := range conv.tagToSqlColIdx {
+		col := conv.cols[idx]
+
+		if !col.IsPartOfPK {
+			continue
+		}
+
+		_, hasReader := conv.valReaders[tag]
+		_, hasTransform := conv.valTransforms[tag]
+		_, hasMask := conv.masks[tag]
+
+		if hasReader || hasTransform || hasMask || !col.TypeInfo.Equals(typeinfo.Int64Type) {
+			return SortedTagsDecodeStrategy, 0
+		}
+
+		return SingleIntPKDecodeStrategy, tag
+	}
+
+	return SortedTagsDecodeStrategy, 0
+}
+
+// readIntPK reads the value of the BIGINT primary key column being converted from the key tuple
+func (conv *KVToSqlRowConverter) readIntPK(cols []interface{}, k types.Tuple, tupItr *types.TupleIterator, size *int64) error {
+	err := tupItr.InitForTuple(k)
+
+	if err != nil {
+		return err
+	}
+
+	nbf := k.Format()
+	primReader, numPrimitives := tupItr.CodecReader()
+
+	for pos := uint64(0); pos+1 < numPrimitives; pos += 2 {
+		if primReader.ReadKind() != types.UintKind {
+			return errors.New("Encountered unexpected kind while attempting to read tag")
+		}
+
+		if primReader.ReadUint() != conv.intPKTag {
+			err = primReader.SkipValue(nbf)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		idx := conv.tagToSqlColIdx[conv.intPKTag]
+		switch kind := primReader.ReadKind(); kind {
+		case types.IntKind:
+			cols[idx] = primReader.ReadInt()
+
+			if size != nil {
+				*size += 8
+			}
+		case types.NullKind:
+			cols[idx] = nil
+		default:
+			return fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, conv.cols[idx].TypeInfo.String(), kind)
+		}
+
+		return nil
+	}
+
+	return nil
+}
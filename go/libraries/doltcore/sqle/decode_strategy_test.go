@@ -0,0 +1,136 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDecodeStrategy(t *testing.T) {
+	int32PK, err := schema.NewColumnWithTypeInfo("id", mapIterPKTag, typeinfo.Int32Type, true, "", false, "")
+	require.NoError(t, err)
+
+	hook := func(tag uint64, elapsed time.Duration) {}
+
+	tests := []struct {
+		name     string
+		cols     []schema.Column
+		opts     []KVToSqlRowConverterOption
+		expected DecodeStrategy
+	}{
+		{
+			name:     "single int pk",
+			cols:     mapIterTestCols,
+			expected: SingleIntPKDecodeStrategy,
+		},
+		{
+			name:     "single int pk only",
+			cols:     mapIterTestCols[:1],
+			expected: SingleIntPKDecodeStrategy,
+		},
+		{
+			name:     "pk not converted",
+			cols:     mapIterTestCols[1:],
+			expected: SortedTagsDecodeStrategy,
+		},
+		{
+			name: "string pk",
+			cols: []schema.Column{
+				schema.NewColumn("name", 0, types.StringKind, true),
+				schema.NewColumn("age", 1, types.UintKind, false),
+			},
+			expected: SortedTagsDecodeStrategy,
+		},
+		{
+			name:     "narrower int pk",
+			cols:     append([]schema.Column{int32PK}, mapIterTestCols[1:]...),
+			expected: SortedTagsDecodeStrategy,
+		},
+		{
+			name: "composite pk",
+			cols: []schema.Column{
+				schema.NewColumn("id", 0, types.IntKind, true),
+				schema.NewColumn("id2", 1, types.IntKind, true),
+				schema.NewColumn("val", 2, types.StringKind, false),
+			},
+			expected: SortedTagsDecodeStrategy,
+		},
+		{
+			name:     "timing hook",
+			cols:     mapIterTestCols,
+			opts:     []KVToSqlRowConverterOption{WithConversionTimingHook(hook, 1)},
+			expected: SortedTagsDecodeStrategy,
+		},
+		{
+			name:     "unsorted value tuples",
+			cols:     mapIterTestCols,
+			opts:     []KVToSqlRowConverterOption{WithValTupleEncoding(UnsortedTagsValTupleEncoding)},
+			expected: GenericDecodeStrategy,
+		},
+		{
+			name:     "drift check",
+			cols:     mapIterTestCols,
+			opts:     []KVToSqlRowConverterOption{WithValTupleDriftCheck(schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...)))},
+			expected: GenericDecodeStrategy,
+		},
+		{
+			name:     "tag order check",
+			cols:     mapIterTestCols,
+			opts:     []KVToSqlRowConverterOption{WithValTupleTagOrderCheck()},
+			expected: GenericDecodeStrategy,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, test.cols, test.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, conv.DecodeStrategy(), "selected %s", conv.DecodeStrategy())
+		})
+	}
+}
+
+func TestSingleIntPKDecodeStrategyMatchesGeneric(t *testing.T) {
+	fast, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+	require.Equal(t, SingleIntPKDecodeStrategy, fast.DecodeStrategy())
+
+	generic, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithValTupleEncoding(UnsortedTagsValTupleEncoding))
+	require.NoError(t, err)
+	require.Equal(t, GenericDecodeStrategy, generic.DecodeStrategy())
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, id := range []int64{0, 1, -42, 1 << 40} {
+		k, v := mapIterTestTuples(t, id, types.String("bill"), nil, types.Float(2.5), types.Timestamp(created))
+
+		fastRow, fastSize, err := fast.ConvertKVToSqlRowWithSize(k, v)
+		require.NoError(t, err)
+		genericRow, genericSize, err := generic.ConvertKVToSqlRowWithSize(k, v)
+		require.NoError(t, err)
+
+		assert.Equal(t, sql.Row{id, "bill", nil, 2.5, created}, fastRow)
+		assert.Equal(t, genericRow, fastRow)
+		assert.Equal(t, genericSize, fastSize)
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// DiffType is the kind of change a DiffIter found for a row
+type DiffType int
+
+const (
+	// DiffAdded is a row which is only in the new map
+	DiffAdded DiffType = iota
+	// DiffRemoved is a row which is only in the old map
+	DiffRemoved
+	// DiffModified is a row which is in both maps with different values
+	DiffModified
+)
+
+// String returns the name of the change, which is the value of the diff_type column of the rows of a DiffIter
+func (dt DiffType) String() string {
+	switch dt {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffModified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// DiffRow is a row which differs between two maps
+type DiffRow struct {
+	Type DiffType
+	// From is the row converted from the old map, which is nil for added rows
+	From sql.Row
+	// To is the row converted from the new map, which is nil for removed rows
+	To sql.Row
+	// ChangedTags are the tags of the columns whose values differ for modified rows, with the columns of the new schema
+	// first, in the order of the new rows, followed by columns only in the old schema
+	ChangedTags []uint64
+}
+
+// diffCol is a column being compared by a DiffIter along with its position in the old and new rows, which is -1 for a
+// column which is only on one side
+type diffCol struct {
+	tag     uint64
+	fromIdx int
+	toIdx   int
+	sqlType sql.Type
+}
+
+// DiffIter reads two maps of the same table, such as the table at two commits, and returns their rows which were added,
+// removed or modified, annotated with the type of change.  It backs the diff tables, where each row is the new values
+// followed by the old values and the type of change.  The maps are read together in the order of a KeyComparator over
+// their primary key, as a MergeIter reads its sources, and rows with equal keys are compared.  The old and new rows are
+// converted by their own converters, so the schemas of the two sides may differ: columns are matched by tag, values
+// are compared with the type of the new column, and a column only in one of the schemas counts as changed when its
+// value is not NULL.  Rows whose value tuples differ only in columns which aren't being converted are not returned, nor
+// are rows filtered out by the key predicate of a converter created with WithKeyPredicate.  A key filtered out on
+// either side of a modified row leaves the row out of the diff.
+type DiffIter struct {
+	ctx      context.Context
+	kc       *KeyComparator
+	fromConv *KVToSqlRowConverter
+	toConv   *KVToSqlRowConverter
+	from     *mergeSource
+	to       *mergeSource
+	fromOK   bool
+	toOK     bool
+	started  bool
+	cols     []diffCol
+}
+
+var _ sql.RowIter = (*DiffIter)(nil)
+
+// NewDiffIter returns a DiffIter over the key value pairs of the old map read from fromGet and those of the new map read
+// from toGet, which must both be ordered by the sort key of kc, converting them with fromConv and toConv respectively
+func NewDiffIter(ctx context.Context, kc *KeyComparator, fromConv, toConv *KVToSqlRowConverter, fromGet, toGet KVGetFunc) *DiffIter {
+	var cols []diffCol
+	seen := make(map[uint64]struct{})
+	for idx, col := range toConv.cols {
+		if mappedIdx, ok := toConv.tagToSqlColIdx[col.Tag]; !ok || mappedIdx != idx {
+			continue
+		}
+
+		fromIdx, ok := fromConv.tagToSqlColIdx[col.Tag]
+		if !ok {
+			fromIdx = -1
+		}
+
+		cols = append(cols, diffCol{tag: col.Tag, fromIdx: fromIdx, toIdx: idx, sqlType: col.TypeInfo.ToSqlType()})
+		seen[col.Tag] = struct{}{}
+	}
+
+	for idx, col := range fromConv.cols {
+		if mappedIdx, ok := fromConv.tagToSqlColIdx[col.Tag]; !ok || mappedIdx != idx {
+			continue
+		}
+
+		if _, ok := seen[col.Tag]; !ok {
+			cols = append(cols, diffCol{tag: col.Tag, fromIdx: idx, toIdx: -1, sqlType: col.TypeInfo.ToSqlType()})
+		}
+	}
+
+	return &DiffIter{
+		ctx:      ctx,
+		kc:       kc,
+		fromConv: fromConv,
+		toConv:   toConv,
+		from:     &mergeSource{kvGet: fromGet},
+		to:       &mergeSource{kvGet: toGet},
+		cols:     cols,
+	}
+}
+
+// NextDiff returns the next row which differs between the maps in key order until both are exhausted, at which point
+// io.EOF is returned
+func (itr *DiffIter) NextDiff() (DiffRow, error) {
+	if !itr.started {
+		err := itr.advanceFrom()
+
+		if err != nil {
+			return DiffRow{}, err
+		}
+
+		err = itr.advanceTo()
+
+		if err != nil {
+			return DiffRow{}, err
+		}
+
+		itr.started = true
+	}
+
+	for {
+		if !itr.fromOK && !itr.toOK {
+			return DiffRow{}, io.EOF
+		}
+
+		n := 1
+		if itr.fromOK && itr.toOK {
+			var err error
+			n, err = itr.kc.CompareSortKeys(itr.from.sortKey, itr.to.sortKey)
+
+			if err != nil {
+				return DiffRow{}, err
+			}
+		} else if itr.fromOK {
+			n = -1
+		}
+
+		switch {
+		case n < 0:
+			r, convErr := itr.fromConv.ConvertKVTuplesToSqlRow(itr.from.k, itr.from.v)
+
+			if convErr != nil && convErr != ErrFilteredByKey {
+				return DiffRow{}, convErr
+			}
+
+			err := itr.advanceFrom()
+
+			if err != nil {
+				return DiffRow{}, err
+			}
+
+			if convErr == nil {
+				return DiffRow{Type: DiffRemoved, From: r}, nil
+			}
+
+		case n > 0:
+			r, convErr := itr.toConv.ConvertKVTuplesToSqlRow(itr.to.k, itr.to.v)
+
+			if convErr != nil && convErr != ErrFilteredByKey {
+				return DiffRow{}, convErr
+			}
+
+			err := itr.advanceTo()
+
+			if err != nil {
+				return DiffRow{}, err
+			}
+
+			if convErr == nil {
+				return DiffRow{Type: DiffAdded, To: r}, nil
+			}
+
+		default:
+			dr, changed, err := itr.diffMatched()
+
+			if err != nil {
+				return DiffRow{}, err
+			}
+
+			err = itr.advanceFrom()
+
+			if err != nil {
+				return DiffRow{}, err
+			}
+
+			err = itr.advanceTo()
+
+			if err != nil {
+				return DiffRow{}, err
+			}
+
+			if changed {
+				return dr, nil
+			}
+		}
+	}
+}
+
+// diffMatched compares the current rows of both maps, which have the same key, returning false if none of the columns
+// being converted changed or if either row is filtered out by its converter's key predicate
+func (itr *DiffIter) diffMatched() (DiffRow, bool, error) {
+	if itr.from.v.Equals(itr.to.v) {
+		return DiffRow{}, false, nil
+	}
+
+	from, err := itr.fromConv.ConvertKVTuplesToSqlRow(itr.from.k, itr.from.v)
+
+	if err == ErrFilteredByKey {
+		return DiffRow{}, false, nil
+	} else if err != nil {
+		return DiffRow{}, false, err
+	}
+
+	to, err := itr.toConv.ConvertKVTuplesToSqlRow(itr.to.k, itr.to.v)
+
+	if err == ErrFilteredByKey {
+		return DiffRow{}, false, nil
+	} else if err != nil {
+		return DiffRow{}, false, err
+	}
+
+	var changedTags []uint64
+	for _, col := range itr.cols {
+		var fromVal, toVal interface{}
+		if col.fromIdx >= 0 {
+			fromVal = SqlRowValue(from, col.fromIdx)
+		}
+
+		if col.toIdx >= 0 {
+			toVal = SqlRowValue(to, col.toIdx)
+		}
+
+		if fromVal == nil || toVal == nil {
+			if fromVal != nil || toVal != nil {
+				changedTags = append(changedTags, col.tag)
+			}
+
+			continue
+		}
+
+		// values which can't be compared as the new type, such as after a type change, count as changed
+		n, err := col.sqlType.Compare(fromVal, toVal)
+
+		if err != nil || n != 0 {
+			changedTags = append(changedTags, col.tag)
+		}
+	}
+
+	if len(changedTags) == 0 {
+		return DiffRow{}, false, nil
+	}
+
+	return DiffRow{Type: DiffModified, From: from, To: to, ChangedTags: changedTags}, true, nil
+}
+
+func (itr *DiffIter) advanceFrom() (err error) {
+	itr.fromOK, err = itr.from.advance(itr.ctx, itr.kc)
+	return err
+}
+
+func (itr *DiffIter) advanceTo() (err error) {
+	itr.toOK, err = itr.to.advance(itr.ctx, itr.kc)
+	return err
+}
+
+// Next returns the next row which differs between the maps as the values of the new row, followed by the values of
+// the old row and the name of the DiffType.  The values of the side a row is missing from are NULL.
+func (itr *DiffIter) Next() (sql.Row, error) {
+	dr, err := itr.NextDiff()
+
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(sql.Row, itr.toConv.rowSize+itr.fromConv.rowSize+1)
+	copy(r, dr.To)
+	copy(r[itr.toConv.rowSize:], dr.From)
+	r[len(r)-1] = dr.Type.String()
+
+	return r, nil
+}
+
+// Close required by sql.RowIter interface
+func (itr *DiffIter) Close(*sql.Context) error {
+	return nil
+}
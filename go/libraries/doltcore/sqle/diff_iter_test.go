@@ -0,0 +1,137 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func drainDiffIter(t *testing.T, itr *DiffIter) []DiffRow {
+	var diffs []DiffRow
+	for {
+		dr, err := itr.NextDiff()
+
+		if err == io.EOF {
+			return diffs
+		}
+
+		require.NoError(t, err)
+		diffs = append(diffs, dr)
+	}
+}
+
+func TestDiffIter(t *testing.T) {
+	ctx := context.Background()
+	kc, err := NewKeyComparator(types.Format_Default, mergeTestSchema(), SortKeyCol{Tag: 0})
+	require.NoError(t, err)
+
+	newConv := func() *KVToSqlRowConverter {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+		require.NoError(t, err)
+		return conv
+	}
+
+	fromKVs := mergeTestKVs(t,
+		[]interface{}{1, 10, "a"},
+		[]interface{}{2, 20, "b"},
+		[]interface{}{3, 30, "c"},
+		[]interface{}{4, 40, "d"},
+	)
+	toKVs := mergeTestKVs(t,
+		[]interface{}{2, 20, "b"},
+		[]interface{}{3, 31, "c"},
+		[]interface{}{4, 40, nil},
+		[]interface{}{5, 50, "e"},
+	)
+
+	newDiffIter := func() *DiffIter {
+		return NewDiffIter(ctx, kc, newConv(), newConv(), kvGetFuncForTuples(fromKVs...), kvGetFuncForTuples(toKVs...))
+	}
+
+	expected := []DiffRow{
+		{Type: DiffRemoved, From: sql.Row{int64(1), int64(10), "a"}},
+		// only a changed
+		{Type: DiffModified, From: sql.Row{int64(3), int64(30), "c"}, To: sql.Row{int64(3), int64(31), "c"}, ChangedTags: []uint64{1}},
+		{Type: DiffModified, From: sql.Row{int64(4), int64(40), "d"}, To: sql.Row{int64(4), int64(40), nil}, ChangedTags: []uint64{2}},
+		{Type: DiffAdded, To: sql.Row{int64(5), int64(50), "e"}},
+	}
+	assert.Equal(t, expected, drainDiffIter(t, newDiffIter()))
+
+	assert.Equal(t, []sql.Row{
+		{nil, nil, nil, int64(1), int64(10), "a", "removed"},
+		{int64(3), int64(31), "c", int64(3), int64(30), "c", "modified"},
+		{int64(4), int64(40), nil, int64(4), int64(40), "d", "modified"},
+		{int64(5), int64(50), "e", nil, nil, nil, "added"},
+	}, drainRowIter(t, newDiffIter()))
+
+	// identical maps have no differences
+	same := NewDiffIter(ctx, kc, newConv(), newConv(), kvGetFuncForTuples(fromKVs...), kvGetFuncForTuples(fromKVs...))
+	assert.Empty(t, drainDiffIter(t, same))
+}
+
+func TestDiffIterSchemaChange(t *testing.T) {
+	ctx := context.Background()
+	kc, err := NewKeyComparator(types.Format_Default, mergeTestSchema(), SortKeyCol{Tag: 0})
+	require.NoError(t, err)
+
+	// the new schema drops b and adds c
+	toCols := []schema.Column{mergeTestCols[0], mergeTestCols[1], schema.NewColumn("c", 3, types.StringKind, false)}
+	fromConv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+	toConv, err := NewKVToSqlRowConverterForCols(types.Format_Default, toCols)
+	require.NoError(t, err)
+
+	toKV := func(pk, a int, c types.Value) []types.Tuple {
+		k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(pk))
+		require.NoError(t, err)
+
+		vals := []types.Value{types.Uint(1), types.Int(a)}
+		if c != nil {
+			vals = append(vals, types.Uint(3), c)
+		}
+
+		v, err := types.NewTuple(types.Format_Default, vals...)
+		require.NoError(t, err)
+		return []types.Tuple{k, v}
+	}
+
+	fromKVs := mergeTestKVs(t,
+		[]interface{}{1, 10, "x"},
+		[]interface{}{2, 20, nil},
+		[]interface{}{3, 30, nil},
+	)
+
+	var toKVs []types.Tuple
+	toKVs = append(toKVs, toKV(1, 10, nil)...)
+	toKVs = append(toKVs, toKV(2, 20, nil)...)
+	toKVs = append(toKVs, toKV(3, 30, types.String("new"))...)
+
+	diffs := drainDiffIter(t, NewDiffIter(ctx, kc, fromConv, toConv, kvGetFuncForTuples(fromKVs...), kvGetFuncForTuples(toKVs...)))
+	assert.Equal(t, []DiffRow{
+		// the dropped column held a value
+		{Type: DiffModified, From: sql.Row{int64(1), int64(10), "x"}, To: sql.Row{int64(1), int64(10), nil}, ChangedTags: []uint64{2}},
+		// the added column holds a value
+		{Type: DiffModified, From: sql.Row{int64(3), int64(30), nil}, To: sql.Row{int64(3), int64(30), "new"}, ChangedTags: []uint64{3}},
+	}, diffs)
+}
@@ -17,9 +17,13 @@ package sqle
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"sort"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/shopspring/decimal"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/store/types"
@@ -33,6 +37,40 @@ func maxU64(x, y uint64) uint64 {
 	return y
 }
 
+// ValTupleEncoding describes the order in which tags are written to value tuples
+type ValTupleEncoding int
+
+const (
+	// UnknownValTupleEncoding is an unrecognized encoding
+	UnknownValTupleEncoding ValTupleEncoding = iota
+	// SortedTagsValTupleEncoding writes tags in ascending order, which allows reading a value tuple to stop once the
+	// largest tag being converted has been passed
+	SortedTagsValTupleEncoding
+	// UnsortedTagsValTupleEncoding writes tags in any order, so value tuples must be scanned in full
+	UnsortedTagsValTupleEncoding
+)
+
+// ValTupleEncodingForFormat returns the ValTupleEncoding used by tables written with the NomsBinFormat given.  A nil
+// format, which some callers pass when the format is not known, is treated as SortedTagsValTupleEncoding as that is
+// what all current formats use.
+func ValTupleEncodingForFormat(nbf *types.NomsBinFormat) (ValTupleEncoding, error) {
+	switch nbf {
+	case nil, types.Format_7_18, types.Format_LD_1:
+		return SortedTagsValTupleEncoding, nil
+	default:
+		return UnknownValTupleEncoding, errors.New("unrecognized value tuple encoding for format")
+	}
+}
+
+func validateValTupleEncoding(enc ValTupleEncoding) error {
+	switch enc {
+	case SortedTagsValTupleEncoding, UnsortedTagsValTupleEncoding:
+		return nil
+	default:
+		return fmt.Errorf("unrecognized value tuple encoding %d", enc)
+	}
+}
+
 // KVToSqlRowConverter takes noms types.Value key value pairs and converts them directly to a sql.Row.  It
 // can be configured to only process a portion of the columns and map columns to desired output columns.
 type KVToSqlRowConverter struct {
@@ -46,12 +84,70 @@ type KVToSqlRowConverter struct {
 	valsFromKey int
 	valsFromVal int
 	maxValTag   uint64
+	// valEncoding determines whether value tuples can be read with an early exit after maxValTag
+	valEncoding ValTupleEncoding
+	// valTransforms are applied to the values of the columns with the given tags after they are read
+	valTransforms map[uint64]valTransform
+	// timingHook, when not nil, is called with the time taken by every timingSampleEvery'th value conversion
+	timingHook        ConversionTimingHook
+	timingSampleEvery uint64
+	numConversions    uint64
+	// valReaders read the values of the columns with the given tags in place of the columns' TypeInfo
+	valReaders map[uint64]valReader
+	// drift, when not nil, records value tuple tags which are not in the table's schema
+	drift *valTupleDrift
+	// strategy is the approach used to read tuples, selected once all options have been applied
+	strategy DecodeStrategy
+	// intPKTag is the tag of the primary key column read by SingleIntPKDecodeStrategy
+	intPKTag uint64
+	// trimTrailingNulls causes NULLs at the end of each converted row to be removed
+	trimTrailingNulls bool
+	// checkTagOrder causes value tuples to be read in full verifying that their tags are strictly increasing
+	checkTagOrder bool
+	// masks are emitted in place of the values of the columns with the given tags, which are skipped without being read
+	masks map[uint64]interface{}
+	// missingZeros maps output indexes to the zero values written there when a column is missing from its tuple
+	missingZeros map[int]interface{}
+	// numOutputCols is the number of leading columns of each row which a DoltMapIter returns.  The columns after them
+	// are decoded only for its row predicates.
+	numOutputCols int
+	// keyPred, when not nil, is evaluated once the key columns are read and the value tuple is only read when it passes
+	keyPred RowPredicate
+	// keyBucket, when not nil, writes a bucket id computed from the key tuple to each row
+	keyBucket *keyBucket
+	// swapCheckPKTags, when not nil, are the primary key tags used to detect swapped key and value tuples
+	swapCheckPKTags map[uint64]struct{}
+	// parallelThreshold and parallelWorkers configure WithParallelDecode, and parallelDecode is set when value tuples are
+	// converted on parallelWorkers goroutines
+	parallelThreshold int
+	parallelWorkers   int
+	parallelDecode    bool
+	// computed are the computed columns of WithComputedColumns in the order they are filled
+	computed []ComputedColumn
+	// maxRowSize, when greater than 0, is the largest estimated row size in bytes of WithMaxRowSize
+	maxRowSize int64
 }
 
-func NewKVToSqlRowConverter(nbf *types.NomsBinFormat, tagToSqlColIdx map[uint64]int, cols []schema.Column, rowSize int) *KVToSqlRowConverter {
+// NewKVToSqlRowConverter returns a KVToSqlRowConverter that writes the value of each tag in tagToSqlColIdx to the
+// mapped index of the output row.  cols[i] must be the column written to index i.  An error is returned if an index
+// is negative, is not less than rowSize, has no matching column, or is mapped to by more than one tag, or if the value
+// tuple encoding of nbf is not recognized.
+func NewKVToSqlRowConverter(nbf *types.NomsBinFormat, tagToSqlColIdx map[uint64]int, cols []schema.Column, rowSize int, opts ...KVToSqlRowConverterOption) (*KVToSqlRowConverter, error) {
+	err := validateTagToSqlColIdx(tagToSqlColIdx, cols, rowSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	valEncoding, err := ValTupleEncodingForFormat(nbf)
+
+	if err != nil {
+		return nil, err
+	}
+
 	valsFromKey, valsFromVal, maxValTag := getValLocations(tagToSqlColIdx, cols)
 
-	return &KVToSqlRowConverter{
+	conv := &KVToSqlRowConverter{
 		nbf:            nbf,
 		cols:           cols,
 		tagToSqlColIdx: tagToSqlColIdx,
@@ -59,7 +155,43 @@ func NewKVToSqlRowConverter(nbf *types.NomsBinFormat, tagToSqlColIdx map[uint64]
 		valsFromKey:    valsFromKey,
 		valsFromVal:    valsFromVal,
 		maxValTag:      maxValTag,
+		valEncoding:    valEncoding,
+		numOutputCols:  rowSize,
 	}
+
+	for _, opt := range opts {
+		err = opt(conv)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	conv.strategy, conv.intPKTag = conv.selectDecodeStrategy()
+	conv.parallelDecode = conv.decodesValsInParallel()
+
+	return conv, nil
+}
+
+func validateTagToSqlColIdx(tagToSqlColIdx map[uint64]int, cols []schema.Column, rowSize int) error {
+	idxToTag := make(map[int]uint64, len(tagToSqlColIdx))
+	for tag, idx := range tagToSqlColIdx {
+		if idx < 0 || idx >= rowSize {
+			return fmt.Errorf("tag %d is mapped to index %d which is out of range for a row of size %d", tag, idx, rowSize)
+		}
+
+		if idx >= len(cols) || cols[idx].Tag != tag {
+			return fmt.Errorf("tag %d is mapped to index %d which does not hold the column with that tag", tag, idx)
+		}
+
+		if otherTag, ok := idxToTag[idx]; ok {
+			return fmt.Errorf("tags %d and %d are both mapped to index %d", otherTag, tag, idx)
+		}
+
+		idxToTag[idx] = tag
+	}
+
+	return nil
 }
 
 // get counts of where the values we want converted come from so we can skip entire tuples at times.
@@ -82,21 +214,262 @@ func getValLocations(tagToSqlColIdx map[uint64]int, cols []schema.Column) (int,
 }
 
 // NewKVToSqlRowConverterForCols returns a KVToSqlConverter instance based on the list of columns passed in
-func NewKVToSqlRowConverterForCols(nbf *types.NomsBinFormat, cols []schema.Column) *KVToSqlRowConverter {
+func NewKVToSqlRowConverterForCols(nbf *types.NomsBinFormat, cols []schema.Column, opts ...KVToSqlRowConverterOption) (*KVToSqlRowConverter, error) {
 	tagToSqlColIdx := make(map[uint64]int)
 	for i, col := range cols {
 		tagToSqlColIdx[col.Tag] = i
 	}
 
-	return NewKVToSqlRowConverter(nbf, tagToSqlColIdx, cols, len(cols))
+	return NewKVToSqlRowConverter(nbf, tagToSqlColIdx, cols, len(cols), opts...)
+}
+
+// TagToSqlColIdxForSqlSchema matches each column of sqlSch to the column of sch with the same name, ignoring case, and
+// returns the tag to output index mapping along with the columns in sqlSch order.  An error is returned if a column in
+// sqlSch has no match in sch.
+func TagToSqlColIdxForSqlSchema(sch schema.Schema, sqlSch sql.Schema) (map[uint64]int, []schema.Column, error) {
+	allCols := sch.GetAllCols()
+	tagToSqlColIdx := make(map[uint64]int, len(sqlSch))
+	cols := make([]schema.Column, len(sqlSch))
+	for i, sqlCol := range sqlSch {
+		col, ok := allCols.GetByNameCaseInsensitive(sqlCol.Name)
+
+		if !ok {
+			return nil, nil, fmt.Errorf("column '%s' in sql schema does not exist in the table schema", sqlCol.Name)
+		}
+
+		tagToSqlColIdx[col.Tag] = i
+		cols[i] = col
+	}
+
+	return tagToSqlColIdx, cols, nil
+}
+
+// NewKVToSqlRowConverterForSqlSchema returns a KVToSqlRowConverter whose output rows follow the column order of sqlSch
+// rather than the order of the columns in sch.
+func NewKVToSqlRowConverterForSqlSchema(nbf *types.NomsBinFormat, sch schema.Schema, sqlSch sql.Schema, opts ...KVToSqlRowConverterOption) (*KVToSqlRowConverter, error) {
+	tagToSqlColIdx, cols, err := TagToSqlColIdxForSqlSchema(sch, sqlSch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKVToSqlRowConverter(nbf, tagToSqlColIdx, cols, len(cols), opts...)
+}
+
+// SchemaConverterCompatible returns whether rows of schema a can be decoded by a KVToSqlRowConverter built for schema
+// b, along with a description of each difference between the two.  The schemas are compatible when every tag refers to
+// a column of the same type, in the same part of the key value pair, in both schemas.  Column names are not compared.
+func SchemaConverterCompatible(a, b schema.Schema) (bool, []string) {
+	aCols, bCols := a.GetAllCols(), b.GetAllCols()
+
+	tagSet := make(map[uint64]struct{}, aCols.Size()+bCols.Size())
+	for _, tag := range aCols.Tags {
+		tagSet[tag] = struct{}{}
+	}
+	for _, tag := range bCols.Tags {
+		tagSet[tag] = struct{}{}
+	}
+
+	tags := make([]uint64, 0, len(tagSet))
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	var diffs []string
+	for _, tag := range tags {
+		aCol, inA := aCols.GetByTag(tag)
+		bCol, inB := bCols.GetByTag(tag)
+
+		switch {
+		case !inA:
+			diffs = append(diffs, fmt.Sprintf("column '%s' with tag %d was added", bCol.Name, tag))
+		case !inB:
+			diffs = append(diffs, fmt.Sprintf("column '%s' with tag %d was removed", aCol.Name, tag))
+		case !aCol.TypeInfo.Equals(bCol.TypeInfo):
+			diffs = append(diffs, fmt.Sprintf("column '%s' with tag %d changed type from %s to %s", bCol.Name, tag, aCol.TypeInfo.String(), bCol.TypeInfo.String()))
+		case aCol.IsPartOfPK != bCol.IsPartOfPK:
+			diffs = append(diffs, fmt.Sprintf("column '%s' with tag %d moved between the primary key and the value", bCol.Name, tag))
+		}
+	}
+
+	return len(diffs) == 0, diffs
+}
+
+// ColumnMeta describes a column of the rows produced by a KVToSqlRowConverter
+type ColumnMeta struct {
+	Name       string
+	Tag        uint64
+	Type       sql.Type
+	Nullable   bool
+	PrimaryKey bool
+}
+
+// ColumnMetas returns a description of each column of the rows the converter produces, indexed by the position of the
+// column in the row.  Positions which no tag is mapped to are always nil and are described by the zero ColumnMeta.
+func (conv *KVToSqlRowConverter) ColumnMetas() []ColumnMeta {
+	metas := make([]ColumnMeta, conv.rowSize)
+	for tag, idx := range conv.tagToSqlColIdx {
+		col := conv.cols[idx]
+		metas[idx] = ColumnMeta{
+			Name:       col.Name,
+			Tag:        tag,
+			Type:       col.TypeInfo.ToSqlType(),
+			Nullable:   col.IsNullable(),
+			PrimaryKey: col.IsPartOfPK,
+		}
+	}
+
+	return metas
 }
 
 // ConvertKVToSqlRow returns a sql.Row generated from the key and value provided.
 func (conv *KVToSqlRowConverter) ConvertKVToSqlRow(k, v types.Value) (sql.Row, error) {
+	keyTup, valTup, err := conv.toTuples(k, v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return conv.ConvertKVTuplesToSqlRow(keyTup, valTup)
+}
+
+// Probe decodes the key and value provided in full, as ConvertKVToSqlRow does, and returns any error doing so while
+// discarding the row.  It is a validation entry point for callers about to stream a large table through the converter,
+// who can probe the first key and value to find a mismatch between the stored data and the columns being converted,
+// such as a value of the wrong kind or unexpected tags, and fail fast before committing to the full scan.  Options
+// which keep statistics, such as WithValTupleDriftCheck, count the probed row as they would any other.
+func (conv *KVToSqlRowConverter) Probe(k, v types.Value) error {
+	_, err := conv.ConvertKVToSqlRow(k, v)
+	return err
+}
+
+// ConvertKVToSqlRowWithSize returns a sql.Row generated from the key and value provided along with an estimate of the
+// number of bytes needed to hold the converted values in memory.  The size is accumulated as each value is read so no
+// additional pass over the row is needed.  See EstimateSqlRowSize for how values are measured.
+func (conv *KVToSqlRowConverter) ConvertKVToSqlRowWithSize(k, v types.Value) (sql.Row, int64, error) {
+	keyTup, valTup, err := conv.toTuples(k, v)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var size int64
+	r, err := conv.convertKVTuples(keyTup, valTup, &size)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r, size, nil
+}
+
+// ConvertKVToSqlRowWithPK returns a sql.Row generated from the key and value provided along with the values of the
+// primary key columns being converted, in primary key order, so that callers maintaining indexes or applying upserts
+// don't need to find the key columns within the row.  Primary key columns which are not being converted are left out of
+// pk.
+func (conv *KVToSqlRowConverter) ConvertKVToSqlRowWithPK(k, v types.Value) (sql.Row, []interface{}, error) {
+	keyTup, valTup, err := conv.toTuples(k, v)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := conv.convertKVTuples(keyTup, valTup, nil)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tupItr := types.TupleItrPool.Get().(*types.TupleIterator)
+	defer types.TupleItrPool.Put(tupItr)
+
+	err = tupItr.InitForTuple(keyTup)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nbf := keyTup.Format()
+	primReader, numPrimitives := tupItr.CodecReader()
+
+	pk := make([]interface{}, 0, conv.valsFromKey)
+	for pos := uint64(0); pos+1 < numPrimitives && len(pk) < conv.valsFromKey; pos += 2 {
+		if primReader.ReadKind() != types.UintKind {
+			return nil, nil, errors.New("Encountered unexpected kind while attempting to read tag")
+		}
+
+		tag := primReader.ReadUint()
+		err = primReader.SkipValue(nbf)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		col, ok := conv.colForTag(tag)
+
+		if !ok || !col.IsPartOfPK {
+			continue
+		}
+
+		idx := conv.tagToSqlColIdx[tag]
+
+		pk = append(pk, SqlRowValue(r, idx))
+	}
+
+	return r, pk, nil
+}
+
+// ConvertKVToSqlRowDelta returns a sql.Row generated from the key and value provided along with the tags of the
+// converted columns whose values differ from prev, which must be a row produced by this converter.  Values are compared
+// using the sql type of each column's TypeInfo, and a change to or from NULL counts as a difference.  A nil prev is
+// treated as a row of NULLs.  Changed tags are returned in output column order.
+func (conv *KVToSqlRowConverter) ConvertKVToSqlRowDelta(k, v types.Value, prev sql.Row) (sql.Row, []uint64, error) {
+	if prev != nil && (len(prev) > conv.rowSize || (!conv.trimTrailingNulls && len(prev) != conv.rowSize)) {
+		return nil, nil, fmt.Errorf("previous row has %d columns but rows of this converter have %d", len(prev), conv.rowSize)
+	}
+
+	r, err := conv.ConvertKVToSqlRow(k, v)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changedTags []uint64
+	for idx, col := range conv.cols {
+		if mappedIdx, ok := conv.tagToSqlColIdx[col.Tag]; !ok || mappedIdx != idx {
+			continue
+		}
+
+		prevVal, val := SqlRowValue(prev, idx), SqlRowValue(r, idx)
+
+		if prevVal == nil || val == nil {
+			if prevVal != nil || val != nil {
+				changedTags = append(changedTags, col.Tag)
+			}
+
+			continue
+		}
+
+		n, err := col.TypeInfo.ToSqlType().Compare(prevVal, val)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if n != 0 {
+			changedTags = append(changedTags, col.Tag)
+		}
+	}
+
+	return r, changedTags, nil
+}
+
+func (conv *KVToSqlRowConverter) toTuples(k, v types.Value) (types.Tuple, types.Tuple, error) {
 	keyTup, ok := k.(types.Tuple)
 
 	if !ok {
-		return nil, errors.New("invalid key is not a tuple")
+		return types.Tuple{}, types.Tuple{}, errors.New("invalid key is not a tuple")
 	}
 
 	var valTup types.Tuple
@@ -104,42 +477,127 @@ func (conv *KVToSqlRowConverter) ConvertKVToSqlRow(k, v types.Value) (sql.Row, e
 		valTup, ok = v.(types.Tuple)
 
 		if !ok {
-			return nil, errors.New("invalid value is not a tuple")
+			return types.Tuple{}, types.Tuple{}, errors.New("invalid value is not a tuple")
 		}
 	} else {
 		valTup = types.EmptyTuple(conv.nbf)
 	}
 
-	return conv.ConvertKVTuplesToSqlRow(keyTup, valTup)
+	return keyTup, valTup, nil
 }
 
 // ConvertKVToSqlRow returns a sql.Row generated from the key and value provided.
 func (conv *KVToSqlRowConverter) ConvertKVTuplesToSqlRow(k, v types.Tuple) (sql.Row, error) {
+	return conv.convertKVTuples(k, v, nil)
+}
+
+// convertKVTuples converts the key and value tuples to a sql.Row.  When size is not nil the estimated size of each
+// converted value is added to it.
+func (conv *KVToSqlRowConverter) convertKVTuples(k, v types.Tuple, size *int64) (sql.Row, error) {
+	cols := make([]interface{}, conv.rowSize)
+	err := conv.readKVTuples(cols, k, v, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if conv.trimTrailingNulls {
+		for len(cols) > 0 && cols[len(cols)-1] == nil {
+			cols = cols[:len(cols)-1]
+		}
+	}
+
+	return cols, nil
+}
+
+// readKVTuples reads the values being converted from the key and value tuples into cols, which must have a length of
+// at least the converter's row size.  Positions of cols which aren't read are left as they are, other than those of
+// columns configured with WithMissingValuesAsZero which are set to their zero value, the index of WithKeyBucket and
+// those of computed columns.  ErrFilteredByKey is returned, without reading the value tuple, if the key columns don't
+// pass the converter's key predicate, and an error wrapping ErrRowTooLarge if the row is larger than the maximum of
+// WithMaxRowSize.
+func (conv *KVToSqlRowConverter) readKVTuples(cols []interface{}, k, v types.Tuple, size *int64) error {
+	if conv.swapCheckPKTags != nil {
+		err := conv.checkSwapped(k, v)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for idx, zero := range conv.missingZeros {
+		cols[idx] = zero
+	}
+
 	tupItr := types.TupleItrPool.Get().(*types.TupleIterator)
 	defer types.TupleItrPool.Put(tupItr)
 
-	cols := make([]interface{}, conv.rowSize)
-	if conv.valsFromKey > 0 {
+	if conv.strategy == SingleIntPKDecodeStrategy {
+		err := conv.readIntPK(cols, k, tupItr, size)
+
+		if err != nil {
+			return err
+		}
+	} else if conv.valsFromKey > 0 {
 		// keys are not in sorted order so cannot use max tag to early exit
-		err := conv.processTuple(cols, conv.valsFromKey, 0xFFFFFFFFFFFFFFFF, k, tupItr)
+		err := conv.processTuple(cols, conv.valsFromKey, 0xFFFFFFFFFFFFFFFF, k, tupItr, size, false, false)
 
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	if conv.valsFromVal > 0 {
-		err := conv.processTuple(cols, conv.valsFromVal, conv.maxValTag, v, tupItr)
+	if conv.keyBucket != nil {
+		bucket, err := conv.keyBucket.bucketFor(k)
 
 		if err != nil {
-			return nil, err
+			return err
 		}
+
+		cols[conv.keyBucket.idx] = bucket
 	}
 
-	return cols, nil
+	if conv.keyPred != nil && !conv.keyPred(cols) {
+		return ErrFilteredByKey
+	}
+
+	checkOrder := conv.checkTagOrder && conv.valEncoding == SortedTagsValTupleEncoding
+	if conv.valsFromVal > 0 || conv.drift != nil || checkOrder {
+		maxTag := conv.maxValTag
+		if conv.valEncoding == UnsortedTagsValTupleEncoding {
+			maxTag = 0xFFFFFFFFFFFFFFFF
+		}
+
+		var err error
+		if conv.parallelDecode {
+			err = conv.processTupleParallel(cols, maxTag, v, tupItr, size)
+		} else {
+			err = conv.processTuple(cols, conv.valsFromVal, maxTag, v, tupItr, size, conv.drift != nil, checkOrder)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(conv.computed) > 0 {
+		err := conv.fillComputedColumns(cols, size)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if conv.maxRowSize > 0 {
+		return conv.checkRowSize(cols[:conv.rowSize])
+	}
+
+	return nil
 }
 
-func (conv *KVToSqlRowConverter) processTuple(cols []interface{}, valsToFill int, maxTag uint64, tup types.Tuple, tupItr *types.TupleIterator) error {
+// processTuple reads the values of the tags being converted from tup.  When checkDrift is true the whole tuple is read,
+// rather than stopping once every value is filled, and tags which are not in the schema are recorded.
+func (conv *KVToSqlRowConverter) processTuple(cols []interface{}, valsToFill int, maxTag uint64, tup types.Tuple, tupItr *types.TupleIterator, size *int64, checkDrift, checkOrder bool) error {
 	err := tupItr.InitForTuple(tup)
 
 	if err != nil {
@@ -149,9 +607,13 @@ func (conv *KVToSqlRowConverter) processTuple(cols []interface{}, valsToFill int
 	nbf := tup.Format()
 	primReader, numPrimitives := tupItr.CodecReader()
 
+	// every tag must be read to check for drift or to verify the tag order
+	readAll := checkDrift || checkOrder
+
 	filled := 0
+	var prevTag uint64
 	for pos := uint64(0); pos+1 < numPrimitives; pos += 2 {
-		if filled >= valsToFill {
+		if filled >= valsToFill && !readAll {
 			break
 		}
 
@@ -162,23 +624,71 @@ func (conv *KVToSqlRowConverter) processTuple(cols []interface{}, valsToFill int
 		}
 
 		tag64 := primReader.ReadUint()
-		if tag64 > maxTag {
+		if tag64 > maxTag && !readAll {
 			break
 		}
 
-		if sqlColIdx, ok := conv.tagToSqlColIdx[tag64]; !ok {
+		if checkOrder {
+			if pos > 0 && tag64 <= prevTag {
+				return fmt.Errorf("%w: tag %d at position %d follows tag %d", ErrValTupleTagsOutOfOrder, tag64, pos/2, prevTag)
+			}
+
+			prevTag = tag64
+		}
+
+		if checkDrift {
+			conv.drift.check(tag64)
+		}
+
+		if sqlColIdx, ok := conv.tagToSqlColIdx[tag64]; !ok || tag64 > maxTag || filled >= valsToFill {
+			err = primReader.SkipValue(nbf)
+
+			if err != nil {
+				return err
+			}
+		} else if mask, ok := conv.masks[tag64]; ok {
+			if primReader.PeekKind() != types.NullKind {
+				cols[sqlColIdx] = mask
+			} else {
+				cols[sqlColIdx] = nil
+			}
+
 			err = primReader.SkipValue(nbf)
 
 			if err != nil {
 				return err
 			}
+
+			if size != nil {
+				*size += estimateValSize(cols[sqlColIdx])
+			}
+
+			filled++
 		} else {
-			cols[sqlColIdx], err = conv.cols[sqlColIdx].TypeInfo.ReadFrom(nbf, primReader)
+			if readVal, ok := conv.valReaders[tag64]; ok {
+				cols[sqlColIdx], err = readVal(nbf, primReader)
+			} else if conv.timingHook == nil {
+				cols[sqlColIdx], err = conv.cols[sqlColIdx].TypeInfo.ReadFrom(nbf, primReader)
+			} else {
+				cols[sqlColIdx], err = conv.timedReadFrom(tag64, conv.cols[sqlColIdx], nbf, primReader)
+			}
 
 			if err != nil {
 				return err
 			}
 
+			if transform, ok := conv.valTransforms[tag64]; ok {
+				cols[sqlColIdx], err = transform(cols[sqlColIdx])
+
+				if err != nil {
+					return err
+				}
+			}
+
+			if size != nil {
+				*size += estimateValSize(cols[sqlColIdx])
+			}
+
 			filled++
 		}
 	}
@@ -186,6 +696,50 @@ func (conv *KVToSqlRowConverter) processTuple(cols []interface{}, valsToFill int
 	return nil
 }
 
+// defaultValSize is the size estimate used for values whose type does not have a more specific estimate
+const defaultValSize = 16
+
+// EstimateSqlRowSize returns an approximation of the number of bytes used by the values in a sql.Row.  Strings, byte
+// slices (which includes JSON values) and decimals are measured by their length, numeric types by their fixed size, and
+// nils are free.  Values of any other type are measured by the length of their String() form when they implement
+// fmt.Stringer, and otherwise count as defaultValSize bytes, so the estimate is a lower bound for rows holding such
+// values.  It does not account for the overhead of the row slice itself.
+func EstimateSqlRowSize(r sql.Row) int64 {
+	var size int64
+	for _, val := range r {
+		size += estimateValSize(val)
+	}
+
+	return size
+}
+
+func estimateValSize(val interface{}) int64 {
+	switch typedVal := val.(type) {
+	case nil:
+		return 0
+	case bool, int8, uint8:
+		return 1
+	case int16, uint16:
+		return 2
+	case int32, uint32, float32:
+		return 4
+	case int, uint, int64, uint64, float64:
+		return 8
+	case string:
+		return int64(len(typedVal))
+	case []byte:
+		return int64(len(typedVal))
+	case time.Time:
+		return 24
+	case decimal.Decimal:
+		return int64(len(typedVal.String()))
+	case fmt.Stringer:
+		return int64(len(typedVal.String()))
+	default:
+		return defaultValSize
+	}
+}
+
 // KVGetFunc defines a function that returns a Key Value pair
 type KVGetFunc func(ctx context.Context) (types.Tuple, types.Tuple, error)
 
@@ -215,6 +769,14 @@ type DoltMapIter struct {
 	kvGet         KVGetFunc
 	closeKVGetter func() error
 	conv          *KVToSqlRowConverter
+	// origConv is the converter the iterator was created with, which Reset restores after converter switches
+	origConv   *KVToSqlRowConverter
+	stats      []*columnStatsCollector
+	switches   []converterSwitch
+	predicates []RowPredicate
+	// lastKey is the key of the last row returned, from which Cursor is built
+	lastKey    types.Tuple
+	hasLastKey bool
 }
 
 // NewDoltMapIter returns a new DoltMapIter
@@ -224,18 +786,133 @@ func NewDoltMapIter(ctx context.Context, keyValGet KVGetFunc, closeKVGetter func
 		kvGet:         keyValGet,
 		closeKVGetter: closeKVGetter,
 		conv:          conv,
+		origConv:      conv,
 	}
 }
 
 // Next returns the next sql.Row until all rows are returned at which point (nil, io.EOF) is returned.
 func (dmi *DoltMapIter) Next() (sql.Row, error) {
-	k, v, err := dmi.kvGet(dmi.ctx)
+	_, _, r, err := dmi.nextRow()
+	return r, unwrapRowConversionError(err)
+}
+
+// rowConversionError is returned by nextRow when a key value pair was read but could not be converted.  The pair has
+// been consumed, so DoltMapIterSource can report the row and carry on reading.
+type rowConversionError struct {
+	err error
+}
+
+func (e rowConversionError) Error() string {
+	return e.err.Error()
+}
+
+// unwrapRowConversionError returns the conversion error wrapped by err if it is a rowConversionError, and err otherwise
+func unwrapRowConversionError(err error) error {
+	if convErr, ok := err.(rowConversionError); ok {
+		return convErr.err
+	}
+
+	return err
+}
+
+// nextRow reads and converts key value pairs until one whose row passes the iterator's row predicates is found, and
+// returns it along with the tuples it was converted from.  Errors converting a pair are returned as a
+// rowConversionError.
+func (dmi *DoltMapIter) nextRow() (types.Tuple, types.Tuple, sql.Row, error) {
+	for {
+		k, v, err := dmi.kvGet(dmi.ctx)
+
+		if err != nil {
+			return types.Tuple{}, types.Tuple{}, nil, err
+		}
+
+		if len(dmi.switches) > 0 {
+			err = dmi.switchConverter(k)
+
+			if err != nil {
+				return types.Tuple{}, types.Tuple{}, nil, err
+			}
+		}
+
+		r, err := dmi.conv.ConvertKVTuplesToSqlRow(k, v)
+
+		if err == ErrFilteredByKey {
+			continue
+		} else if err != nil {
+			return types.Tuple{}, types.Tuple{}, nil, rowConversionError{err}
+		}
+
+		if !dmi.passesRowPredicates(r) {
+			continue
+		}
+
+		if dmi.stats != nil {
+			err = dmi.updateStats(r)
+
+			if err != nil {
+				return types.Tuple{}, types.Tuple{}, nil, err
+			}
+		}
+
+		dmi.lastKey, dmi.hasLastKey = k, true
+		return k, v, dmi.conv.outputRow(r), nil
+	}
+}
+
+// AnyRow returns true if the source has another row, without returning it, which is all an EXISTS or semi-join probe
+// needs to know.  Rows are read as Next reads them, so key and row predicates, converter switches and statistics apply,
+// and the row found is consumed so a following call to Next returns the row after it.  false is returned once all rows
+// are returned.
+func (dmi *DoltMapIter) AnyRow() (bool, error) {
+	_, _, _, err := dmi.nextRow()
+
+	if err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, unwrapRowConversionError(err)
+	}
+
+	return true, nil
+}
+
+// NextWithRawTuples returns the next sql.Row along with the encoded bytes of the key and value tuples it was converted
+// from, which is useful when investigating a row that does not decode as expected.  The bytes are copies owned by the
+// caller and can be decoded with types.DecodeValue.  (nil, nil, nil, io.EOF) is returned once all rows are returned.
+func (dmi *DoltMapIter) NextWithRawTuples() (sql.Row, []byte, []byte, error) {
+	k, v, r, err := dmi.nextRow()
+
+	if err != nil {
+		return nil, nil, nil, unwrapRowConversionError(err)
+	}
+
+	keyBytes, err := encodedTupleBytes(k)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	valBytes, err := encodedTupleBytes(v)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return r, keyBytes, valBytes, nil
+}
+
+// encodedTupleBytes returns a copy of the serialized form of a tuple
+func encodedTupleBytes(tup types.Tuple) ([]byte, error) {
+	c, err := types.EncodeValue(tup, tup.Format())
 
 	if err != nil {
 		return nil, err
 	}
 
-	return dmi.conv.ConvertKVTuplesToSqlRow(k, v)
+	data := c.Data()
+	cp := make([]byte, len(data))
+	copy(cp, data)
+
+	return cp, nil
 }
 
 func (dmi *DoltMapIter) Close(*sql.Context) error {
@@ -245,3 +922,38 @@ func (dmi *DoltMapIter) Close(*sql.Context) error {
 
 	return nil
 }
+
+// Reset closes the current KVGetFunc, if it has a close function, and rebinds the iterator to newGet so that it can be
+// reused with the same converter.  Reset may be called at any point, including part way through an iteration, and the
+// next call to Next returns the first row of the new source.  If closing the current getter fails the error is
+// returned and the iterator is left bound to the current source.  Converter switches pending for the current source
+// are discarded, as are the position Cursor would return and the statistics collected so far, and the iterator goes
+// back to the converter it was created with if a switch has replaced it.
+func (dmi *DoltMapIter) Reset(newGet KVGetFunc, newClose func() error) error {
+	statIdxs, err := dmi.statIdxsForConv(dmi.origConv)
+
+	if err != nil {
+		return err
+	}
+
+	if dmi.closeKVGetter != nil {
+		err := dmi.closeKVGetter()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	dmi.kvGet = newGet
+	dmi.closeKVGetter = newClose
+	dmi.switches = nil
+	dmi.lastKey, dmi.hasLastKey = types.Tuple{}, false
+	dmi.conv = dmi.origConv
+
+	for i, c := range dmi.stats {
+		c.idx = statIdxs[i]
+		c.reset()
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const doltMapIterSourceName = "DoltMapIter source"
+
+// DoltMapIterSource reads every row from the DoltMapIter given, converts it to a row.Row of the schema provided, and
+// writes it to outChan.  It is meant to be run on its own goroutine as the first stage of a channel based pipeline such
+// as the one used by the fwt package.  outChan is closed once the iterator returns io.EOF, a read error occurs, or
+// stopChan is closed.  Rows are read with the iterator's Next, so its converter switches, row predicates and statistics
+// apply.  Rows that fail conversion are written to badRowChan and iteration continues, while read errors are written to
+// badRowChan and end iteration.
+func DoltMapIterSource(ctx context.Context, vrw types.ValueReadWriter, dmi *DoltMapIter, sch schema.Schema, outChan chan<- pipeline.RowWithProps, badRowChan chan<- *pipeline.TransformRowFailure, stopChan <-chan struct{}) {
+	defer close(outChan)
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		default:
+		}
+
+		_, _, sqlRow, err := dmi.nextRow()
+
+		if err == io.EOF {
+			return
+		} else if _, ok := err.(rowConversionError); ok {
+			sendBadRow(badRowChan, stopChan, &pipeline.TransformRowFailure{TransformName: doltMapIterSourceName, Details: err.Error()})
+			continue
+		} else if err != nil {
+			sendBadRow(badRowChan, stopChan, &pipeline.TransformRowFailure{TransformName: doltMapIterSourceName, Details: err.Error()})
+			return
+		}
+
+		r, err := sqlutil.SqlRowToDoltRow(ctx, vrw, sqlRow, sch)
+
+		if err != nil {
+			sendBadRow(badRowChan, stopChan, &pipeline.TransformRowFailure{TransformName: doltMapIterSourceName, Details: err.Error()})
+			continue
+		}
+
+		select {
+		case outChan <- pipeline.RowWithProps{Row: r, Props: pipeline.NoProps}:
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+func sendBadRow(badRowChan chan<- *pipeline.TransformRowFailure, stopChan <-chan struct{}, trf *pipeline.TransformRowFailure) {
+	select {
+	case badRowChan <- trf:
+	case <-stopChan:
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fwt"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// kvGetFuncForTuples returns a KVGetFunc that returns the key value pairs given in order followed by io.EOF
+func kvGetFuncForTuples(kvs ...types.Tuple) KVGetFunc {
+	pos := 0
+	return func(ctx context.Context) (types.Tuple, types.Tuple, error) {
+		if pos+1 >= len(kvs) {
+			return types.Tuple{}, types.Tuple{}, io.EOF
+		}
+
+		k, v := kvs[pos], kvs[pos+1]
+		pos += 2
+		return k, v, nil
+	}
+}
+
+func TestDoltMapIterSourceToFWT(t *testing.T) {
+	cols := []schema.Column{
+		schema.NewColumn("pk", 0, types.StringKind, true),
+		schema.NewColumn("val", 1, types.StringKind, false),
+	}
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(cols...))
+
+	var kvs []types.Tuple
+	for _, vals := range [][2]string{{"a", "12345"}, {"abc", "1"}} {
+		k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.String(vals[0]))
+		require.NoError(t, err)
+		v, err := types.NewTuple(types.Format_Default, types.Uint(1), types.String(vals[1]))
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+	require.NoError(t, err)
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+
+	srcChan := make(chan pipeline.RowWithProps)
+	outChan := make(chan pipeline.RowWithProps)
+	badRowChan := make(chan *pipeline.TransformRowFailure, 8)
+	stopChan := make(chan struct{})
+
+	go DoltMapIterSource(ctx, types.NewMemoryValueStore(), dmi, sch, srcChan, badRowChan, stopChan)
+	go func() {
+		defer close(outChan)
+		fwt.NewAutoSizingFWTTransformer(sch, fwt.PrintAllWhenTooLong, 100).TransformToFWT(srcChan, outChan, badRowChan, stopChan)
+	}()
+
+	var results [][2]string
+	for r := range outChan {
+		pk, _ := r.Row.GetColVal(0)
+		val, _ := r.Row.GetColVal(1)
+		results = append(results, [2]string{string(pk.(types.String)), string(val.(types.String))})
+	}
+
+	assert.Empty(t, badRowChan)
+	assert.Equal(t, [][2]string{{"a  ", "12345"}, {"abc", "1    "}}, results)
+}
+
+func TestDoltMapIterSourceReadsWithNext(t *testing.T) {
+	ctx := context.Background()
+	kvs := mergeTestKVs(t, []interface{}{1, 10, "a"}, []interface{}{2, 20, "b"}, []interface{}{3, 30, "c"})
+
+	// the value tuple of the second row has its tags out of order, so it fails conversion
+	badVal, err := types.NewTuple(types.Format_Default, types.Uint(2), types.String("b"), types.Uint(1), types.Int(20))
+	require.NoError(t, err)
+	kvs[3] = badVal
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols, WithValTupleTagOrderCheck())
+	require.NoError(t, err)
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	require.NoError(t, dmi.CollectStats(1))
+
+	outChan := make(chan pipeline.RowWithProps, 8)
+	badRowChan := make(chan *pipeline.TransformRowFailure, 8)
+	DoltMapIterSource(ctx, types.NewMemoryValueStore(), dmi, mergeTestSchema(), outChan, badRowChan, make(chan struct{}))
+
+	var pks []int64
+	for r := range outChan {
+		pk, _ := r.Row.GetColVal(0)
+		pks = append(pks, int64(pk.(types.Int)))
+	}
+
+	// the row which fails conversion is reported and the rows after it are still read
+	assert.Equal(t, []int64{1, 3}, pks)
+	assert.Len(t, badRowChan, 1)
+
+	stats := dmi.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(2), stats[0].RowCount)
+
+	cursor, err := dmi.Cursor()
+	require.NoError(t, err)
+	expected := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs[4:]...), nil, conv)
+	_, err = expected.Next()
+	require.NoError(t, err)
+	expectedCursor, err := expected.Cursor()
+	require.NoError(t, err)
+	assert.Equal(t, expectedCursor, cursor)
+}
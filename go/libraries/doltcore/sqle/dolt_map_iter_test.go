@@ -0,0 +1,551 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	mapIterPKTag uint64 = iota
+	mapIterNameTag
+	mapIterAgeTag
+	mapIterScoreTag
+	mapIterCreatedTag
+)
+
+var mapIterTestCols = []schema.Column{
+	schema.NewColumn("id", mapIterPKTag, types.IntKind, true),
+	schema.NewColumn("name", mapIterNameTag, types.StringKind, false),
+	schema.NewColumn("age", mapIterAgeTag, types.UintKind, false),
+	schema.NewColumn("score", mapIterScoreTag, types.FloatKind, false),
+	schema.NewColumn("created", mapIterCreatedTag, types.TimestampKind, false),
+}
+
+// mapIterTestTuples builds the key and value tuples for a row of the mapIterTestCols schema.  A nil value for a column
+// leaves the column out of the value tuple.
+func mapIterTestTuples(t *testing.T, id int64, vals ...types.Value) (types.Tuple, types.Tuple) {
+	keyTup, err := types.NewTuple(types.Format_Default, types.Uint(mapIterPKTag), types.Int(id))
+	require.NoError(t, err)
+
+	var taggedVals []types.Value
+	for i, val := range vals {
+		if val != nil {
+			taggedVals = append(taggedVals, types.Uint(mapIterTestCols[i+1].Tag), val)
+		}
+	}
+
+	valTup, err := types.NewTuple(types.Format_Default, taggedVals...)
+	require.NoError(t, err)
+
+	return keyTup, valTup
+}
+
+func TestConvertKVToSqlRowWithSize(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		vals         []types.Value
+		expectedRow  sql.Row
+		expectedSize int64
+	}{
+		{
+			name:         "key only",
+			expectedRow:  sql.Row{int64(1), nil, nil, nil, nil},
+			expectedSize: 8,
+		},
+		{
+			name:         "string",
+			vals:         []types.Value{types.String("hello")},
+			expectedRow:  sql.Row{int64(1), "hello", nil, nil, nil},
+			expectedSize: 8 + 5,
+		},
+		{
+			name:         "numerics",
+			vals:         []types.Value{nil, types.Uint(32), types.Float(1.5)},
+			expectedRow:  sql.Row{int64(1), nil, uint64(32), 1.5, nil},
+			expectedSize: 8 + 8 + 8,
+		},
+		{
+			name:         "all types",
+			vals:         []types.Value{types.String("abc"), types.Uint(32), types.Float(1.5), types.Timestamp(created)},
+			expectedRow:  sql.Row{int64(1), "abc", uint64(32), 1.5, created},
+			expectedSize: 8 + 3 + 8 + 8 + 24,
+		},
+	}
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k, v := mapIterTestTuples(t, 1, test.vals...)
+			r, size, err := conv.ConvertKVToSqlRowWithSize(k, v)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRow, r)
+			assert.Equal(t, test.expectedSize, size)
+		})
+	}
+}
+
+func TestNewKVToSqlRowConverterValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		tagToSqlColIdx map[uint64]int
+		rowSize        int
+		expectErr      bool
+	}{
+		{
+			name:           "valid",
+			tagToSqlColIdx: map[uint64]int{mapIterPKTag: 0, mapIterAgeTag: 2},
+			rowSize:        len(mapIterTestCols),
+		},
+		{
+			name:           "row larger than mapped columns",
+			tagToSqlColIdx: map[uint64]int{mapIterPKTag: 0},
+			rowSize:        len(mapIterTestCols) + 1,
+		},
+		{
+			name:           "index not less than row size",
+			tagToSqlColIdx: map[uint64]int{mapIterPKTag: 0, mapIterCreatedTag: 4},
+			rowSize:        4,
+			expectErr:      true,
+		},
+		{
+			name:           "negative index",
+			tagToSqlColIdx: map[uint64]int{mapIterPKTag: -1},
+			rowSize:        len(mapIterTestCols),
+			expectErr:      true,
+		},
+		{
+			name:           "two tags mapped to the same index",
+			tagToSqlColIdx: map[uint64]int{mapIterPKTag: 0, mapIterNameTag: 0},
+			rowSize:        len(mapIterTestCols),
+			expectErr:      true,
+		},
+		{
+			name:           "index holds a different column",
+			tagToSqlColIdx: map[uint64]int{mapIterNameTag: 2},
+			rowSize:        len(mapIterTestCols),
+			expectErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverter(types.Format_Default, test.tagToSqlColIdx, mapIterTestCols, test.rowSize)
+
+			if test.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, conv)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, conv)
+			}
+		})
+	}
+}
+
+func TestNewKVToSqlRowConverterForSqlSchema(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...))
+	k, v := mapIterTestTuples(t, 1, types.String("bill"), types.Uint(32))
+
+	tests := []struct {
+		name        string
+		sqlSch      sql.Schema
+		expectedRow sql.Row
+		expectErr   bool
+	}{
+		{
+			name:        "reordered",
+			sqlSch:      sql.Schema{{Name: "age"}, {Name: "id"}, {Name: "name"}},
+			expectedRow: sql.Row{uint64(32), int64(1), "bill"},
+		},
+		{
+			name:        "names differing in case",
+			sqlSch:      sql.Schema{{Name: "NAME"}, {Name: "Id"}},
+			expectedRow: sql.Row{"bill", int64(1)},
+		},
+		{
+			name:      "unmatched column",
+			sqlSch:    sql.Schema{{Name: "id"}, {Name: "nickname"}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverterForSqlSchema(types.Format_Default, sch, test.sqlSch)
+
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			r, err := conv.ConvertKVToSqlRow(k, v)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRow, r)
+		})
+	}
+}
+
+func TestConvertKVToSqlRowDelta(t *testing.T) {
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	prevK, prevV := mapIterTestTuples(t, 1, types.String("bill"), types.Uint(32), nil)
+	prev, err := conv.ConvertKVToSqlRow(prevK, prevV)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		vals         []types.Value
+		prev         sql.Row
+		expectedTags []uint64
+	}{
+		{
+			name:         "no changes",
+			vals:         []types.Value{types.String("bill"), types.Uint(32), nil},
+			prev:         prev,
+			expectedTags: nil,
+		},
+		{
+			name:         "partial change",
+			vals:         []types.Value{types.String("bill"), types.Uint(33), nil},
+			prev:         prev,
+			expectedTags: []uint64{mapIterAgeTag},
+		},
+		{
+			name:         "null transitions",
+			vals:         []types.Value{nil, types.Uint(32), types.Float(1.5)},
+			prev:         prev,
+			expectedTags: []uint64{mapIterNameTag, mapIterScoreTag},
+		},
+		{
+			name:         "no previous row",
+			vals:         []types.Value{types.String("bill"), nil, nil},
+			prev:         nil,
+			expectedTags: []uint64{mapIterPKTag, mapIterNameTag},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k, v := mapIterTestTuples(t, 1, test.vals...)
+			expectedRow, err := conv.ConvertKVToSqlRow(k, v)
+			require.NoError(t, err)
+
+			r, changedTags, err := conv.ConvertKVToSqlRowDelta(k, v, test.prev)
+			require.NoError(t, err)
+			assert.Equal(t, expectedRow, r)
+			assert.Equal(t, test.expectedTags, changedTags)
+		})
+	}
+
+	k, v := mapIterTestTuples(t, 1)
+	_, _, err = conv.ConvertKVToSqlRowDelta(k, v, sql.Row{int64(1)})
+	assert.Error(t, err)
+}
+
+func TestDoltMapIterReset(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	kvs1 := mergeTestKVs(t, []interface{}{1, 10, "a"}, []interface{}{2, 20, "b"})
+	kvs2 := mergeTestKVs(t, []interface{}{3, 30, "c"})
+
+	closed1 := 0
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs1...), func() error { closed1++; return nil }, conv)
+	require.NoError(t, dmi.CollectStats(1))
+	assert.Equal(t, []sql.Row{{int64(1), int64(10), "a"}, {int64(2), int64(20), "b"}}, drainRowIter(t, dmi))
+
+	closed2 := 0
+	require.NoError(t, dmi.Reset(kvGetFuncForTuples(kvs2...), func() error { closed2++; return nil }))
+	assert.Equal(t, 1, closed1)
+	assert.Equal(t, []sql.Row{{int64(3), int64(30), "c"}}, drainRowIter(t, dmi))
+
+	// statistics are collected afresh for the new source
+	stats := dmi.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, ColumnStats{Name: "a", Tag: 1, Min: int64(30), Max: int64(30), RowCount: 1, DistinctEstimate: 1}, stats[0])
+
+	// reset part way through an iteration
+	require.NoError(t, dmi.Reset(kvGetFuncForTuples(kvs1...), nil))
+	assert.Equal(t, 1, closed2)
+	r, err := dmi.Next()
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), int64(10), "a"}, r)
+
+	require.NoError(t, dmi.Reset(kvGetFuncForTuples(kvs1...), nil))
+	assert.Equal(t, []sql.Row{{int64(1), int64(10), "a"}, {int64(2), int64(20), "b"}}, drainRowIter(t, dmi))
+
+	closeErr := errors.New("close failed")
+	require.NoError(t, dmi.Reset(kvGetFuncForTuples(kvs2...), func() error { return closeErr }))
+	assert.Equal(t, closeErr, dmi.Reset(kvGetFuncForTuples(kvs1...), nil))
+	assert.Equal(t, []sql.Row{{int64(3), int64(30), "c"}}, drainRowIter(t, dmi))
+}
+
+func TestDoltMapIterNextWithRawTuples(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), mergeTestCols)
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t, []interface{}{1, 10, "a"}, []interface{}{2, 20, "b"})
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+
+	for i := 0; i < len(kvs); i += 2 {
+		r, keyBytes, valBytes, err := dmi.NextWithRawTuples()
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{int64(i/2 + 1), int64((i/2 + 1) * 10), string(rune('a' + i/2))}, r)
+
+		k, err := types.DecodeValue(chunks.NewChunk(keyBytes), vrw)
+		require.NoError(t, err)
+		assert.True(t, kvs[i].Equals(k))
+
+		v, err := types.DecodeValue(chunks.NewChunk(valBytes), vrw)
+		require.NoError(t, err)
+		assert.True(t, kvs[i+1].Equals(v))
+
+		// the bytes are not aliased to the tuples
+		for j := range keyBytes {
+			keyBytes[j] = 0
+		}
+
+		r, err = conv.ConvertKVTuplesToSqlRow(kvs[i], kvs[i+1])
+		require.NoError(t, err)
+		assert.Equal(t, int64(i/2+1), r[0])
+	}
+
+	_, _, _, err = dmi.NextWithRawTuples()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDoltMapIterAnyRow(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(), nil, conv)
+	anyRow, err := dmi.AnyRow()
+	require.NoError(t, err)
+	assert.False(t, anyRow)
+
+	kvs := mergeTestKVs(t, []interface{}{1, 10, "a"}, []interface{}{2, 20, "b"}, []interface{}{3, 30, "c"})
+	dmi = NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	anyRow, err = dmi.AnyRow()
+	require.NoError(t, err)
+	assert.True(t, anyRow)
+
+	// the probed row is consumed
+	r, err := dmi.Next()
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(2), int64(20), "b"}, r)
+
+	cursor, err := dmi.Cursor()
+	require.NoError(t, err)
+
+	// rows which don't pass the row predicates are not found
+	dmi.FilterRows(func(r sql.Row) bool {
+		return r[0].(int64) != 3
+	})
+	anyRow, err = dmi.AnyRow()
+	require.NoError(t, err)
+	assert.False(t, anyRow)
+
+	// nor are rows filtered out by a key predicate, and statistics and the cursor follow the row found
+	keyConv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols, WithKeyPredicate(func(r sql.Row) bool {
+		return r[0].(int64) >= 2
+	}))
+	require.NoError(t, err)
+	dmi = NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, keyConv)
+	require.NoError(t, dmi.CollectStats(1))
+	anyRow, err = dmi.AnyRow()
+	require.NoError(t, err)
+	assert.True(t, anyRow)
+	assert.Equal(t, uint64(1), dmi.Stats()[0].RowCount)
+	assert.Equal(t, int64(20), dmi.Stats()[0].Min)
+
+	probedCursor, err := dmi.Cursor()
+	require.NoError(t, err)
+	assert.Equal(t, cursor, probedCursor)
+
+	getErr := errors.New("read failed")
+	dmi = NewDoltMapIter(ctx, func(ctx context.Context) (types.Tuple, types.Tuple, error) {
+		return types.Tuple{}, types.Tuple{}, getErr
+	}, nil, conv)
+	_, err = dmi.AnyRow()
+	assert.Equal(t, getErr, err)
+}
+
+func TestColumnMetas(t *testing.T) {
+	nameCol, err := schema.NewColumnWithTypeInfo("name", mapIterNameTag, typeinfo.StringDefaultType, false, "", false, "", schema.NotNullConstraint{})
+	require.NoError(t, err)
+	cols := []schema.Column{mapIterTestCols[mapIterScoreTag], mapIterTestCols[mapIterPKTag], nameCol}
+	tagToSqlColIdx := map[uint64]int{mapIterScoreTag: 0, mapIterPKTag: 1, mapIterNameTag: 2}
+
+	conv, err := NewKVToSqlRowConverter(types.Format_Default, tagToSqlColIdx, cols, 4)
+	require.NoError(t, err)
+
+	metas := conv.ColumnMetas()
+	require.Len(t, metas, 4)
+	assert.Equal(t, ColumnMeta{Name: "score", Tag: mapIterScoreTag, Type: sql.Float64, Nullable: true}, metas[0])
+	assert.Equal(t, ColumnMeta{Name: "id", Tag: mapIterPKTag, Type: sql.Int64, Nullable: false, PrimaryKey: true}, metas[1])
+	assert.Equal(t, ColumnMeta{Name: "name", Tag: mapIterNameTag, Type: sql.LongText, Nullable: false}, metas[2])
+	assert.Equal(t, ColumnMeta{}, metas[3])
+}
+
+func TestSchemaConverterCompatible(t *testing.T) {
+	base := schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...))
+	withCols := func(cols ...schema.Column) schema.Schema {
+		return schema.MustSchemaFromCols(schema.NewColCollection(cols...))
+	}
+
+	tests := []struct {
+		name          string
+		other         schema.Schema
+		expectedDiffs []string
+	}{
+		{
+			name:  "same layout",
+			other: base,
+		},
+		{
+			name:  "renamed column",
+			other: withCols(mapIterTestCols[0], schema.NewColumn("full_name", mapIterNameTag, types.StringKind, false), mapIterTestCols[2], mapIterTestCols[3], mapIterTestCols[4]),
+		},
+		{
+			name:          "added column",
+			other:         withCols(append(append([]schema.Column{}, mapIterTestCols...), schema.NewColumn("email", 10, types.StringKind, false))...),
+			expectedDiffs: []string{"column 'email' with tag 10 was added"},
+		},
+		{
+			name:          "removed column",
+			other:         withCols(mapIterTestCols[0], mapIterTestCols[1], mapIterTestCols[2], mapIterTestCols[4]),
+			expectedDiffs: []string{"column 'score' with tag 3 was removed"},
+		},
+		{
+			name:          "retyped column",
+			other:         withCols(mapIterTestCols[0], mapIterTestCols[1], schema.NewColumn("age", mapIterAgeTag, types.IntKind, false), mapIterTestCols[3], mapIterTestCols[4]),
+			expectedDiffs: []string{"column 'age' with tag 2 changed type from Uint64 to Int64"},
+		},
+		{
+			name:          "column moved into key",
+			other:         withCols(mapIterTestCols[0], schema.NewColumn("name", mapIterNameTag, types.StringKind, true), mapIterTestCols[2], mapIterTestCols[3], mapIterTestCols[4]),
+			expectedDiffs: []string{"column 'name' with tag 1 moved between the primary key and the value"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			compatible, diffs := SchemaConverterCompatible(base, test.other)
+			assert.Equal(t, len(test.expectedDiffs) == 0, compatible)
+			assert.Equal(t, test.expectedDiffs, diffs)
+		})
+	}
+}
+
+func TestEstimateSqlRowSize(t *testing.T) {
+	r := sql.Row{nil, int8(1), int16(1), int32(1), float32(1), int64(1), "12345", []byte("123"), struct{}{}}
+	assert.Equal(t, int64(0+1+2+4+4+8+5+3+defaultValSize), EstimateSqlRowSize(r))
+
+	jsonDoc := []byte(`{"a": [1, 2, 3], "b": "a much longer string value than sixteen bytes"}`)
+	dec := decimal.RequireFromString("12345678901234567890.123456789")
+	r = sql.Row{jsonDoc, dec}
+	assert.Equal(t, int64(len(jsonDoc)+len(dec.String())), EstimateSqlRowSize(r))
+}
+
+func TestConvertKVToSqlRowWithPK(t *testing.T) {
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	k, v := mapIterTestTuples(t, 42, types.String("bill"), types.Uint(32))
+	r, pk, err := conv.ConvertKVToSqlRowWithPK(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(42), "bill", uint64(32), nil, nil}, r)
+	assert.Equal(t, []interface{}{int64(42)}, pk)
+
+	// the primary key columns of a composite key are returned in key order regardless of their order in the row
+	const (
+		regionTag uint64 = iota
+		idTag
+		nameTag
+	)
+	cols := []schema.Column{
+		schema.NewColumn("name", nameTag, types.StringKind, false),
+		schema.NewColumn("id", idTag, types.IntKind, true),
+		schema.NewColumn("region", regionTag, types.StringKind, true),
+	}
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+	require.NoError(t, err)
+
+	k, err = types.NewTuple(types.Format_Default, types.Uint(regionTag), types.String("us-west"), types.Uint(idTag), types.Int(7))
+	require.NoError(t, err)
+	v, err = types.NewTuple(types.Format_Default, types.Uint(nameTag), types.String("rob"))
+	require.NoError(t, err)
+
+	r, pk, err = conv.ConvertKVToSqlRowWithPK(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{"rob", int64(7), "us-west"}, r)
+	assert.Equal(t, []interface{}{"us-west", int64(7)}, pk)
+
+	// key columns which aren't converted are left out
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols[:2])
+	require.NoError(t, err)
+
+	r, pk, err = conv.ConvertKVToSqlRowWithPK(k, v)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{"rob", int64(7)}, r)
+	assert.Equal(t, []interface{}{int64(7)}, pk)
+}
+
+func TestConverterProbe(t *testing.T) {
+	k, v := mapIterTestTuples(t, 1, types.String("bill"), types.Uint(32))
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+	assert.NoError(t, conv.Probe(k, v))
+
+	// a converter for a target schema where age is a string doesn't match the stored data
+	mismatched := append([]schema.Column{}, mapIterTestCols...)
+	mismatched[2] = schema.NewColumn("age", mapIterAgeTag, types.StringKind, false)
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, mismatched)
+	require.NoError(t, err)
+
+	probeErr := conv.Probe(k, v)
+	require.Error(t, probeErr)
+
+	dmi := NewDoltMapIter(context.Background(), kvGetFuncForTuples(k, v), nil, conv)
+	_, err = dmi.Next()
+	require.Error(t, err)
+	assert.Equal(t, err.Error(), probeErr.Error())
+
+	assert.Error(t, conv.Probe(types.String("not a tuple"), v))
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// checkpointPerm is the permissions of export output and checkpoint files
+const checkpointPerm = 0644
+
+// CheckpointExt is the extension appended to the path of an export to get the path of its checkpoint file
+const CheckpointExt = ".checkpoint"
+
+// ErrNoCheckpoint is returned when resuming an export which has no checkpoint, because it never started or completed
+var ErrNoCheckpoint = errors.New("export has no checkpoint to resume from")
+
+// CheckpointPathForExport returns the path of the checkpoint file recorded for the export at exportPath
+func CheckpointPathForExport(exportPath string) string {
+	return exportPath + CheckpointExt
+}
+
+// ExportCheckpoint is the position of an interrupted export, recorded as JSON in its checkpoint file
+type ExportCheckpoint struct {
+	// Cursor is the cursor of the last row written, as returned by DoltMapIter.Cursor, or empty if no rows were written
+	Cursor string `json:"cursor"`
+	// Offset is the size of the output once the last row was written
+	Offset int64 `json:"offset"`
+	// Rows is the number of rows written
+	Rows uint64 `json:"rows"`
+}
+
+// CheckpointedExport writes the rows of a map to a file, recording a checkpoint every so many rows so that an export
+// which fails partway, including by the process crashing, can be resumed from the last checkpoint rather than
+// restarted.  The checkpoint file is removed once the export completes.
+type CheckpointedExport struct {
+	// FS is the filesystem the output and checkpoint files are written to, which is filesys.LocalFS when nil
+	FS filesys.Filesys
+	// Path is the path of the output file
+	Path string
+	// Every is the number of rows written between checkpoints
+	Every int
+	// WriteHeader, when not nil, writes the start of the output before the first row.  It isn't called on resume.
+	WriteHeader func(w io.Writer) error
+	// WriteRow writes a row to the output
+	WriteRow func(w io.Writer, r sql.Row) error
+}
+
+func (ce CheckpointedExport) fs() filesys.Filesys {
+	if ce.FS == nil {
+		return filesys.LocalFS
+	}
+
+	return ce.FS
+}
+
+// Run exports the rows of m, converted with conv, from the start, replacing any existing output and checkpoint.  The
+// checkpoint of a previous export to the same path is removed before anything is written, so it can't be resumed from
+// if this run fails before recording its own first checkpoint.  It returns the number of rows written.
+func (ce CheckpointedExport) Run(ctx context.Context, vrw types.ValueReadWriter, m types.Map, conv *KVToSqlRowConverter) (uint64, error) {
+	if ce.Every <= 0 {
+		return 0, fmt.Errorf("rows between checkpoints must be greater than 0 but was %d", ce.Every)
+	}
+
+	err := ce.removeCheckpoint()
+
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := ce.fs().OpenForWrite(ce.Path, checkpointPerm)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return ce.export(ctx, vrw, m, conv, f, ExportCheckpoint{}, ce.WriteHeader)
+}
+
+// Resume continues an interrupted export of the rows of m from its last checkpoint.  The output is truncated to the
+// size it had when the checkpoint was recorded, dropping anything written after it, and the rows following the
+// checkpoint's row are appended, so the output is the same as if the export had not been interrupted.  m must not have
+// changed since the export started.  ErrNoCheckpoint is returned if the export has no checkpoint, and the total number
+// of rows written by both runs is returned otherwise.
+func (ce CheckpointedExport) Resume(ctx context.Context, vrw types.ValueReadWriter, m types.Map, conv *KVToSqlRowConverter) (uint64, error) {
+	if ce.Every <= 0 {
+		return 0, fmt.Errorf("rows between checkpoints must be greater than 0 but was %d", ce.Every)
+	}
+
+	cpPath := CheckpointPathForExport(ce.Path)
+
+	if exists, isDir := ce.fs().Exists(cpPath); !exists || isDir {
+		return 0, ErrNoCheckpoint
+	}
+
+	data, err := ce.fs().ReadFile(cpPath)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var cp ExportCheckpoint
+	err = json.Unmarshal(data, &cp)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+
+	f, err := ce.fs().OpenForWriteAt(ce.Path, cp.Offset)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return ce.export(ctx, vrw, m, conv, f, cp, nil)
+}
+
+// export writes the rows of m following the row of cp to f, which is closed when done, starting with the header given
+func (ce CheckpointedExport) export(ctx context.Context, vrw types.ValueReadWriter, m types.Map, conv *KVToSqlRowConverter, f io.WriteCloser, cp ExportCheckpoint, writeHeader func(w io.Writer) error) (rows uint64, err error) {
+	defer func() {
+		closeErr := f.Close()
+
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	dmi, err := NewDoltMapIterFromCursor(ctx, vrw, m, cp.Cursor, conv)
+
+	if err != nil {
+		return 0, err
+	}
+
+	bwr := bufio.NewWriter(f)
+	wr := &countingWriter{wr: bwr, n: cp.Offset}
+
+	if writeHeader != nil {
+		err = writeHeader(wr)
+
+		if err != nil {
+			return 0, err
+		}
+
+		// a crash before the first row checkpoint resumes after the header
+		err = ce.checkpoint(f, bwr, cp.Cursor, wr.n, cp.Rows)
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	rows = cp.Rows
+	for {
+		r, err := dmi.Next()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return rows, err
+		}
+
+		err = ce.WriteRow(wr, r)
+
+		if err != nil {
+			return rows, err
+		}
+
+		rows++
+
+		if (rows-cp.Rows)%uint64(ce.Every) == 0 {
+			cursor, err := dmi.Cursor()
+
+			if err != nil {
+				return rows, err
+			}
+
+			err = ce.checkpoint(f, bwr, cursor, wr.n, rows)
+
+			if err != nil {
+				return rows, err
+			}
+		}
+	}
+
+	err = bwr.Flush()
+
+	if err != nil {
+		return rows, err
+	}
+
+	return rows, ce.removeCheckpoint()
+}
+
+// removeCheckpoint removes the checkpoint file of the export if there is one
+func (ce CheckpointedExport) removeCheckpoint() error {
+	cpPath := CheckpointPathForExport(ce.Path)
+
+	if exists, _ := ce.fs().Exists(cpPath); !exists {
+		return nil
+	}
+
+	return ce.fs().DeleteFile(cpPath)
+}
+
+// checkpoint makes what has been written to f durable, when its filesystem supports syncing, and then records a
+// checkpoint at its end.  The checkpoint file is replaced atomically so an interruption while recording it leaves the
+// previous checkpoint in place.
+func (ce CheckpointedExport) checkpoint(f io.Writer, bwr *bufio.Writer, cursor string, offset int64, rows uint64) error {
+	err := bwr.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	if syncer, ok := f.(interface{ Sync() error }); ok {
+		err = syncer.Sync()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(ExportCheckpoint{Cursor: cursor, Offset: offset, Rows: rows})
+
+	if err != nil {
+		return err
+	}
+
+	cpPath := CheckpointPathForExport(ce.Path)
+	wr, err := ce.fs().OpenForWrite(cpPath+".tmp", checkpointPerm)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = wr.Write(data)
+
+	if err != nil {
+		_ = wr.Close()
+		return err
+	}
+
+	err = wr.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return ce.fs().MoveFile(cpPath+".tmp", cpPath)
+}
+
+// countingWriter counts the bytes written through it, starting from n
+type countingWriter struct {
+	wr io.Writer
+	n  int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.wr.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCheckpointedExport(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), mergeTestCols)
+	require.NoError(t, err)
+
+	var rows [][]interface{}
+	var expected strings.Builder
+	expected.WriteString("pk,a,b\n")
+	for i := 1; i <= 10; i++ {
+		rows = append(rows, []interface{}{i, i * 10, string(rune('a' + i))})
+		expected.WriteString(fmt.Sprintf("%d,%d,%c\n", i, i*10, 'a'+i))
+	}
+
+	var kvs []types.Value
+	for _, tup := range mergeTestKVs(t, rows...) {
+		kvs = append(kvs, tup)
+	}
+	m, err := types.NewMap(ctx, vrw, kvs...)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "export_checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	errCrash := errors.New("crash")
+	crashAt := int64(0)
+	ce := CheckpointedExport{
+		Path:  filepath.Join(dir, "export.csv"),
+		Every: 3,
+		WriteHeader: func(w io.Writer) error {
+			_, err := io.WriteString(w, "pk,a,b\n")
+			return err
+		},
+		WriteRow: func(w io.Writer, r sql.Row) error {
+			if r[0].(int64) == crashAt {
+				return errCrash
+			}
+
+			_, err := fmt.Fprintf(w, "%d,%d,%s\n", r[0], r[1], r[2])
+			return err
+		},
+	}
+
+	t.Run("uninterrupted", func(t *testing.T) {
+		crashAt = 0
+		n, err := ce.Run(ctx, vrw, m, conv)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), n)
+		assertFileContents(t, ce.Path, expected.String())
+
+		_, err = os.Stat(CheckpointPathForExport(ce.Path))
+		assert.True(t, os.IsNotExist(err))
+
+		_, err = ce.Resume(ctx, vrw, m, conv)
+		assert.Equal(t, ErrNoCheckpoint, err)
+	})
+
+	t.Run("crash and resume", func(t *testing.T) {
+		crashAt = 8
+		n, err := ce.Run(ctx, vrw, m, conv)
+		assert.Equal(t, errCrash, err)
+		assert.Equal(t, uint64(7), n)
+
+		// rows 7 and part of 8 reached the file after the last checkpoint, at row 6, before the crash
+		f, err := os.OpenFile(ce.Path, os.O_APPEND|os.O_WRONLY, 0)
+		require.NoError(t, err)
+		_, err = io.WriteString(f, "7,70,h\n8,8")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		crashAt = 0
+		n, err = ce.Resume(ctx, vrw, m, conv)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), n)
+		assertFileContents(t, ce.Path, expected.String())
+
+		_, err = ce.Resume(ctx, vrw, m, conv)
+		assert.Equal(t, ErrNoCheckpoint, err)
+	})
+
+	t.Run("checkpoint permissions", func(t *testing.T) {
+		crashAt = 5
+		_, err := ce.Run(ctx, vrw, m, conv)
+		assert.Equal(t, errCrash, err)
+
+		info, err := os.Stat(CheckpointPathForExport(ce.Path))
+		require.NoError(t, err)
+		assert.Zero(t, info.Mode().Perm()&^0644)
+
+		crashAt = 0
+		_, err = ce.Resume(ctx, vrw, m, conv)
+		require.NoError(t, err)
+	})
+
+	t.Run("rerun removes stale checkpoint", func(t *testing.T) {
+		crashAt = 8
+		_, err := ce.Run(ctx, vrw, m, conv)
+		assert.Equal(t, errCrash, err)
+
+		// without a header, a rerun which crashes before its first checkpoint has recorded nothing to resume from
+		noHeader := ce
+		noHeader.WriteHeader = nil
+		crashAt = 2
+		_, err = noHeader.Run(ctx, vrw, m, conv)
+		assert.Equal(t, errCrash, err)
+
+		crashAt = 0
+		_, err = noHeader.Resume(ctx, vrw, m, conv)
+		assert.Equal(t, ErrNoCheckpoint, err)
+	})
+
+	t.Run("in memory filesystem", func(t *testing.T) {
+		memCE := ce
+		memCE.FS = filesys.EmptyInMemFS("/")
+		memCE.Path = "/export.csv"
+
+		crashAt = 8
+		_, err := memCE.Run(ctx, vrw, m, conv)
+		assert.Equal(t, errCrash, err)
+
+		crashAt = 0
+		n, err := memCE.Resume(ctx, vrw, m, conv)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), n)
+
+		data, err := memCE.FS.ReadFile(memCE.Path)
+		require.NoError(t, err)
+		assert.Equal(t, expected.String(), string(data))
+
+		exists, _ := memCE.FS.Exists(CheckpointPathForExport(memCE.Path))
+		assert.False(t, exists)
+	})
+
+	t.Run("crash before the first row checkpoint", func(t *testing.T) {
+		crashAt = 2
+		_, err := ce.Run(ctx, vrw, m, conv)
+		assert.Equal(t, errCrash, err)
+
+		crashAt = 0
+		n, err := ce.Resume(ctx, vrw, m, conv)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), n)
+		assertFileContents(t, ce.Path, expected.String())
+	})
+
+	t.Run("output shorter than checkpoint", func(t *testing.T) {
+		crashAt = 5
+		_, err := ce.Run(ctx, vrw, m, conv)
+		assert.Equal(t, errCrash, err)
+
+		require.NoError(t, ioutil.WriteFile(ce.Path, []byte("pk,a,b\n"), os.ModePerm))
+		crashAt = 0
+		_, err = ce.Resume(ctx, vrw, m, conv)
+		assert.Error(t, err)
+	})
+}
+
+func assertFileContents(t *testing.T, path, expected string) {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(data))
+}
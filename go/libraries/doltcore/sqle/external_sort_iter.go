@@ -0,0 +1,421 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultExternalSortRunSize is the default number of rows an ExternalSortIter holds in memory at once
+const DefaultExternalSortRunSize = 64 * 1024
+
+func init() {
+	// values which are not gob basic types must be registered to be encoded as interface values in spilled rows
+	gob.Register(time.Time{})
+	gob.Register(decimal.Decimal{})
+}
+
+// SortColumn is a column of the rows being sorted by an ExternalSortIter.  NULLs sort before all other values in
+// ascending order, and after them in descending order.
+type SortColumn struct {
+	// Idx is the index of the column in each row
+	Idx int
+	// Descending reverses the order of the column
+	Descending bool
+}
+
+// ExternalSortIter emits the rows of an inner sql.RowIter in the order of a list of sort columns.  At most runSize
+// rows are held in memory.  When the inner iterator returns more rows than that, each full buffer is sorted and
+// written to a temp file as a run and the runs are merged as rows are read.  The temp files are removed when the
+// iterator is closed or when sorting fails.
+type ExternalSortIter struct {
+	itr      sql.RowIter
+	sch      sql.Schema
+	sortCols []SortColumn
+	runSize  int
+	tmpDir   string
+
+	sorted bool
+	// rows holds the sorted rows when everything fit in a single run
+	rows   []sql.Row
+	rowIdx int
+	// runs holds the spilled runs when rows did not fit in a single run
+	runs  []*sortRun
+	merge *sortRunHeap
+}
+
+var _ sql.RowIter = (*ExternalSortIter)(nil)
+
+// NewExternalSortIter returns an ExternalSortIter which sorts the rows of itr, which are rows of the schema sch, by
+// sortCols.  Spilled runs are written to tmpDir, which should be the repo's scratch directory returned by
+// env.ScratchDir.  A runSize of 0 or less uses DefaultExternalSortRunSize.
+func NewExternalSortIter(itr sql.RowIter, sch sql.Schema, sortCols []SortColumn, runSize int, tmpDir string) (*ExternalSortIter, error) {
+	err := validateSortColumns(sch, sortCols)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if runSize <= 0 {
+		runSize = DefaultExternalSortRunSize
+	}
+
+	return &ExternalSortIter{
+		itr:      itr,
+		sch:      sch,
+		sortCols: sortCols,
+		runSize:  runSize,
+		tmpDir:   tmpDir,
+	}, nil
+}
+
+// Next returns the next row in sorted order until all rows are returned at which point (nil, io.EOF) is returned.  The
+// first call reads every row of the inner iterator.
+func (esi *ExternalSortIter) Next() (sql.Row, error) {
+	if !esi.sorted {
+		err := esi.sort()
+
+		if err != nil {
+			esi.removeRuns()
+			return nil, err
+		}
+
+		esi.sorted = true
+	}
+
+	if esi.merge == nil {
+		if esi.rowIdx >= len(esi.rows) {
+			return nil, io.EOF
+		}
+
+		r := esi.rows[esi.rowIdx]
+		esi.rows[esi.rowIdx] = nil
+		esi.rowIdx++
+
+		return r, nil
+	}
+
+	return esi.merge.next()
+}
+
+// sort reads every row of the inner iterator, spilling sorted runs as the buffer fills
+func (esi *ExternalSortIter) sort() error {
+	buf := make([]sql.Row, 0, esi.runSize)
+	for {
+		r, err := esi.itr.Next()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if len(buf) == esi.runSize {
+			err = esi.spill(buf)
+
+			if err != nil {
+				return err
+			}
+
+			buf = buf[:0]
+		}
+
+		buf = append(buf, r)
+	}
+
+	err := esi.sortRows(buf)
+
+	if err != nil {
+		return err
+	}
+
+	if len(esi.runs) == 0 {
+		esi.rows = buf
+		return nil
+	}
+
+	if len(buf) > 0 {
+		err = esi.spill(buf)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	esi.merge = &sortRunHeap{esi: esi}
+	for _, run := range esi.runs {
+		err = esi.merge.pushNext(run)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (esi *ExternalSortIter) sortRows(rows []sql.Row) error {
+	var err error
+	sort.SliceStable(rows, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+
+		var cmp int
+		cmp, err = esi.compare(rows[i], rows[j])
+		return cmp < 0
+	})
+
+	return err
+}
+
+// compare compares two rows by the sort columns
+func (esi *ExternalSortIter) compare(a, b sql.Row) (int, error) {
+	return compareSortRows(esi.sch, esi.sortCols, a, b)
+}
+
+// compareSortRows compares two rows of the schema given by a list of sort columns
+func compareSortRows(sch sql.Schema, sortCols []SortColumn, a, b sql.Row) (int, error) {
+	for _, sortCol := range sortCols {
+		aVal, bVal := a[sortCol.Idx], b[sortCol.Idx]
+
+		var cmp int
+		switch {
+		case aVal == nil && bVal == nil:
+			cmp = 0
+		case aVal == nil:
+			cmp = -1
+		case bVal == nil:
+			cmp = 1
+		default:
+			var err error
+			cmp, err = sch[sortCol.Idx].Type.Compare(aVal, bVal)
+
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if sortCol.Descending {
+			cmp = -cmp
+		}
+
+		if cmp != 0 {
+			return cmp, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// validateSortColumns returns an error if there are no sort columns or any is out of range for the schema given
+func validateSortColumns(sch sql.Schema, sortCols []SortColumn) error {
+	if len(sortCols) == 0 {
+		return fmt.Errorf("at least one sort column is required")
+	}
+
+	for _, sortCol := range sortCols {
+		if sortCol.Idx < 0 || sortCol.Idx >= len(sch) {
+			return fmt.Errorf("sort column index %d is out of range for a schema with %d columns", sortCol.Idx, len(sch))
+		}
+	}
+
+	return nil
+}
+
+// spill sorts the rows given and writes them to a new run
+func (esi *ExternalSortIter) spill(rows []sql.Row) error {
+	err := esi.sortRows(rows)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(esi.tmpDir, "sort_run_")
+
+	if err != nil {
+		return err
+	}
+
+	run := &sortRun{f: f, idx: len(esi.runs)}
+	esi.runs = append(esi.runs, run)
+
+	wr := bufio.NewWriter(f)
+	enc := gob.NewEncoder(wr)
+	for _, r := range rows {
+		err = enc.Encode(r)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	err = wr.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+
+	if err != nil {
+		return err
+	}
+
+	run.dec = gob.NewDecoder(bufio.NewReader(f))
+	return nil
+}
+
+// removeRuns closes and deletes the files of all spilled runs
+func (esi *ExternalSortIter) removeRuns() error {
+	var firstErr error
+	for _, run := range esi.runs {
+		err := run.f.Close()
+
+		if err == nil {
+			err = os.Remove(run.f.Name())
+		} else {
+			_ = os.Remove(run.f.Name())
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	esi.runs = nil
+	esi.merge = nil
+	esi.rows = nil
+
+	return firstErr
+}
+
+// Close closes the inner iterator and removes any spilled runs
+func (esi *ExternalSortIter) Close(ctx *sql.Context) error {
+	err := esi.itr.Close(ctx)
+	rmErr := esi.removeRuns()
+
+	if err != nil {
+		return err
+	}
+
+	return rmErr
+}
+
+// sortRun is a sorted run of rows spilled to a temp file
+type sortRun struct {
+	f   *os.File
+	dec *gob.Decoder
+	// idx is the position of the run, used to break ties so that equal rows keep the order they were read in
+	idx int
+}
+
+// sortRunHead is the next unread row of a run
+type sortRunHead struct {
+	r   sql.Row
+	run *sortRun
+}
+
+// sortRunHeap is a min heap of the next row of each run
+type sortRunHeap struct {
+	esi   *ExternalSortIter
+	heads []sortRunHead
+	err   error
+}
+
+var _ heap.Interface = (*sortRunHeap)(nil)
+
+func (h *sortRunHeap) Len() int {
+	return len(h.heads)
+}
+
+func (h *sortRunHeap) Less(i, j int) bool {
+	cmp, err := h.esi.compare(h.heads[i].r, h.heads[j].r)
+
+	if err != nil && h.err == nil {
+		h.err = err
+	}
+
+	if cmp == 0 {
+		return h.heads[i].run.idx < h.heads[j].run.idx
+	}
+
+	return cmp < 0
+}
+
+func (h *sortRunHeap) Swap(i, j int) {
+	h.heads[i], h.heads[j] = h.heads[j], h.heads[i]
+}
+
+func (h *sortRunHeap) Push(x interface{}) {
+	h.heads = append(h.heads, x.(sortRunHead))
+}
+
+func (h *sortRunHeap) Pop() interface{} {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
+
+// pushNext reads the next row of a run and adds it to the heap.  Runs which have been fully read are left out.
+func (h *sortRunHeap) pushNext(run *sortRun) error {
+	var r sql.Row
+	err := run.dec.Decode(&r)
+
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	heap.Push(h, sortRunHead{r, run})
+	return h.err
+}
+
+// next returns the smallest unread row across all runs
+func (h *sortRunHeap) next() (sql.Row, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	if len(h.heads) == 0 {
+		return nil, io.EOF
+	}
+
+	head := heap.Pop(h).(sortRunHead)
+
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	err := h.pushNext(head.run)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return head.r, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var externalSortSch = sql.Schema{
+	{Name: "id", Type: sql.Int64},
+	{Name: "name", Type: sql.Text, Nullable: true},
+	{Name: "created", Type: sql.Datetime},
+}
+
+func externalSortRows(n int) []sql.Row {
+	rng := rand.New(rand.NewSource(0))
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := make([]sql.Row, n)
+	for i := range rows {
+		var name interface{}
+		if i%10 != 0 {
+			name = fmt.Sprintf("name%03d", rng.Intn(100))
+		}
+
+		rows[i] = sql.Row{int64(i), name, start.Add(time.Duration(rng.Intn(1000)) * time.Hour)}
+	}
+
+	return rows
+}
+
+// errAfterIter returns the rows given followed by an error
+type errAfterIter struct {
+	sql.RowIter
+	err error
+}
+
+func (itr errAfterIter) Next() (sql.Row, error) {
+	r, err := itr.RowIter.Next()
+
+	if err == io.EOF {
+		return nil, itr.err
+	}
+
+	return r, err
+}
+
+func requireDirEmpty(t *testing.T, dir string) {
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestExternalSortIter(t *testing.T) {
+	const numRows = 1000
+	rows := externalSortRows(numRows)
+
+	tests := []struct {
+		name     string
+		sortCols []SortColumn
+		runSize  int
+		less     func(a, b sql.Row) bool
+	}{
+		{
+			name:     "in memory",
+			sortCols: []SortColumn{{Idx: 2}},
+			runSize:  numRows,
+			less: func(a, b sql.Row) bool {
+				return a[2].(time.Time).Before(b[2].(time.Time))
+			},
+		},
+		{
+			name:     "spilled",
+			sortCols: []SortColumn{{Idx: 2}},
+			runSize:  64,
+			less: func(a, b sql.Row) bool {
+				return a[2].(time.Time).Before(b[2].(time.Time))
+			},
+		},
+		{
+			name:     "spilled descending with nulls",
+			sortCols: []SortColumn{{Idx: 1, Descending: true}, {Idx: 0}},
+			runSize:  64,
+			less: func(a, b sql.Row) bool {
+				if a[1] == nil || b[1] == nil {
+					return a[1] != nil && b[1] == nil || a[1] == nil && b[1] == nil && a[0].(int64) < b[0].(int64)
+				}
+
+				if a[1] != b[1] {
+					return a[1].(string) > b[1].(string)
+				}
+
+				return a[0].(int64) < b[0].(int64)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "external_sort")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpDir)
+
+			itr, err := NewExternalSortIter(sql.RowsToRowIter(rows...), externalSortSch, test.sortCols, test.runSize, tmpDir)
+			require.NoError(t, err)
+
+			var prev sql.Row
+			count := 0
+			for {
+				r, err := itr.Next()
+
+				if err == io.EOF {
+					break
+				}
+
+				require.NoError(t, err)
+
+				if prev != nil {
+					require.False(t, test.less(r, prev), "%v emitted after %v", r, prev)
+				}
+
+				prev = r
+				count++
+			}
+
+			assert.Equal(t, numRows, count)
+
+			if test.runSize < numRows {
+				files, err := ioutil.ReadDir(tmpDir)
+				require.NoError(t, err)
+				assert.Len(t, files, (numRows+test.runSize-1)/test.runSize)
+			}
+
+			require.NoError(t, itr.Close(sql.NewEmptyContext()))
+			requireDirEmpty(t, tmpDir)
+		})
+	}
+
+	t.Run("inner error removes runs", func(t *testing.T) {
+		tmpDir, err := ioutil.TempDir("", "external_sort")
+		require.NoError(t, err)
+		defer os.RemoveAll(tmpDir)
+
+		innerErr := errors.New("read failed")
+		inner := errAfterIter{sql.RowsToRowIter(rows...), innerErr}
+		itr, err := NewExternalSortIter(inner, externalSortSch, []SortColumn{{Idx: 0}}, 64, tmpDir)
+		require.NoError(t, err)
+
+		_, err = itr.Next()
+		assert.Equal(t, innerErr, err)
+		requireDirEmpty(t, tmpDir)
+		require.NoError(t, itr.Close(sql.NewEmptyContext()))
+	})
+
+	t.Run("invalid sort column", func(t *testing.T) {
+		_, err := NewExternalSortIter(sql.RowsToRowIter(), externalSortSch, []SortColumn{{Idx: 3}}, 0, "")
+		assert.Error(t, err)
+		_, err = NewExternalSortIter(sql.RowsToRowIter(), externalSortSch, nil, 0, "")
+		assert.Error(t, err)
+	})
+}
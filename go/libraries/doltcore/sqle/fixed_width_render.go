@@ -0,0 +1,229 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fwt"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/nullprinter"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/tabular"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DefaultFixedWidthSamples is the number of rows RenderFixedWidth samples to size columns unless told otherwise
+const DefaultFixedWidthSamples = 100
+
+// FixedWidthOption is a function which configures RenderFixedWidth
+type FixedWidthOption func(*fixedWidthRenderer)
+
+// WithFixedWidthSamples sets the number of rows, including the header, sampled to determine the width of each column.
+// Values of rows after the samples which don't fit are handled by the TooLongBehavior.  0 samples every row.
+func WithFixedWidthSamples(n int) FixedWidthOption {
+	return func(fwr *fixedWidthRenderer) {
+		fwr.numSamples = n
+	}
+}
+
+// WithFixedWidthTooLongBehavior sets the behavior for values wider than their column.  The default is
+// fwt.PrintAllWhenTooLong.
+func WithFixedWidthTooLongBehavior(bhv fwt.TooLongBehavior) FixedWidthOption {
+	return func(fwr *fixedWidthRenderer) {
+		fwr.tooLngBhv = bhv
+	}
+}
+
+// fixedWidthRenderer holds the state of a single call to RenderFixedWidth
+type fixedWidthRenderer struct {
+	numSamples int
+	tooLngBhv  fwt.TooLongBehavior
+	cols       *schema.ColCollection
+	strSch     schema.Schema
+	nbf        *types.NomsBinFormat
+	// A map of column tag to max print width
+	printWidths map[uint64]int
+	// A map of column tag to max number of runes
+	maxRunes map[uint64]int
+}
+
+// RenderFixedWidth writes the rows of iter to w as a fixed width text table with the columns of sch, which must be
+// the columns of the rows iter returns in order.  It produces the same table as sending the rows through
+// DoltMapIterSource, an fwt.AutoSizingFWTTransformer and a tabular.TextTableWriter, sampling rows to size the columns
+// and then streaming the rest, but does so on the calling goroutine without channels.  NULL values are printed as
+// NULL, and other values as their SQL string.  The iterator is read to the end but not closed.
+func RenderFixedWidth(w io.Writer, iter *DoltMapIter, sch schema.Schema, opts ...FixedWidthOption) error {
+	strSch, err := untyped.UntypeUnkeySchema(sch)
+
+	if err != nil {
+		return err
+	}
+
+	fwr := &fixedWidthRenderer{
+		numSamples:  DefaultFixedWidthSamples,
+		tooLngBhv:   fwt.PrintAllWhenTooLong,
+		cols:        sch.GetAllCols(),
+		strSch:      strSch,
+		nbf:         iter.conv.nbf,
+		printWidths: make(map[uint64]int, sch.GetAllCols().Size()),
+		maxRunes:    make(map[uint64]int, sch.GetAllCols().Size()),
+	}
+
+	for _, opt := range opts {
+		opt(fwr)
+	}
+
+	header := make(row.TaggedValues, fwr.cols.Size())
+	for _, col := range fwr.cols.GetColumns() {
+		header[col.Tag] = types.String(col.Name)
+	}
+
+	headerRow, err := row.New(fwr.nbf, strSch, header)
+
+	if err != nil {
+		return err
+	}
+
+	fwr.measureRow(headerRow)
+	samples := []row.Row{headerRow}
+
+	var iterErr error
+	for fwr.numSamples <= 0 || len(samples) < fwr.numSamples {
+		var r row.Row
+		r, iterErr = fwr.nextRow(iter)
+
+		if iterErr != nil {
+			break
+		}
+
+		fwr.measureRow(r)
+		samples = append(samples, r)
+	}
+
+	if iterErr != nil && iterErr != io.EOF {
+		return iterErr
+	}
+
+	ctx := context.Background()
+	ttw, err := tabular.NewTextTableWriter(iohelp.NopWrCloser(w), strSch)
+
+	if err != nil {
+		return err
+	}
+
+	fwf := fwt.FixedWidthFormatterForSchema(strSch, fwr.tooLngBhv, fwr.printWidths, fwr.maxRunes)
+	writeRow := func(r row.Row) error {
+		formatted, err := fwf.FormatRow(r, strSch)
+
+		if err != nil {
+			return err
+		}
+
+		return ttw.WriteRow(ctx, formatted)
+	}
+
+	for _, r := range samples {
+		if err := writeRow(r); err != nil {
+			return err
+		}
+	}
+
+	for iterErr == nil {
+		var r row.Row
+		r, iterErr = fwr.nextRow(iter)
+
+		if iterErr == nil {
+			iterErr = writeRow(r)
+		}
+	}
+
+	if iterErr != io.EOF {
+		return iterErr
+	}
+
+	return ttw.Close(ctx)
+}
+
+// nextRow reads the next row from iter and converts its values to strings
+func (fwr *fixedWidthRenderer) nextRow(iter *DoltMapIter) (row.Row, error) {
+	sqlRow, err := iter.Next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sqlRow) != fwr.cols.Size() {
+		return nil, fmt.Errorf("row has %d values but the schema has %d columns", len(sqlRow), fwr.cols.Size())
+	}
+
+	taggedVals := make(row.TaggedValues, len(sqlRow))
+	for i, col := range fwr.cols.GetColumns() {
+		str, err := sqlValToString(col, sqlRow[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		taggedVals[col.Tag] = types.String(str)
+	}
+
+	return row.New(fwr.nbf, fwr.strSch, taggedVals)
+}
+
+// measureRow updates the maximum print widths and rune counts of each column with the values of the row given in the
+// same way the fwt.AutoSizingFWTTransformer does
+func (fwr *fixedWidthRenderer) measureRow(r row.Row) {
+	for _, tag := range fwr.cols.Tags {
+		val, ok := r.GetColVal(tag)
+
+		if !ok {
+			continue
+		}
+
+		str := string(val.(types.String))
+
+		if printWidth := fwt.StringWidth(str); printWidth > fwr.printWidths[tag] {
+			fwr.printWidths[tag] = printWidth
+		}
+
+		if numRunes := len([]rune(str)); numRunes > fwr.maxRunes[tag] {
+			fwr.maxRunes[tag] = numRunes
+		}
+	}
+}
+
+// sqlValToString returns the string printed for a value of the column given
+func sqlValToString(col schema.Column, val interface{}) (string, error) {
+	if val == nil {
+		return nullprinter.PrintedNull, nil
+	}
+
+	if str, ok := val.(string); ok {
+		return str, nil
+	}
+
+	sqlVal, err := col.TypeInfo.ToSqlType().SQL(val)
+
+	if err != nil {
+		return "", err
+	}
+
+	return sqlVal.ToString(), nil
+}
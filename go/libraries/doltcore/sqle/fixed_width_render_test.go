@@ -0,0 +1,127 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fwt"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/tabular"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRenderFixedWidthMatchesPipeline(t *testing.T) {
+	cols := []schema.Column{
+		schema.NewColumn("pk", 0, types.StringKind, true),
+		schema.NewColumn("value", 1, types.StringKind, false),
+	}
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(cols...))
+
+	var kvs []types.Tuple
+	for _, vals := range [][2]string{{"a", "12345"}, {"abc", "1"}, {"b", "日本語"}, {"a much longer key", "x"}} {
+		k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.String(vals[0]))
+		require.NoError(t, err)
+		v, err := types.NewTuple(types.Format_Default, types.Uint(1), types.String(vals[1]))
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	for _, numSamples := range []int{0, 2, 100} {
+		expected := renderFixedWidthWithPipeline(t, sch, cols, kvs, numSamples)
+
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+		require.NoError(t, err)
+		dmi := NewDoltMapIter(context.Background(), kvGetFuncForTuples(kvs...), nil, conv)
+
+		var buf bytes.Buffer
+		err = RenderFixedWidth(&buf, dmi, sch, WithFixedWidthSamples(numSamples))
+		require.NoError(t, err)
+		assert.Equal(t, expected, buf.String(), "%d samples", numSamples)
+	}
+}
+
+func TestRenderFixedWidthNulls(t *testing.T) {
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols[:3])
+	require.NoError(t, err)
+	k, v := mapIterTestTuples(t, 1, types.String("bill"), nil)
+	dmi := NewDoltMapIter(context.Background(), kvGetFuncForTuples(k, v), nil, conv)
+
+	var buf bytes.Buffer
+	err = RenderFixedWidth(&buf, dmi, schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols[:3]...)))
+	require.NoError(t, err)
+
+	expected := "" +
+		"+----+------+------+\n" +
+		"| id | name | age  |\n" +
+		"+----+------+------+\n" +
+		"| 1  | bill | NULL |\n" +
+		"+----+------+------+\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+// renderFixedWidthWithPipeline renders the key value pairs given through the channel based fwt pipeline
+func renderFixedWidthWithPipeline(t *testing.T, sch schema.Schema, cols []schema.Column, kvs []types.Tuple, numSamples int) string {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+	require.NoError(t, err)
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+
+	header := make(row.TaggedValues)
+	for _, col := range cols {
+		header[col.Tag] = types.String(col.Name)
+	}
+	headerRow, err := row.New(types.Format_Default, sch, header)
+	require.NoError(t, err)
+
+	rowChan := make(chan pipeline.RowWithProps)
+	srcChan := make(chan pipeline.RowWithProps)
+	outChan := make(chan pipeline.RowWithProps)
+	badRowChan := make(chan *pipeline.TransformRowFailure, 8)
+	stopChan := make(chan struct{})
+
+	go DoltMapIterSource(ctx, types.NewMemoryValueStore(), dmi, sch, rowChan, badRowChan, stopChan)
+	go func() {
+		defer close(srcChan)
+		srcChan <- pipeline.RowWithProps{Row: headerRow, Props: pipeline.NoProps}
+		for r := range rowChan {
+			srcChan <- r
+		}
+	}()
+	go func() {
+		defer close(outChan)
+		fwt.NewAutoSizingFWTTransformer(sch, fwt.PrintAllWhenTooLong, numSamples).TransformToFWT(srcChan, outChan, badRowChan, stopChan)
+	}()
+
+	var buf bytes.Buffer
+	ttw, err := tabular.NewTextTableWriter(iohelp.NopWrCloser(&buf), sch)
+	require.NoError(t, err)
+
+	for r := range outChan {
+		require.NoError(t, ttw.WriteRowWithProps(ctx, r.Row, r.Props))
+	}
+
+	require.NoError(t, ttw.Close(ctx))
+	assert.Empty(t, badRowChan)
+	return buf.String()
+}
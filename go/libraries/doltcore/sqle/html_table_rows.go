@@ -0,0 +1,163 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// HTMLTableOption is a function which configures an HTMLTableRowWriter
+type HTMLTableOption func(*HTMLTableRowWriter)
+
+// WithHTMLNull sets the text of the cells of NULL values, which is escaped like any other value.  By default NULLs are
+// written as empty cells.
+func WithHTMLNull(null string) HTMLTableOption {
+	return func(hw *HTMLTableRowWriter) {
+		hw.null = html.EscapeString(null)
+	}
+}
+
+// HTMLTableRowWriter writes the rows produced by a KVToSqlRowConverter to an io.Writer as an HTML <table> for reports.
+// The <thead> holds the names of the converted columns and each row is written as a <tr> of the <tbody> as soon as it
+// is given, so the document is never held in memory.  Values are written as their SQL strings and, along with the
+// column names, are HTML escaped.  Close must be called to end the table.
+type HTMLTableRowWriter struct {
+	wr            io.Writer
+	cols          []*schema.Column
+	null          string
+	headerWritten bool
+	buf           []byte
+}
+
+// NewHTMLTableRowWriter returns an HTMLTableRowWriter which writes the rows produced by conv to wr.  Positions in the
+// rows which no column maps to are left out of the table.
+func NewHTMLTableRowWriter(wr io.Writer, conv *KVToSqlRowConverter, opts ...HTMLTableOption) *HTMLTableRowWriter {
+	hw := &HTMLTableRowWriter{
+		wr:   wr,
+		cols: make([]*schema.Column, conv.rowSize),
+	}
+
+	for _, idx := range conv.tagToSqlColIdx {
+		hw.cols[idx] = &conv.cols[idx]
+	}
+
+	for _, opt := range opts {
+		opt(hw)
+	}
+
+	return hw
+}
+
+// writeHeader writes the start of the table and its <thead> if they have not been written yet
+func (hw *HTMLTableRowWriter) writeHeader() error {
+	if hw.headerWritten {
+		return nil
+	}
+
+	hw.headerWritten = true
+	hw.buf = append(hw.buf[:0], "<table>\n<thead>\n<tr>"...)
+	for _, col := range hw.cols {
+		if col == nil {
+			continue
+		}
+
+		hw.buf = append(hw.buf, "<th>"...)
+		hw.buf = append(hw.buf, html.EscapeString(col.Name)...)
+		hw.buf = append(hw.buf, "</th>"...)
+	}
+
+	hw.buf = append(hw.buf, "</tr>\n</thead>\n<tbody>\n"...)
+	_, err := hw.wr.Write(hw.buf)
+	return err
+}
+
+// WriteRow writes the row given as a row of the table.  The row must have been produced by the converter the writer
+// was created with.
+func (hw *HTMLTableRowWriter) WriteRow(ctx context.Context, r sql.Row) error {
+	err := hw.writeHeader()
+
+	if err != nil {
+		return err
+	}
+
+	hw.buf = append(hw.buf[:0], "<tr>"...)
+	for idx, col := range hw.cols {
+		if col == nil {
+			continue
+		}
+
+		val := SqlRowValue(r, idx)
+
+		hw.buf = append(hw.buf, "<td>"...)
+		if val == nil {
+			hw.buf = append(hw.buf, hw.null...)
+		} else {
+			str, err := sqlValToString(*col, val)
+
+			if err != nil {
+				return fmt.Errorf("failed to write column %s: %w", col.Name, err)
+			}
+
+			hw.buf = append(hw.buf, html.EscapeString(str)...)
+		}
+
+		hw.buf = append(hw.buf, "</td>"...)
+	}
+
+	hw.buf = append(hw.buf, "</tr>\n"...)
+	_, err = hw.wr.Write(hw.buf)
+	return err
+}
+
+// WriteRows writes every row of itr, returning the number of rows written.  The iterator is not closed.
+func (hw *HTMLTableRowWriter) WriteRows(ctx *sql.Context, itr sql.RowIter) (int64, error) {
+	var numRows int64
+	for {
+		r, err := itr.Next()
+
+		if err == io.EOF {
+			return numRows, nil
+		} else if err != nil {
+			return numRows, err
+		}
+
+		err = hw.WriteRow(ctx, r)
+
+		if err != nil {
+			return numRows, err
+		}
+
+		numRows++
+	}
+}
+
+// Close ends the table, writing its header first if no rows were written.  The underlying writer is not closed.
+func (hw *HTMLTableRowWriter) Close() error {
+	err := hw.writeHeader()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(hw.wr, "</tbody>\n</table>\n")
+	return err
+}
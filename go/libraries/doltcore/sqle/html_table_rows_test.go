@@ -0,0 +1,77 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestHTMLTableRowWriter(t *testing.T) {
+	ctx := context.Background()
+	cols := []schema.Column{
+		mergeTestCols[0],
+		mergeTestCols[1],
+		schema.NewColumn("<b>", 2, types.StringKind, false),
+	}
+
+	kvs := mergeTestKVs(t,
+		[]interface{}{1, 10, `<a href="x">Tom & Jerry</a>`},
+		[]interface{}{2, nil, "plain"},
+	)
+
+	writeTable := func(opts ...HTMLTableOption) string {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+		require.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		hw := NewHTMLTableRowWriter(buf, conv, opts...)
+		for i := 0; i+1 < len(kvs); i += 2 {
+			r, err := conv.ConvertKVTuplesToSqlRow(kvs[i], kvs[i+1])
+			require.NoError(t, err)
+			require.NoError(t, hw.WriteRow(ctx, r))
+		}
+
+		require.NoError(t, hw.Close())
+		return buf.String()
+	}
+
+	expected := "<table>\n" +
+		"<thead>\n<tr><th>pk</th><th>a</th><th>&lt;b&gt;</th></tr>\n</thead>\n" +
+		"<tbody>\n" +
+		"<tr><td>1</td><td>10</td><td>&lt;a href=&#34;x&#34;&gt;Tom &amp; Jerry&lt;/a&gt;</td></tr>\n" +
+		"<tr><td>2</td><td></td><td>plain</td></tr>\n" +
+		"</tbody>\n</table>\n"
+	assert.Equal(t, expected, writeTable())
+
+	withNull := writeTable(WithHTMLNull("<NULL>"))
+	assert.Contains(t, withNull, "<tr><td>2</td><td>&lt;NULL&gt;</td><td>plain</td></tr>\n")
+
+	t.Run("no rows", func(t *testing.T) {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+		require.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, NewHTMLTableRowWriter(buf, conv).Close())
+		assert.Equal(t, "<table>\n<thead>\n<tr><th>pk</th><th>a</th><th>&lt;b&gt;</th></tr>\n</thead>\n<tbody>\n</tbody>\n</table>\n", buf.String())
+	})
+}
@@ -165,9 +165,9 @@ func (il *doltIndexLookup) RowIterForRanges(ctx *sql.Context, rowData types.Map,
 
 	covers := il.indexCoversCols(columns)
 	if covers {
-		return NewCoveringIndexRowIterAdapter(ctx, il.idx, nrr, columns), nil
+		return NewCoveringIndexRowIterAdapter(ctx, il.idx, nrr, columns)
 	} else {
-		return NewIndexLookupRowIterAdapter(ctx, il.idx, nrr), nil
+		return NewIndexLookupRowIterAdapter(ctx, il.idx, nrr)
 	}
 }
 
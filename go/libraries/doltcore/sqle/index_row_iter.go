@@ -51,14 +51,19 @@ type indexLookupRowIterAdapter struct {
 }
 
 // NewIndexLookupRowIterAdapter returns a new indexLookupRowIterAdapter.
-func NewIndexLookupRowIterAdapter(ctx *sql.Context, idx DoltIndex, keyIter nomsKeyIter) *indexLookupRowIterAdapter {
+func NewIndexLookupRowIterAdapter(ctx *sql.Context, idx DoltIndex, keyIter nomsKeyIter) (*indexLookupRowIterAdapter, error) {
 	pkTags := make(map[uint64]int)
 	for i, tag := range idx.Schema().GetPKCols().Tags {
 		pkTags[tag] = i
 	}
 
 	cols := idx.Schema().GetAllCols().GetColumns()
-	conv := NewKVToSqlRowConverterForCols(idx.IndexRowData().Format(), cols)
+	conv, err := NewKVToSqlRowConverterForCols(idx.IndexRowData().Format(), cols)
+
+	if err != nil {
+		return nil, err
+	}
+
 	resBuf := resultBufferPool.Get().(*async.RingBuffer)
 	resBuf.Reset()
 
@@ -72,7 +77,7 @@ func NewIndexLookupRowIterAdapter(ctx *sql.Context, idx DoltIndex, keyIter nomsK
 	}
 
 	go iter.queueRows(ctx)
-	return iter
+	return iter, nil
 }
 
 // Next returns the next row from the iterator.
@@ -222,7 +227,7 @@ type coveringIndexRowIterAdapter struct {
 	nbf       *types.NomsBinFormat
 }
 
-func NewCoveringIndexRowIterAdapter(ctx *sql.Context, idx DoltIndex, keyIter nomsKeyIter, resultCols []string) *coveringIndexRowIterAdapter {
+func NewCoveringIndexRowIterAdapter(ctx *sql.Context, idx DoltIndex, keyIter nomsKeyIter, resultCols []string) (*coveringIndexRowIterAdapter, error) {
 	idxCols := idx.IndexSchema().GetPKCols()
 	tblPKCols := idx.Schema().GetPKCols()
 	sch := idx.Schema()
@@ -245,15 +250,21 @@ func NewCoveringIndexRowIterAdapter(ctx *sql.Context, idx DoltIndex, keyIter nom
 		}
 	}
 
+	conv, err := NewKVToSqlRowConverter(idx.IndexRowData().Format(), tagToSqlColIdx, cols, len(cols))
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &coveringIndexRowIterAdapter{
 		idx:       idx,
 		keyIter:   keyIter,
-		conv:      NewKVToSqlRowConverter(idx.IndexRowData().Format(), tagToSqlColIdx, cols, len(cols)),
+		conv:      conv,
 		ctx:       ctx,
 		pkCols:    sch.GetPKCols(),
 		nonPKCols: sch.GetNonPKCols(),
 		nbf:       idx.TableData().Format(),
-	}
+	}, nil
 }
 
 // Next returns the next row from the iterator.
@@ -0,0 +1,67 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// keyBucket is the configuration of WithKeyBucket
+type keyBucket struct {
+	idx        int
+	numBuckets uint64
+}
+
+// bucketFor returns the bucket of the key tuple given
+func (kb *keyBucket) bucketFor(k types.Tuple) (uint64, error) {
+	h, err := k.Hash(k.Format())
+
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(h[:8]) % kb.numBuckets, nil
+}
+
+// WithKeyBucket writes a bucket id computed from the key of each row to the index idx of the converted rows, so that a
+// scan can be partitioned deterministically, such as for sharding.  The bucket is the hash of the key tuple's encoded
+// bytes modulo numBuckets as a uint64, so a key is always assigned the same bucket for the same number of buckets.  The
+// bucket is set before the predicate of WithKeyPredicate is evaluated, so it can be used to read a single bucket without
+// decoding the value tuples of the rows in the others.  idx must be less than the converter's row size and must not be
+// an index a column is written to.
+func WithKeyBucket(idx int, numBuckets uint64) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		if numBuckets == 0 {
+			return errors.New("the number of key buckets must be greater than 0")
+		}
+
+		if idx < 0 || idx >= conv.rowSize {
+			return fmt.Errorf("key bucket index %d is out of range for a row of size %d", idx, conv.rowSize)
+		}
+
+		for tag, colIdx := range conv.tagToSqlColIdx {
+			if colIdx == idx {
+				return fmt.Errorf("key bucket index %d already holds the column with tag %d", idx, tag)
+			}
+		}
+
+		conv.keyBucket = &keyBucket{idx: idx, numBuckets: numBuckets}
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWithKeyBucket(t *testing.T) {
+	ctx := context.Background()
+	tagToSqlColIdx := map[uint64]int{0: 0, 1: 1, 2: 2}
+	cols := append([]schema.Column{}, mergeTestCols...)
+	newConv := func(opts ...KVToSqlRowConverterOption) *KVToSqlRowConverter {
+		conv, err := NewKVToSqlRowConverter(types.Format_Default, tagToSqlColIdx, cols, 4, opts...)
+		require.NoError(t, err)
+		return conv
+	}
+
+	var rows [][]interface{}
+	for i := 0; i < 8; i++ {
+		rows = append(rows, []interface{}{i, i * 10, "val"})
+	}
+
+	bucketsFor := func(conv *KVToSqlRowConverter) []interface{} {
+		var buckets []interface{}
+		for _, r := range drainRowIter(t, NewDoltMapIter(ctx, kvGetFuncForTuples(mergeTestKVs(t, rows...)...), nil, conv)) {
+			buckets = append(buckets, r[3])
+		}
+
+		return buckets
+	}
+
+	// buckets depend only on the encoded key, so they are pinned to catch any change in how they are computed
+	expected := []interface{}{uint64(1), uint64(1), uint64(1), uint64(0), uint64(0), uint64(2), uint64(1), uint64(2)}
+	assert.Equal(t, expected, bucketsFor(newConv(WithKeyBucket(3, 3))))
+	assert.Equal(t, expected, bucketsFor(newConv(WithKeyBucket(3, 3))))
+
+	// values don't change a row's bucket
+	for i := range rows {
+		rows[i][1], rows[i][2] = nil, "other"
+	}
+	assert.Equal(t, expected, bucketsFor(newConv(WithKeyBucket(3, 3))))
+
+	t.Run("with key predicate", func(t *testing.T) {
+		conv := newConv(WithKeyBucket(3, 3), WithKeyPredicate(func(r sql.Row) bool {
+			return r[3] == uint64(2)
+		}))
+
+		var pks []interface{}
+		for _, r := range drainRowIter(t, NewDoltMapIter(ctx, kvGetFuncForTuples(mergeTestKVs(t, rows...)...), nil, conv)) {
+			pks = append(pks, r[0])
+		}
+
+		assert.Equal(t, []interface{}{int64(5), int64(7)}, pks)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, opt := range []KVToSqlRowConverterOption{WithKeyBucket(3, 0), WithKeyBucket(4, 3), WithKeyBucket(-1, 3), WithKeyBucket(1, 3)} {
+			_, err := NewKVToSqlRowConverter(types.Format_Default, tagToSqlColIdx, cols, 4, opt)
+			assert.Error(t, err)
+		}
+	})
+}
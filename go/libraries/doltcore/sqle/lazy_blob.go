@@ -0,0 +1,99 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// LazyBlob is returned in place of the value of a varbinary column by a converter created with WithLazyBlobs.  It
+// holds a reference to the value's chunks rather than its data, which is read on demand through NewReader.  A
+// LazyBlob reads from the ValueReadWriter of the table it came from and is only valid until the iterator which
+// returned it is closed.  To keep memory bounded it should be consumed before the next row is requested.
+type LazyBlob struct {
+	blob types.Blob
+}
+
+// NewReader returns a reader over the value's data.  Each call returns a new reader starting at the beginning.
+func (lb *LazyBlob) NewReader(ctx context.Context) (io.Reader, error) {
+	rd, _, err := typeinfo.VarBinaryReader(ctx, lb.blob)
+	return rd, err
+}
+
+// Len returns the length of the value's data in bytes
+func (lb *LazyBlob) Len() (uint64, error) {
+	_, length, err := typeinfo.VarBinaryReader(context.Background(), lb.blob)
+	return length, err
+}
+
+// Bytes reads the full value into memory.  This defeats the purpose of a LazyBlob and is intended for small values
+// and for consumers that cannot stream.
+func (lb *LazyBlob) Bytes(ctx context.Context) ([]byte, error) {
+	rd, err := lb.NewReader(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(rd)
+}
+
+// valReader reads the value for a column from a tuple in place of the column's TypeInfo
+type valReader func(nbf *types.NomsBinFormat, reader types.CodecReader) (interface{}, error)
+
+// WithLazyBlobs causes the values of varbinary columns to be returned as *LazyBlob rather than being read fully into
+// memory as strings.  Consumers, such as export writers, can then stream each value to its destination.  NULLs are
+// still returned as nil.  Varchar and text columns store their values inline in the row so they are read as usual.
+func WithLazyBlobs() KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		for tag, idx := range conv.tagToSqlColIdx {
+			if conv.cols[idx].TypeInfo.GetTypeIdentifier() != typeinfo.VarBinaryTypeIdentifier {
+				continue
+			}
+
+			if conv.valReaders == nil {
+				conv.valReaders = make(map[uint64]valReader)
+			}
+
+			conv.valReaders[tag] = readLazyBlob
+		}
+
+		return nil
+	}
+}
+
+func readLazyBlob(_ *types.NomsBinFormat, reader types.CodecReader) (interface{}, error) {
+	switch k := reader.PeekKind(); k {
+	case types.BlobKind:
+		blob, err := reader.ReadBlob()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &LazyBlob{blob}, nil
+	case types.NullKind:
+		_ = reader.ReadKind()
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("cannot read NomsKind %v as a lazy blob", k)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	lazyBlobPKTag   = 0
+	lazyBlobDataTag = 1
+)
+
+func TestWithLazyBlobs(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	dataTI, err := typeinfo.FromSqlType(sql.LongBlob)
+	require.NoError(t, err)
+	dataCol, err := schema.NewColumnWithTypeInfo("data", lazyBlobDataTag, dataTI, false, "", false, "")
+	require.NoError(t, err)
+	cols := []schema.Column{schema.NewColumn("id", lazyBlobPKTag, types.IntKind, true), dataCol}
+
+	const valSize = 8 * 1024 * 1024
+	data := strings.Repeat(`a "quoted" value `, valSize/17)
+
+	blobVal, err := dataCol.TypeInfo.ConvertValueToNomsValue(ctx, vrw, data)
+	require.NoError(t, err)
+
+	k, err := types.NewTuple(vrw.Format(), types.Uint(lazyBlobPKTag), types.Int(1))
+	require.NoError(t, err)
+	v, err := types.NewTuple(vrw.Format(), types.Uint(lazyBlobDataTag), blobVal)
+	require.NoError(t, err)
+
+	// round trip the value tuple through the store so that it is decoded with a ValueReadWriter like table data is
+	ref, err := vrw.WriteValue(ctx, v)
+	require.NoError(t, err)
+	readVal, err := vrw.ReadValue(ctx, ref.TargetHash())
+	require.NoError(t, err)
+	v = readVal.(types.Tuple)
+
+	t.Run("eager", func(t *testing.T) {
+		conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), cols)
+		require.NoError(t, err)
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+		assert.Equal(t, data, r[1])
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), cols, WithLazyBlobs())
+		require.NoError(t, err)
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		runtime.ReadMemStats(&after)
+		require.NoError(t, err)
+
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(valSize/8))
+
+		lb, ok := r[1].(*LazyBlob)
+		require.True(t, ok)
+
+		length, err := lb.Len()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(len(data)), length)
+
+		rd, err := lb.NewReader(ctx)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		wr := bufio.NewWriter(&buf)
+		require.NoError(t, csv.WriteQuotedCSVField(wr, rd, false))
+		require.NoError(t, wr.Flush())
+		assert.Equal(t, `"`+strings.ReplaceAll(data, `"`, `""`)+`"`, buf.String())
+	})
+
+	t.Run("lazy null", func(t *testing.T) {
+		conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), cols, WithLazyBlobs())
+		require.NoError(t, err)
+		nullV, err := types.NewTuple(vrw.Format(), types.Uint(lazyBlobDataTag), types.NullValue)
+		require.NoError(t, err)
+		r, err := conv.ConvertKVTuplesToSqlRow(k, nullV)
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{int64(1), nil}, r)
+	})
+}
@@ -0,0 +1,421 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// SortDirection is the direction a sort key column is ordered in
+type SortDirection int
+
+const (
+	// Ascending orders values from smallest to largest
+	Ascending SortDirection = iota
+	// Descending orders values from largest to smallest
+	Descending
+)
+
+// NullOrdering is the placement of NULLs relative to the other values of a sort key column, as set by the NULLS FIRST
+// and NULLS LAST clauses of ORDER BY
+type NullOrdering int
+
+const (
+	// NullsDefault places NULLs as MySQL does, before all other values in ascending order and after them in descending
+	// order
+	NullsDefault NullOrdering = iota
+	// NullsFirst places NULLs before all other values whatever the direction
+	NullsFirst
+	// NullsLast places NULLs after all other values whatever the direction
+	NullsLast
+)
+
+// SortKeyCol is a single column of a sort key along with the direction it is sorted in and where its NULLs are placed
+type SortKeyCol struct {
+	Tag       uint64
+	Direction SortDirection
+	Nulls     NullOrdering
+}
+
+// SortKeyComparer decodes the sort keys of key value pairs and orders them.  It is how a MergeIter orders the rows of
+// its sources and finds the rows of different sources with the same key.  KeyComparator is the implementation for
+// sort keys made of columns of a schema.
+type SortKeyComparer interface {
+	// SortKey decodes the sort key of the key value pair given
+	SortKey(k, v types.Tuple) (sql.Row, error)
+	// CompareSortKeys returns a negative number when a sorts before b, a positive number when a sorts after b, and 0
+	// when they are the same key
+	CompareSortKeys(a, b sql.Row) (int, error)
+}
+
+var _ SortKeyComparer = (*KeyComparator)(nil)
+
+// KeyComparator orders key value pairs by a multi-column sort key where each column may be sorted in a different
+// direction.  Only the columns that are part of the sort key are decoded.  NULL values sort before all other values
+// in ascending order and after them in descending order, unless the column's NullOrdering places them otherwise.
+type KeyComparator struct {
+	keyCols  []SortKeyCol
+	sqlTypes []sql.Type
+	conv     *KVToSqlRowConverter
+}
+
+// NewKeyComparator returns a KeyComparator for the sort key columns given, which must all exist in the schema.
+func NewKeyComparator(nbf *types.NomsBinFormat, sch schema.Schema, keyCols ...SortKeyCol) (*KeyComparator, error) {
+	if len(keyCols) == 0 {
+		return nil, fmt.Errorf("sort key must have at least one column")
+	}
+
+	allCols := sch.GetAllCols()
+	cols := make([]schema.Column, len(keyCols))
+	sqlTypes := make([]sql.Type, len(keyCols))
+	for i, keyCol := range keyCols {
+		col, ok := allCols.GetByTag(keyCol.Tag)
+
+		if !ok {
+			return nil, fmt.Errorf("sort key column with tag %d not found in schema", keyCol.Tag)
+		}
+
+		cols[i] = col
+		sqlTypes[i] = col.TypeInfo.ToSqlType()
+	}
+
+	conv, err := NewKVToSqlRowConverterForCols(nbf, cols)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyComparator{
+		keyCols:  keyCols,
+		sqlTypes: sqlTypes,
+		conv:     conv,
+	}, nil
+}
+
+// SortKey decodes the sort key columns from the key value pair given.  The values are returned in sort key order.
+func (kc *KeyComparator) SortKey(k, v types.Tuple) (sql.Row, error) {
+	return kc.conv.ConvertKVTuplesToSqlRow(k, v)
+}
+
+// CompareSortKeys compares two sort keys returned by SortKey.  The result is negative when a sorts before b, positive
+// when a sorts after b, and 0 when they are equal.
+func (kc *KeyComparator) CompareSortKeys(a, b sql.Row) (int, error) {
+	for i, keyCol := range kc.keyCols {
+		if keyCol.Nulls != NullsDefault && (a[i] == nil) != (b[i] == nil) {
+			// the placement of NULLs doesn't depend on the direction
+			if (a[i] == nil) == (keyCol.Nulls == NullsFirst) {
+				return -1, nil
+			}
+
+			return 1, nil
+		}
+
+		n, err := compareNullable(kc.sqlTypes[i], a[i], b[i])
+
+		if err != nil {
+			return 0, err
+		}
+
+		if n != 0 {
+			if keyCol.Direction == Descending {
+				return -n, nil
+			}
+
+			return n, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// Compare decodes the sort keys of two key value pairs and compares them
+func (kc *KeyComparator) Compare(k1, v1, k2, v2 types.Tuple) (int, error) {
+	a, err := kc.SortKey(k1, v1)
+
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := kc.SortKey(k2, v2)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return kc.CompareSortKeys(a, b)
+}
+
+func compareNullable(sqlType sql.Type, a, b interface{}) (int, error) {
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return 0, nil
+		} else if a == nil {
+			return -1, nil
+		}
+
+		return 1, nil
+	}
+
+	return sqlType.Compare(a, b)
+}
+
+type mergeSource struct {
+	// idx is the position of the source in the arguments of NewMergeIter
+	idx     int
+	kvGet   KVGetFunc
+	k       types.Tuple
+	v       types.Tuple
+	sortKey sql.Row
+}
+
+type mergeHeap struct {
+	sources []*mergeSource
+	kc      SortKeyComparer
+	err     error
+}
+
+func (mh *mergeHeap) Len() int {
+	return len(mh.sources)
+}
+
+func (mh *mergeHeap) Less(i, j int) bool {
+	n, err := mh.kc.CompareSortKeys(mh.sources[i].sortKey, mh.sources[j].sortKey)
+
+	if err != nil && mh.err == nil {
+		mh.err = err
+	}
+
+	// rows with the same key are ordered by the position of their source
+	return n < 0 || (n == 0 && mh.sources[i].idx < mh.sources[j].idx)
+}
+
+func (mh *mergeHeap) Swap(i, j int) {
+	mh.sources[i], mh.sources[j] = mh.sources[j], mh.sources[i]
+}
+
+func (mh *mergeHeap) Push(x interface{}) {
+	mh.sources = append(mh.sources, x.(*mergeSource))
+}
+
+func (mh *mergeHeap) Pop() interface{} {
+	n := len(mh.sources)
+	src := mh.sources[n-1]
+	mh.sources = mh.sources[:n-1]
+	return src
+}
+
+// TieBreak is how a MergeIter handles a key which appears in more than one of its sources
+type TieBreak int
+
+const (
+	// TieBreakEmitAll emits the row of every source holding the key, in the order of the sources
+	TieBreakEmitAll TieBreak = iota
+	// TieBreakFirstWins emits only the row of the first source holding the key
+	TieBreakFirstWins
+	// TieBreakLastWins emits only the row of the last source holding the key, so later sources override earlier ones
+	// as later partitions do in a union of overlapping partitions
+	TieBreakLastWins
+)
+
+// MergeIter combines several streams of key value pairs, each of which is already ordered by the sort key of a
+// SortKeyComparer, into a single stream of sql.Rows ordered by that sort key.  Rows of different sources with the same
+// sort key are handled according to the MergeIter's TieBreak.  Rows filtered out by the key predicate of a converter
+// created with WithKeyPredicate are skipped, and when a tie is broken it is the winning row which is filtered.
+type MergeIter struct {
+	ctx      context.Context
+	conv     *KVToSqlRowConverter
+	mh       *mergeHeap
+	pending  []KVGetFunc
+	tieBreak TieBreak
+}
+
+// NewMergeIter returns a MergeIter that reads from each of the KVGetFuncs given and converts the merged stream using
+// conv.  The order of the KVGetFuncs is the order of the sources used to break ties, and every row is emitted until
+// SetTieBreak is called.
+func NewMergeIter(ctx context.Context, kc SortKeyComparer, conv *KVToSqlRowConverter, kvGets ...KVGetFunc) *MergeIter {
+	return &MergeIter{
+		ctx:     ctx,
+		conv:    conv,
+		mh:      &mergeHeap{kc: kc},
+		pending: kvGets,
+	}
+}
+
+// SetTieBreak sets how keys appearing in more than one source are handled.  Two rows have the same key when the
+// SortKeyComparer compares their sort keys as equal.  With TieBreakFirstWins and TieBreakLastWins each source is
+// expected to hold a key at most once.  It must be called before the first call to Next.
+func (itr *MergeIter) SetTieBreak(tb TieBreak) {
+	itr.tieBreak = tb
+}
+
+// advance reads the next key value pair from the source and decodes its sort key with kc, returning false when the
+// source is exhausted
+func (src *mergeSource) advance(ctx context.Context, kc SortKeyComparer) (bool, error) {
+	k, v, err := src.kvGet(ctx)
+
+	if err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	sortKey, err := kc.SortKey(k, v)
+
+	if err != nil {
+		return false, err
+	}
+
+	src.k, src.v, src.sortKey = k, v, sortKey
+	return true, nil
+}
+
+func (itr *MergeIter) init() error {
+	for i, kvGet := range itr.pending {
+		src := &mergeSource{idx: i, kvGet: kvGet}
+		ok, err := src.advance(itr.ctx, itr.mh.kc)
+
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			itr.mh.sources = append(itr.mh.sources, src)
+		}
+	}
+
+	itr.pending = nil
+	heap.Init(itr.mh)
+	return itr.mh.err
+}
+
+// Next returns the next sql.Row in sort key order until all sources are exhausted at which point (nil, io.EOF) is
+// returned.
+func (itr *MergeIter) Next() (sql.Row, error) {
+	if itr.pending != nil {
+		if err := itr.init(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if itr.mh.Len() == 0 {
+			return nil, io.EOF
+		}
+
+		var r sql.Row
+		var err error
+		if itr.tieBreak != TieBreakEmitAll {
+			r, err = itr.nextTieBroken()
+		} else {
+			r, err = itr.nextOfAll()
+		}
+
+		if err != ErrFilteredByKey {
+			return r, err
+		}
+	}
+}
+
+// nextOfAll returns the row of the source holding the smallest key and moves that source past it.  If the row is
+// filtered out by the converter's key predicate the source is still moved and ErrFilteredByKey is returned.
+func (itr *MergeIter) nextOfAll() (sql.Row, error) {
+	src := itr.mh.sources[0]
+	r, convErr := itr.conv.ConvertKVTuplesToSqlRow(src.k, src.v)
+
+	if convErr != nil && convErr != ErrFilteredByKey {
+		return nil, convErr
+	}
+
+	ok, err := src.advance(itr.ctx, itr.mh.kc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		heap.Fix(itr.mh, 0)
+	} else {
+		heap.Pop(itr.mh)
+	}
+
+	if itr.mh.err != nil {
+		return nil, itr.mh.err
+	}
+
+	return r, convErr
+}
+
+// nextTieBroken returns the row of the winning source among the sources holding the smallest key, and moves every one
+// of them past the key.  If the winning row is filtered out by the converter's key predicate the sources are still
+// moved and ErrFilteredByKey is returned.
+func (itr *MergeIter) nextTieBroken() (sql.Row, error) {
+	ties := []*mergeSource{heap.Pop(itr.mh).(*mergeSource)}
+	for itr.mh.Len() > 0 {
+		n, err := itr.mh.kc.CompareSortKeys(itr.mh.sources[0].sortKey, ties[0].sortKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if n != 0 {
+			break
+		}
+
+		// sources holding the same key are popped in source order
+		ties = append(ties, heap.Pop(itr.mh).(*mergeSource))
+	}
+
+	winner := ties[0]
+	if itr.tieBreak == TieBreakLastWins {
+		winner = ties[len(ties)-1]
+	}
+
+	r, convErr := itr.conv.ConvertKVTuplesToSqlRow(winner.k, winner.v)
+
+	if convErr != nil && convErr != ErrFilteredByKey {
+		return nil, convErr
+	}
+
+	for _, src := range ties {
+		ok, err := src.advance(itr.ctx, itr.mh.kc)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			heap.Push(itr.mh, src)
+		}
+	}
+
+	if itr.mh.err != nil {
+		return nil, itr.mh.err
+	}
+
+	return r, convErr
+}
+
+// Close required by sql.RowIter interface
+func (itr *MergeIter) Close(*sql.Context) error {
+	return nil
+}
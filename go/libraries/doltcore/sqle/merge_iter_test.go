@@ -0,0 +1,228 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var mergeTestCols = []schema.Column{
+	schema.NewColumn("pk", 0, types.IntKind, true),
+	schema.NewColumn("a", 1, types.IntKind, false),
+	schema.NewColumn("b", 2, types.StringKind, false),
+}
+
+func mergeTestSchema() schema.Schema {
+	return schema.MustSchemaFromCols(schema.NewColCollection(mergeTestCols...))
+}
+
+// mergeTestKVs builds the key value tuples for rows of mergeTestCols.  Each row is given as (pk, a, b) where a nil a
+// or b is left out of the value tuple.
+func mergeTestKVs(t *testing.T, rows ...[]interface{}) []types.Tuple {
+	var kvs []types.Tuple
+	for _, r := range rows {
+		k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(r[0].(int)))
+		require.NoError(t, err)
+
+		var vals []types.Value
+		if r[1] != nil {
+			vals = append(vals, types.Uint(1), types.Int(r[1].(int)))
+		}
+		if r[2] != nil {
+			vals = append(vals, types.Uint(2), types.String(r[2].(string)))
+		}
+
+		v, err := types.NewTuple(types.Format_Default, vals...)
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	return kvs
+}
+
+func drainRowIter(t *testing.T, itr sql.RowIter) []sql.Row {
+	var rows []sql.Row
+	for {
+		r, err := itr.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+
+	return rows
+}
+
+func TestKeyComparatorMixedDirections(t *testing.T) {
+	kc, err := NewKeyComparator(types.Format_Default, mergeTestSchema(), SortKeyCol{Tag: 1, Direction: Ascending}, SortKeyCol{Tag: 2, Direction: Descending})
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t, []interface{}{1, 1, "a"}, []interface{}{2, 1, "b"}, []interface{}{3, 2, "a"}, []interface{}{4, nil, "a"})
+
+	sortKey, err := kc.SortKey(kvs[0], kvs[1])
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "a"}, sortKey)
+
+	tests := []struct {
+		name     string
+		i, j     int
+		expected int
+	}{
+		{"first column ascending", 0, 2, -1},
+		{"second column descending", 0, 1, 1},
+		{"equal", 1, 1, 0},
+		{"null first ascending", 3, 0, -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, err := kc.Compare(kvs[test.i*2], kvs[test.i*2+1], kvs[test.j*2], kvs[test.j*2+1])
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, n)
+		})
+	}
+}
+
+func TestMergeIterAscThenDesc(t *testing.T) {
+	sch := mergeTestSchema()
+	kc, err := NewKeyComparator(types.Format_Default, sch, SortKeyCol{Tag: 1, Direction: Ascending}, SortKeyCol{Tag: 2, Direction: Descending})
+	require.NoError(t, err)
+
+	left := mergeTestKVs(t, []interface{}{1, 1, "c"}, []interface{}{2, 1, "a"}, []interface{}{3, 3, "z"})
+	right := mergeTestKVs(t, []interface{}{4, 1, "b"}, []interface{}{5, 2, "b"}, []interface{}{6, 3, "a"})
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+	itr := NewMergeIter(context.Background(), kc, conv, kvGetFuncForTuples(left...), kvGetFuncForTuples(right...))
+	rows := drainRowIter(t, itr)
+
+	expected := []sql.Row{
+		{int64(1), int64(1), "c"},
+		{int64(4), int64(1), "b"},
+		{int64(2), int64(1), "a"},
+		{int64(5), int64(2), "b"},
+		{int64(3), int64(3), "z"},
+		{int64(6), int64(3), "a"},
+	}
+	assert.Equal(t, expected, rows)
+}
+
+func TestMergeIterNullOrdering(t *testing.T) {
+	sch := mergeTestSchema()
+	left := mergeTestKVs(t, []interface{}{1, nil, "a"}, []interface{}{2, 2, "b"}, []interface{}{3, 5, "c"})
+	right := mergeTestKVs(t, []interface{}{4, nil, "d"}, []interface{}{5, 1, "e"}, []interface{}{6, 3, "f"})
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	pksFor := func(left, right []types.Tuple, keyCols ...SortKeyCol) []interface{} {
+		kc, err := NewKeyComparator(types.Format_Default, sch, keyCols...)
+		require.NoError(t, err)
+
+		var pks []interface{}
+		for _, r := range drainRowIter(t, NewMergeIter(context.Background(), kc, conv, kvGetFuncForTuples(left...), kvGetFuncForTuples(right...))) {
+			pks = append(pks, r[0])
+		}
+
+		return pks
+	}
+
+	t.Run("ascending", func(t *testing.T) {
+		nullsFirst := pksFor(left, right, SortKeyCol{Tag: 1, Direction: Ascending, Nulls: NullsFirst}, SortKeyCol{Tag: 2})
+		assert.Equal(t, []interface{}{int64(1), int64(4), int64(5), int64(2), int64(6), int64(3)}, nullsFirst)
+		assert.Equal(t, nullsFirst, pksFor(left, right, SortKeyCol{Tag: 1, Direction: Ascending}, SortKeyCol{Tag: 2}))
+
+		// each stream is ordered with its NULLs last
+		left := mergeTestKVs(t, []interface{}{2, 2, "b"}, []interface{}{3, 5, "c"}, []interface{}{1, nil, "a"})
+		right := mergeTestKVs(t, []interface{}{5, 1, "e"}, []interface{}{6, 3, "f"}, []interface{}{4, nil, "d"})
+		nullsLast := pksFor(left, right, SortKeyCol{Tag: 1, Direction: Ascending, Nulls: NullsLast}, SortKeyCol{Tag: 2})
+		assert.Equal(t, []interface{}{int64(5), int64(2), int64(6), int64(3), int64(1), int64(4)}, nullsLast)
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		left := mergeTestKVs(t, []interface{}{1, nil, "a"}, []interface{}{3, 5, "c"}, []interface{}{2, 2, "b"})
+		right := mergeTestKVs(t, []interface{}{4, nil, "d"}, []interface{}{6, 3, "f"}, []interface{}{5, 1, "e"})
+		nullsFirst := pksFor(left, right, SortKeyCol{Tag: 1, Direction: Descending, Nulls: NullsFirst}, SortKeyCol{Tag: 2})
+		assert.Equal(t, []interface{}{int64(1), int64(4), int64(3), int64(6), int64(2), int64(5)}, nullsFirst)
+
+		left = mergeTestKVs(t, []interface{}{3, 5, "c"}, []interface{}{2, 2, "b"}, []interface{}{1, nil, "a"})
+		right = mergeTestKVs(t, []interface{}{6, 3, "f"}, []interface{}{5, 1, "e"}, []interface{}{4, nil, "d"})
+		nullsLast := pksFor(left, right, SortKeyCol{Tag: 1, Direction: Descending, Nulls: NullsLast}, SortKeyCol{Tag: 2})
+		assert.Equal(t, []interface{}{int64(3), int64(6), int64(2), int64(5), int64(1), int64(4)}, nullsLast)
+		assert.Equal(t, nullsLast, pksFor(left, right, SortKeyCol{Tag: 1, Direction: Descending}, SortKeyCol{Tag: 2}))
+	})
+}
+
+func TestMergeIterTieBreak(t *testing.T) {
+	kc, err := NewKeyComparator(types.Format_Default, mergeTestSchema(), SortKeyCol{Tag: 0})
+	require.NoError(t, err)
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	// three overlapping partitions where b names the partition
+	first := mergeTestKVs(t, []interface{}{1, 10, "first"}, []interface{}{2, 20, "first"}, []interface{}{4, 40, "first"})
+	second := mergeTestKVs(t, []interface{}{2, 21, "second"}, []interface{}{3, 31, "second"}, []interface{}{4, 41, "second"})
+	third := mergeTestKVs(t, []interface{}{2, 22, "third"}, []interface{}{5, 52, "third"})
+
+	rowsFor := func(tb TieBreak) []sql.Row {
+		itr := NewMergeIter(context.Background(), kc, conv, kvGetFuncForTuples(first...), kvGetFuncForTuples(second...), kvGetFuncForTuples(third...))
+		itr.SetTieBreak(tb)
+		return drainRowIter(t, itr)
+	}
+
+	t.Run("emit all", func(t *testing.T) {
+		assert.Equal(t, []sql.Row{
+			{int64(1), int64(10), "first"},
+			{int64(2), int64(20), "first"},
+			{int64(2), int64(21), "second"},
+			{int64(2), int64(22), "third"},
+			{int64(3), int64(31), "second"},
+			{int64(4), int64(40), "first"},
+			{int64(4), int64(41), "second"},
+			{int64(5), int64(52), "third"},
+		}, rowsFor(TieBreakEmitAll))
+	})
+
+	t.Run("first wins", func(t *testing.T) {
+		assert.Equal(t, []sql.Row{
+			{int64(1), int64(10), "first"},
+			{int64(2), int64(20), "first"},
+			{int64(3), int64(31), "second"},
+			{int64(4), int64(40), "first"},
+			{int64(5), int64(52), "third"},
+		}, rowsFor(TieBreakFirstWins))
+	})
+
+	t.Run("last wins", func(t *testing.T) {
+		assert.Equal(t, []sql.Row{
+			{int64(1), int64(10), "first"},
+			{int64(2), int64(22), "third"},
+			{int64(3), int64(31), "second"},
+			{int64(4), int64(41), "second"},
+			{int64(5), int64(52), "third"},
+		}, rowsFor(TieBreakLastWins))
+	})
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrNumericOutOfRange is returned, wrapped with the column and value, when a converter created with
+// WithNumericRangeValidation and no NumericRangeHandler reads a value outside the range of its column's type
+var ErrNumericOutOfRange = errors.New("numeric value out of range")
+
+// NumericRangeHandler is called with the stored value, an int64 or uint64, of an integer column when it is outside the
+// range of the column's SQL type.  It returns the value to emit in its place, or an error which stops conversion.
+type NumericRangeHandler func(col schema.Column, val interface{}) (interface{}, error)
+
+// numericRange is the range of values of an integer column narrower than 64 bits
+type numericRange struct {
+	unsigned bool
+	min      int64
+	max      int64
+	umax     uint64
+}
+
+// numericRangeForCol returns the range of the SQL type of the column given.  false is returned for columns which are
+// not integers or which are 64 bits wide, as their stored values can't be out of range.
+func numericRangeForCol(col schema.Column) (numericRange, bool) {
+	switch col.TypeInfo.ToSqlType() {
+	case sql.Int8:
+		return numericRange{min: math.MinInt8, max: math.MaxInt8}, true
+	case sql.Int16:
+		return numericRange{min: math.MinInt16, max: math.MaxInt16}, true
+	case sql.Int24:
+		return numericRange{min: -1 << 23, max: 1<<23 - 1}, true
+	case sql.Int32:
+		return numericRange{min: math.MinInt32, max: math.MaxInt32}, true
+	case sql.Uint8:
+		return numericRange{unsigned: true, umax: math.MaxUint8}, true
+	case sql.Uint16:
+		return numericRange{unsigned: true, umax: math.MaxUint16}, true
+	case sql.Uint24:
+		return numericRange{unsigned: true, umax: 1<<24 - 1}, true
+	case sql.Uint32:
+		return numericRange{unsigned: true, umax: math.MaxUint32}, true
+	default:
+		return numericRange{}, false
+	}
+}
+
+// String returns the range in interval notation
+func (nr numericRange) String() string {
+	if nr.unsigned {
+		return fmt.Sprintf("[0, %d]", nr.umax)
+	}
+
+	return fmt.Sprintf("[%d, %d]", nr.min, nr.max)
+}
+
+// ClampToColumnRange is a NumericRangeHandler which emits the bound of the column's range nearest to the value in place
+// of the value
+func ClampToColumnRange(col schema.Column, val interface{}) (interface{}, error) {
+	nr, ok := numericRangeForCol(col)
+
+	if !ok {
+		return nil, fmt.Errorf("column '%s' of type %s has no range to clamp to", col.Name, col.TypeInfo.String())
+	}
+
+	return nr.clamp(col, val)
+}
+
+// clamp returns the value of the column given nearest to val, an int64 or uint64, within the range.  Values within the
+// range are returned as the Go type of the column unchanged.
+func (nr numericRange) clamp(col schema.Column, val interface{}) (interface{}, error) {
+	var below, above bool
+	switch v := val.(type) {
+	case int64:
+		below = v < nr.min
+		above = !nr.unsigned && v > nr.max || nr.unsigned && v > 0 && uint64(v) > nr.umax
+	case uint64:
+		above = nr.unsigned && v > nr.umax || !nr.unsigned && v > uint64(nr.max)
+	default:
+		return nil, fmt.Errorf("column '%s' cannot clamp a %T value", col.Name, val)
+	}
+
+	switch {
+	case nr.unsigned && below:
+		return col.TypeInfo.ConvertNomsValueToValue(types.Uint(0))
+	case nr.unsigned && above:
+		return col.TypeInfo.ConvertNomsValueToValue(types.Uint(nr.umax))
+	case below:
+		return col.TypeInfo.ConvertNomsValueToValue(types.Int(nr.min))
+	case above:
+		return col.TypeInfo.ConvertNomsValueToValue(types.Int(nr.max))
+	case nr.unsigned:
+		if i, ok := val.(int64); ok {
+			return col.TypeInfo.ConvertNomsValueToValue(types.Uint(uint64(i)))
+		}
+
+		return col.TypeInfo.ConvertNomsValueToValue(types.Uint(val.(uint64)))
+	default:
+		if u, ok := val.(uint64); ok {
+			return col.TypeInfo.ConvertNomsValueToValue(types.Int(int64(u)))
+		}
+
+		return col.TypeInfo.ConvertNomsValueToValue(types.Int(val.(int64)))
+	}
+}
+
+// WithNumericRangeValidation checks the values of integer columns as they are read against the range of the column's
+// SQL type, such as a TINYINT holding 300 from a bad import, which would otherwise be silently truncated.  Out of range
+// values are passed to handler, or produce an error wrapping ErrNumericOutOfRange which names the column when handler
+// is nil.  The check applies to the columns with the tags given, or to every integer column being converted when no
+// tags are given.  BIGINT columns can't hold out of range values so they are never checked.  An error is returned if a
+// tag given is not an integer column, while tags that are not being converted are ignored.
+func WithNumericRangeValidation(handler NumericRangeHandler, tags ...uint64) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		checkTags := tags
+		if len(checkTags) == 0 {
+			for tag := range conv.tagToSqlColIdx {
+				checkTags = append(checkTags, tag)
+			}
+		}
+
+		for _, tag := range checkTags {
+			col, ok := conv.colForTag(tag)
+
+			if !ok {
+				continue
+			}
+
+			nr, ok := numericRangeForCol(col)
+
+			if !ok {
+				if id := col.TypeInfo.GetTypeIdentifier(); len(tags) == 0 || id == typeinfo.IntTypeIdentifier || id == typeinfo.UintTypeIdentifier {
+					continue
+				}
+
+				return fmt.Errorf("column '%s' of type %s is not an integer column", col.Name, col.TypeInfo.String())
+			}
+
+			if conv.valReaders == nil {
+				conv.valReaders = make(map[uint64]valReader)
+			}
+
+			conv.valReaders[tag] = rangeCheckedReader(col, nr, handler)
+		}
+
+		return nil
+	}
+}
+
+func rangeCheckedReader(col schema.Column, nr numericRange, handler NumericRangeHandler) valReader {
+	return func(nbf *types.NomsBinFormat, reader types.CodecReader) (interface{}, error) {
+		var val interface{}
+		var inRange bool
+		switch reader.PeekKind() {
+		case types.IntKind:
+			_ = reader.ReadKind()
+			i := reader.ReadInt()
+			val, inRange = i, i >= nr.min && (nr.unsigned && uint64(i) <= nr.umax || !nr.unsigned && i <= nr.max)
+		case types.UintKind:
+			_ = reader.ReadKind()
+			u := reader.ReadUint()
+			val, inRange = u, nr.unsigned && u <= nr.umax || !nr.unsigned && u <= uint64(nr.max)
+		default:
+			return col.TypeInfo.ReadFrom(nbf, reader)
+		}
+
+		if !inRange {
+			if handler == nil {
+				return nil, fmt.Errorf("%w: column '%s' of type %s has the value %v outside of %s", ErrNumericOutOfRange, col.Name, col.TypeInfo.String(), val, nr)
+			}
+
+			return handler(col, val)
+		}
+
+		return nr.clamp(col, val)
+	}
+}
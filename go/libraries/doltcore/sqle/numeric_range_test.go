@@ -0,0 +1,186 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func numericRangeTestCols(t *testing.T) []schema.Column {
+	tis := []typeinfo.TypeInfo{typeinfo.Int64Type, typeinfo.Int8Type, typeinfo.Int16Type, typeinfo.Int24Type, typeinfo.Uint8Type, typeinfo.Uint32Type}
+	names := []string{"id", "tiny", "small", "medium", "utiny", "uint"}
+
+	cols := make([]schema.Column, len(tis))
+	for i, ti := range tis {
+		var err error
+		cols[i], err = schema.NewColumnWithTypeInfo(names[i], uint64(i), ti, i == 0, "", false, "")
+		require.NoError(t, err)
+	}
+
+	return cols
+}
+
+func numericRangeTestTuples(t *testing.T, vals ...types.Value) (types.Tuple, types.Tuple) {
+	k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(1))
+	require.NoError(t, err)
+
+	var taggedVals []types.Value
+	for i, val := range vals {
+		taggedVals = append(taggedVals, types.Uint(uint64(i+1)), val)
+	}
+
+	v, err := types.NewTuple(types.Format_Default, taggedVals...)
+	require.NoError(t, err)
+
+	return k, v
+}
+
+func TestWithNumericRangeValidation(t *testing.T) {
+	cols := numericRangeTestCols(t)
+
+	inRange := []types.Value{types.Int(-128), types.Int(32767), types.Int(-8388608), types.Uint(255), types.Uint(4294967295)}
+	inRangeRow := sql.Row{int64(1), int8(-128), int16(32767), int32(-8388608), uint8(255), uint32(4294967295)}
+
+	tests := []struct {
+		name     string
+		vals     []types.Value
+		expected sql.Row
+		errCol   string
+	}{
+		{
+			name:     "in range",
+			vals:     inRange,
+			expected: inRangeRow,
+		},
+		{
+			name:     "nulls",
+			vals:     []types.Value{types.NullValue, types.NullValue, types.NullValue, types.NullValue, types.NullValue},
+			expected: sql.Row{int64(1), nil, nil, nil, nil, nil},
+		},
+		{
+			name:   "tinyint over",
+			vals:   []types.Value{types.Int(300), inRange[1], inRange[2], inRange[3], inRange[4]},
+			errCol: "tiny",
+		},
+		{
+			name:   "smallint under",
+			vals:   []types.Value{inRange[0], types.Int(-32769), inRange[2], inRange[3], inRange[4]},
+			errCol: "small",
+		},
+		{
+			name:   "mediumint over",
+			vals:   []types.Value{inRange[0], inRange[1], types.Int(8388608), inRange[3], inRange[4]},
+			errCol: "medium",
+		},
+		{
+			name:   "tinyint unsigned over",
+			vals:   []types.Value{inRange[0], inRange[1], inRange[2], types.Uint(256), inRange[4]},
+			errCol: "utiny",
+		},
+		{
+			name:   "int unsigned over",
+			vals:   []types.Value{inRange[0], inRange[1], inRange[2], inRange[3], types.Uint(1 << 32)},
+			errCol: "uint",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithNumericRangeValidation(nil))
+			require.NoError(t, err)
+
+			k, v := numericRangeTestTuples(t, test.vals...)
+			r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+
+			if test.errCol != "" {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrNumericOutOfRange))
+				assert.Contains(t, err.Error(), "'"+test.errCol+"'")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, r)
+		})
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+		require.NoError(t, err)
+
+		k, v := numericRangeTestTuples(t, types.Int(300), inRange[1], inRange[2], inRange[3], inRange[4])
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+		assert.Equal(t, int8(44), r[1])
+	})
+
+	t.Run("only tags given", func(t *testing.T) {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithNumericRangeValidation(nil, 2, 99))
+		require.NoError(t, err)
+
+		k, v := numericRangeTestTuples(t, types.Int(300), inRange[1], inRange[2], inRange[3], inRange[4])
+		_, err = conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+
+		k, v = numericRangeTestTuples(t, inRange[0], types.Int(40000), inRange[2], inRange[3], inRange[4])
+		_, err = conv.ConvertKVTuplesToSqlRow(k, v)
+		assert.True(t, errors.Is(err, ErrNumericOutOfRange))
+	})
+
+	t.Run("clamp", func(t *testing.T) {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithNumericRangeValidation(ClampToColumnRange))
+		require.NoError(t, err)
+
+		k, v := numericRangeTestTuples(t, types.Int(300), types.Int(-40000), types.Int(1<<30), types.Int(-1), types.Uint(1<<40))
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{int64(1), int8(127), int16(-32768), int32(8388607), uint8(0), uint32(4294967295)}, r)
+	})
+
+	t.Run("callback", func(t *testing.T) {
+		var violations []string
+		handler := func(col schema.Column, val interface{}) (interface{}, error) {
+			violations = append(violations, col.Name)
+			return nil, nil
+		}
+
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithNumericRangeValidation(handler))
+		require.NoError(t, err)
+
+		k, v := numericRangeTestTuples(t, types.Int(300), inRange[1], inRange[2], types.Uint(256), inRange[4])
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{int64(1), nil, int16(32767), int32(-8388608), nil, uint32(4294967295)}, r)
+		assert.ElementsMatch(t, []string{"tiny", "utiny"}, violations)
+	})
+
+	t.Run("not an integer column", func(t *testing.T) {
+		_, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithNumericRangeValidation(nil, mapIterNameTag))
+		assert.Error(t, err)
+
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithNumericRangeValidation(nil))
+		require.NoError(t, err)
+		assert.Equal(t, SingleIntPKDecodeStrategy, conv.DecodeStrategy())
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// cursorVersion is the first byte of every decoded cursor so that the encoding can change without misreading older
+// cursors
+const cursorVersion byte = 1
+
+// ErrNoCursor is returned by Cursor when the iterator has not returned a row yet
+var ErrNoCursor = errors.New("no rows have been returned to build a cursor from")
+
+// ErrInvalidCursor is wrapped by the error returned when a cursor can't be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor returns an opaque token for the position after the last row returned by Next or NextWithRawTuples, for use
+// in paging through a table without offset scanning.  The token encodes the key of the last row, so it is stable for
+// as long as the key's encoding is, and it only uses URL safe characters.  A following page is read by passing the
+// token to NewDoltMapIterFromCursor.  ErrNoCursor is returned if no row has been returned yet.
+func (dmi *DoltMapIter) Cursor() (string, error) {
+	if !dmi.hasLastKey {
+		return "", ErrNoCursor
+	}
+
+	keyBytes, err := encodedTupleBytes(dmi.lastKey)
+
+	if err != nil {
+		return "", err
+	}
+
+	return encodeCursorBytes(append([]byte{cursorVersion}, keyBytes...)), nil
+}
+
+func encodeCursorBytes(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor returns the key encoded in a token returned by Cursor.  An error wrapping ErrInvalidCursor is returned
+// if the token is malformed.
+func DecodeCursor(vrw types.ValueReadWriter, cursor string) (types.Tuple, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return types.Tuple{}, fmt.Errorf("%w: %s", ErrInvalidCursor, err.Error())
+	}
+
+	if len(data) < 2 || data[0] != cursorVersion {
+		return types.Tuple{}, fmt.Errorf("%w: unrecognized cursor version", ErrInvalidCursor)
+	}
+
+	if types.NomsKind(data[1]) != types.TupleKind {
+		return types.Tuple{}, fmt.Errorf("%w: cursor does not hold a key", ErrInvalidCursor)
+	}
+
+	key, err := decodeCursorKey(vrw, data[1:])
+
+	if err != nil {
+		return types.Tuple{}, err
+	}
+
+	return key, nil
+}
+
+// decodeCursorKey decodes the key tuple of a cursor, turning the panics the decoder raises on truncated data into
+// errors
+func decodeCursorKey(vrw types.ValueReadWriter, data []byte) (key types.Tuple, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: malformed key", ErrInvalidCursor)
+		}
+	}()
+
+	val, err := types.DecodeValue(chunks.NewChunk(data), vrw)
+
+	if err != nil {
+		return types.Tuple{}, fmt.Errorf("%w: %s", ErrInvalidCursor, err.Error())
+	}
+
+	return val.(types.Tuple), nil
+}
+
+// NewDoltMapIterFromCursor returns a DoltMapIter over the rows of m which follow the position of a token returned by
+// Cursor.  The iterator seeks directly to the cursor's key rather than scanning the rows before it.  If the cursor's
+// row has since been deleted iteration resumes at the next row, and an empty cursor starts from the first row.
+func NewDoltMapIterFromCursor(ctx context.Context, vrw types.ValueReadWriter, m types.Map, cursor string, conv *KVToSqlRowConverter) (*DoltMapIter, error) {
+	if cursor == "" {
+		mapItr, err := m.Iterator(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return NewDoltMapIter(ctx, GetGetFuncForMapIter(m.Format(), mapItr), nil, conv), nil
+	}
+
+	key, err := DecodeCursor(vrw, cursor)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mapItr, err := m.IteratorFrom(ctx, key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	get := GetGetFuncForMapIter(m.Format(), mapItr)
+	first := true
+	seekGet := func(ctx context.Context) (types.Tuple, types.Tuple, error) {
+		k, v, err := get(ctx)
+
+		if err != nil || !first {
+			return k, v, err
+		}
+
+		// the seek lands on the cursor's row when it still exists, and it was returned with the previous page
+		first = false
+		if k.Equals(key) {
+			return get(ctx)
+		}
+
+		return k, v, nil
+	}
+
+	return NewDoltMapIter(ctx, seekGet, nil, conv), nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDoltMapIterCursor(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), mergeTestCols)
+	require.NoError(t, err)
+
+	var rows [][]interface{}
+	var expected []sql.Row
+	for i := 1; i <= 10; i++ {
+		rows = append(rows, []interface{}{i, i * 10, string(rune('a' + i))})
+		expected = append(expected, sql.Row{int64(i), int64(i * 10), string(rune('a' + i))})
+	}
+
+	var kvs []types.Value
+	for _, tup := range mergeTestKVs(t, rows...) {
+		kvs = append(kvs, tup)
+	}
+	m, err := types.NewMap(ctx, vrw, kvs...)
+	require.NoError(t, err)
+
+	// page through the map 3 rows at a time
+	const pageSize = 3
+	var paged []sql.Row
+	cursor := ""
+	for {
+		dmi, err := NewDoltMapIterFromCursor(ctx, vrw, m, cursor, conv)
+		require.NoError(t, err)
+
+		n := 0
+		for ; n < pageSize; n++ {
+			r, err := dmi.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			paged = append(paged, r)
+		}
+
+		if n < pageSize {
+			break
+		}
+
+		cursor, err = dmi.Cursor()
+		require.NoError(t, err)
+		assert.Equal(t, url.QueryEscape(cursor), cursor)
+	}
+
+	assert.Equal(t, expected, paged)
+
+	// cursors are stable
+	dmi, err := NewDoltMapIterFromCursor(ctx, vrw, m, "", conv)
+	require.NoError(t, err)
+	_, err = dmi.Cursor()
+	assert.Equal(t, ErrNoCursor, err)
+	_, err = dmi.Next()
+	require.NoError(t, err)
+	first, err := dmi.Cursor()
+	require.NoError(t, err)
+
+	dmi2, err := NewDoltMapIterFromCursor(ctx, vrw, m, "", conv)
+	require.NoError(t, err)
+	_, _, _, err = dmi2.NextWithRawTuples()
+	require.NoError(t, err)
+	second, err := dmi2.Cursor()
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	// deleting the cursor's row resumes at the row after it
+	_, err = dmi.Next()
+	require.NoError(t, err)
+	cursor, err = dmi.Cursor()
+	require.NoError(t, err)
+
+	key, err := DecodeCursor(vrw, cursor)
+	require.NoError(t, err)
+	edited, err := m.Edit().Remove(key).Map(ctx)
+	require.NoError(t, err)
+
+	dmi, err = NewDoltMapIterFromCursor(ctx, vrw, edited, cursor, conv)
+	require.NoError(t, err)
+	assert.Equal(t, expected[2:], drainRowIter(t, dmi))
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	vrw := types.NewMemoryValueStore()
+
+	str, err := types.EncodeValue(types.String("not a key"), vrw.Format())
+	require.NoError(t, err)
+	notKey := append([]byte{cursorVersion}, str.Data()...)
+
+	for _, cursor := range []string{"not base64!", "", "AQ", "Ag", encodeCursorBytes(notKey), encodeCursorBytes([]byte{cursorVersion, byte(types.TupleKind), 5})} {
+		_, err := DecodeCursor(vrw, cursor)
+		assert.True(t, errors.Is(err, ErrInvalidCursor), "cursor %q: %v", cursor, err)
+	}
+}
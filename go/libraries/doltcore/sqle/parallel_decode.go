@@ -0,0 +1,175 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// WithParallelDecode causes the converter to convert the values of each value tuple holding at least threshold of the
+// columns being converted on up to workers goroutines, for extremely wide rows where converting the values one after
+// another is the bottleneck even for a single row.  The value tuple is read once, and its values are then split into
+// disjoint ranges of output positions, one per worker, so workers never write the same position of the row.  Transforms
+// and size estimates are applied once the workers are done.  Converters with value readers, masks, a timing hook or
+// checks which read whole tuples, such as WithValTupleDriftCheck, always convert values serially.  Rows narrower than
+// threshold are converted serially, as starting the workers costs more than it saves for them.
+func WithParallelDecode(threshold, workers int) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		if threshold < 1 || workers < 1 {
+			return fmt.Errorf("parallel decode threshold and workers must be at least 1 but were %d and %d", threshold, workers)
+		}
+
+		conv.parallelThreshold = threshold
+		conv.parallelWorkers = workers
+		return nil
+	}
+}
+
+// decodesValsInParallel returns whether value tuples are converted by processTupleParallel, which is decided once all
+// options have been applied
+func (conv *KVToSqlRowConverter) decodesValsInParallel() bool {
+	return conv.parallelWorkers > 1 && conv.valsFromVal >= conv.parallelThreshold && len(conv.valReaders) == 0 &&
+		len(conv.masks) == 0 && conv.timingHook == nil && conv.drift == nil && !conv.checkTagOrder
+}
+
+// parallelVal is the position in a value tuple of a value being converted, along with its tag and output position
+type parallelVal struct {
+	pos uint64
+	tag uint64
+	idx int
+}
+
+// processTupleParallel finds the values of the tags being converted in the value tuple tup, skipping over them without
+// decoding them, and then has each of the converter's workers read and convert a contiguous range of them through its
+// own reader positioned at the start of that range
+func (conv *KVToSqlRowConverter) processTupleParallel(cols []interface{}, maxTag uint64, tup types.Tuple, tupItr *types.TupleIterator, size *int64) error {
+	err := tupItr.InitForTuple(tup)
+
+	if err != nil {
+		return err
+	}
+
+	nbf := tup.Format()
+	primReader, numPrimitives := tupItr.CodecReader()
+
+	vals := make([]parallelVal, 0, conv.valsFromVal)
+	for pos := uint64(0); pos+1 < numPrimitives && len(vals) < conv.valsFromVal; pos += 2 {
+		if primReader.ReadKind() != types.UintKind {
+			return errors.New("Encountered unexpected kind while attempting to read tag")
+		}
+
+		tag := primReader.ReadUint()
+		if tag > maxTag {
+			break
+		}
+
+		if idx, ok := conv.tagToSqlColIdx[tag]; ok {
+			vals = append(vals, parallelVal{pos: pos + 1, tag: tag, idx: idx})
+		}
+
+		err = primReader.SkipValue(nbf)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	workers := conv.parallelWorkers
+	if workers > len(vals) {
+		workers = len(vals)
+	}
+
+	errs := make([]error, workers)
+	chunkSize := (len(vals) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*chunkSize, (w+1)*chunkSize
+		if end > len(vals) {
+			end = len(vals)
+		}
+
+		wg.Add(1)
+		go func(w int, chunk []parallelVal) {
+			defer wg.Done()
+			errs[w] = conv.readParallelVals(cols, tup, chunk)
+		}(w, vals[start:end])
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pv := range vals {
+		if transform, ok := conv.valTransforms[pv.tag]; ok {
+			cols[pv.idx], err = transform(cols[pv.idx])
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if size != nil {
+			*size += estimateValSize(cols[pv.idx])
+		}
+	}
+
+	return nil
+}
+
+// readParallelVals reads and converts the values of chunk, which are in the order they are stored in tup
+func (conv *KVToSqlRowConverter) readParallelVals(cols []interface{}, tup types.Tuple, chunk []parallelVal) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	itr, err := tup.IteratorAt(chunk[0].pos)
+
+	if err != nil {
+		return err
+	}
+
+	nbf := tup.Format()
+	primReader, _ := itr.CodecReader()
+
+	pos := chunk[0].pos
+	for _, pv := range chunk {
+		for ; pos < pv.pos; pos++ {
+			err = primReader.SkipValue(nbf)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		cols[pv.idx], err = conv.cols[pv.idx].TypeInfo.ReadFrom(nbf, primReader)
+
+		if err != nil {
+			return err
+		}
+
+		pos++
+	}
+
+	return nil
+}
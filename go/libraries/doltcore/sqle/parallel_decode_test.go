@@ -0,0 +1,115 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// wideRowTuples returns the columns of a row with numCols columns after its primary key, of several kinds, and a key
+// and value tuple for it in which every seventh column is NULL
+func wideRowTuples(tb testing.TB, numCols int) ([]schema.Column, types.Tuple, types.Tuple) {
+	cols := []schema.Column{schema.NewColumn("pk", 0, types.IntKind, true)}
+	var vals []types.Value
+	created := time.Date(2021, 3, 14, 15, 9, 26, 0, time.UTC)
+	for i := 1; i <= numCols; i++ {
+		tag := uint64(i)
+		var col schema.Column
+		var val types.Value
+		switch i % 4 {
+		case 0:
+			col, val = schema.NewColumn(fmt.Sprintf("i%d", i), tag, types.IntKind, false), types.Int(-i)
+		case 1:
+			col, val = schema.NewColumn(fmt.Sprintf("s%d", i), tag, types.StringKind, false), types.String(strings.Repeat("x", i%20))
+		case 2:
+			col, val = schema.NewColumn(fmt.Sprintf("f%d", i), tag, types.FloatKind, false), types.Float(float64(i)/4)
+		default:
+			col, val = schema.NewColumn(fmt.Sprintf("t%d", i), tag, types.TimestampKind, false), types.Timestamp(created.Add(time.Duration(i)*time.Hour))
+		}
+
+		cols = append(cols, col)
+		if i%7 != 0 {
+			vals = append(vals, types.Uint(tag), val)
+		}
+	}
+
+	k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(42))
+	require.NoError(tb, err)
+	v, err := types.NewTuple(types.Format_Default, vals...)
+	require.NoError(tb, err)
+
+	return cols, k, v
+}
+
+func TestWithParallelDecode(t *testing.T) {
+	cols, k, v := wideRowTuples(t, 500)
+
+	serial, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols)
+	require.NoError(t, err)
+	expected, expectedSize, err := serial.ConvertKVToSqlRowWithSize(k, v)
+	require.NoError(t, err)
+
+	for _, workers := range []int{2, 3, 8, 1000} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithParallelDecode(100, workers))
+			require.NoError(t, err)
+			assert.True(t, conv.parallelDecode)
+
+			r, size, err := conv.ConvertKVToSqlRowWithSize(k, v)
+			require.NoError(t, err)
+			assert.Equal(t, expected, r)
+			assert.Equal(t, expectedSize, size)
+		})
+	}
+
+	// a projection narrower than the threshold is converted serially
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols[:50], WithParallelDecode(100, 4))
+	require.NoError(t, err)
+	assert.False(t, conv.parallelDecode)
+
+	// so is a converter whose values are masked
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithParallelDecode(100, 4), WithMaskedColumns(map[uint64]interface{}{3: "***"}))
+	require.NoError(t, err)
+	assert.False(t, conv.parallelDecode)
+
+	_, err = NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithParallelDecode(0, 4))
+	assert.Error(t, err)
+}
+
+func BenchmarkParallelDecode(b *testing.B) {
+	cols, k, v := wideRowTuples(b, 500)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("%d workers", workers), func(b *testing.B) {
+			conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, cols, WithParallelDecode(100, workers))
+			require.NoError(b, err)
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := conv.ConvertKVTuplesToSqlRow(k, v)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
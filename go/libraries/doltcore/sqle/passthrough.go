@@ -0,0 +1,165 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ConvertKVTuplesToNomsValues reads the columns being converted from the key and value tuples given as the stored
+// types.Values, without converting them to SQL values, and returns them at the output positions of the converter.
+// Columns which are missing from the tuples, as NULLs are stored, are nil.  It is the passthrough used to copy rows
+// between maps with the same schema, where converting values to SQL values and back with NomsValuesToKVTuples is
+// wasted work.  Options which change the converted SQL values, such as value readers, transforms and masks, are not
+// applied, and the key predicate of WithKeyPredicate is not evaluated.
+func (conv *KVToSqlRowConverter) ConvertKVTuplesToNomsValues(k, v types.Tuple) ([]types.Value, error) {
+	vals := make([]types.Value, conv.rowSize)
+
+	if conv.valsFromKey > 0 {
+		err := conv.readNomsValues(vals, conv.valsFromKey, 0xFFFFFFFFFFFFFFFF, k)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conv.valsFromVal > 0 {
+		maxTag := conv.maxValTag
+		if conv.valEncoding == UnsortedTagsValTupleEncoding {
+			maxTag = 0xFFFFFFFFFFFFFFFF
+		}
+
+		err := conv.readNomsValues(vals, conv.valsFromVal, maxTag, v)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return vals, nil
+}
+
+// readNomsValues reads the values of the tags being converted from tup into vals, stopping once valsToFill values are
+// read or a tag greater than maxTag is found
+func (conv *KVToSqlRowConverter) readNomsValues(vals []types.Value, valsToFill int, maxTag uint64, tup types.Tuple) error {
+	filled := 0
+	idx := -1
+	return tup.IterFields(func(pos uint64, field types.Value) (stop bool, err error) {
+		if pos%2 == 0 {
+			tag, ok := field.(types.Uint)
+
+			if !ok {
+				return false, errors.New("Encountered unexpected kind while attempting to read tag")
+			}
+
+			if uint64(tag) > maxTag {
+				return true, nil
+			}
+
+			sqlColIdx, ok := conv.tagToSqlColIdx[uint64(tag)]
+
+			if !ok {
+				sqlColIdx = -1
+			}
+
+			idx = sqlColIdx
+			return false, nil
+		}
+
+		if idx >= 0 {
+			if !types.IsNull(field) {
+				vals[idx] = field
+			}
+
+			filled++
+		}
+
+		return filled >= valsToFill, nil
+	})
+}
+
+// NomsValuesToKVTuples returns the key and value tuples of a row of sch holding the values given, which must be at the
+// output positions of the converter as returned by ConvertKVTuplesToNomsValues.  It is the writer paired with
+// ConvertKVTuplesToNomsValues, and encodes the tuples directly from the values, so rows can be copied between maps with
+// the same schema without a round trip through SQL values or row.Row.  As with row.Row, a primary key column without a
+// value is stored as NULL, nil values are left out of the value tuple, and values of columns which are not in sch are
+// dropped.  Values are not checked against the kinds of their columns.
+func (conv *KVToSqlRowConverter) NomsValuesToKVTuples(ctx context.Context, sch schema.Schema, vals []types.Value) (types.Tuple, types.Tuple, error) {
+	if len(vals) != conv.rowSize {
+		return types.Tuple{}, types.Tuple{}, fmt.Errorf("expected %d values but received %d", conv.rowSize, len(vals))
+	}
+
+	if schema.IsKeyless(sch) {
+		return conv.keylessNomsValuesToKVTuples(ctx, sch, vals)
+	}
+
+	pkTags := sch.GetPKCols().Tags
+	keyVals := make([]types.Value, 0, 2*len(pkTags))
+	for _, tag := range pkTags {
+		var val types.Value = types.NullValue
+		if idx, ok := conv.tagToSqlColIdx[tag]; ok && vals[idx] != nil {
+			val = vals[idx]
+		}
+
+		keyVals = append(keyVals, types.Uint(tag), val)
+	}
+
+	nonPKTags := sch.GetNonPKCols().SortedTags
+	valVals := make([]types.Value, 0, 2*len(nonPKTags))
+	for _, tag := range nonPKTags {
+		if idx, ok := conv.tagToSqlColIdx[tag]; ok && vals[idx] != nil {
+			valVals = append(valVals, types.Uint(tag), vals[idx])
+		}
+	}
+
+	k, err := types.NewTuple(conv.nbf, keyVals...)
+
+	if err != nil {
+		return types.Tuple{}, types.Tuple{}, err
+	}
+
+	v, err := types.NewTuple(conv.nbf, valVals...)
+
+	if err != nil {
+		return types.Tuple{}, types.Tuple{}, err
+	}
+
+	return k, v, nil
+}
+
+// keylessNomsValuesToKVTuples builds the tuples of a row of a keyless schema, whose key is derived from its values,
+// through row.Row
+func (conv *KVToSqlRowConverter) keylessNomsValuesToKVTuples(ctx context.Context, sch schema.Schema, vals []types.Value) (types.Tuple, types.Tuple, error) {
+	taggedVals := make(row.TaggedValues, len(conv.tagToSqlColIdx))
+	for tag, idx := range conv.tagToSqlColIdx {
+		if vals[idx] != nil {
+			taggedVals[tag] = vals[idx]
+		}
+	}
+
+	r, err := row.New(conv.nbf, sch, taggedVals)
+
+	if err != nil {
+		return types.Tuple{}, types.Tuple{}, err
+	}
+
+	return row.ToNoms(ctx, sch, r)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNomsValuesPassthrough(t *testing.T) {
+	ctx := context.Background()
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...))
+	kvs := columnValueTestKVs(t, 6)
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	for i := 0; i < len(kvs); i += 2 {
+		vals, err := conv.ConvertKVTuplesToNomsValues(kvs[i], kvs[i+1])
+		require.NoError(t, err)
+		assert.Equal(t, types.Int(i/2), vals[0])
+
+		k, v, err := conv.NomsValuesToKVTuples(ctx, sch, vals)
+		require.NoError(t, err)
+		assert.True(t, kvs[i].Equals(k))
+		assert.True(t, kvs[i+1].Equals(v))
+	}
+
+	// the name of row 0 is NULL, and values outside the projection are not read
+	conv, err = NewKVToSqlRowConverter(types.Format_Default, map[uint64]int{mapIterNameTag: 0, mapIterAgeTag: 1}, []schema.Column{mapIterTestCols[1], mapIterTestCols[2]}, 2)
+	require.NoError(t, err)
+	vals, err := conv.ConvertKVTuplesToNomsValues(kvs[0], kvs[1])
+	require.NoError(t, err)
+	assert.Equal(t, []types.Value{nil, types.Uint(0)}, vals)
+	vals, err = conv.ConvertKVTuplesToNomsValues(kvs[2], kvs[3])
+	require.NoError(t, err)
+	assert.Equal(t, []types.Value{types.String("name"), types.Uint(1)}, vals)
+
+	_, _, err = conv.NomsValuesToKVTuples(ctx, sch, vals[:1])
+	assert.Error(t, err)
+}
+
+func BenchmarkNomsValuesPassthrough(b *testing.B) {
+	ctx := context.Background()
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...))
+	kvs := columnValueTestKVs(b, 10000)
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(b, err)
+
+	b.Run("passthrough", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < len(kvs); j += 2 {
+				vals, err := conv.ConvertKVTuplesToNomsValues(kvs[j], kvs[j+1])
+				require.NoError(b, err)
+				_, _, err = conv.NomsValuesToKVTuples(ctx, sch, vals)
+				require.NoError(b, err)
+			}
+		}
+	})
+
+	b.Run("sql round trip", func(b *testing.B) {
+		vrw := types.NewMemoryValueStore()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < len(kvs); j += 2 {
+				r, err := conv.ConvertKVTuplesToSqlRow(kvs[j], kvs[j+1])
+				require.NoError(b, err)
+				dRow, err := sqlutil.SqlRowToDoltRow(ctx, vrw, r, sch)
+				require.NoError(b, err)
+				_, _, err = row.ToNoms(ctx, sch, dRow)
+				require.NoError(b, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var errPrefetcherClosed = errors.New("prefetching key value getter has been closed")
+
+type kvOrErr struct {
+	k, v types.Tuple
+	err  error
+}
+
+// PrefetchKVGetter reads ahead from a KVGetFunc on a background goroutine so that fetching key value pairs, which may
+// wait on disk or network I/O, overlaps with converting them.  Pairs and errors are returned in the order the wrapped
+// KVGetFunc produced them.  Once an error, including io.EOF, is returned it is returned for every following call.
+type PrefetchKVGetter struct {
+	get      KVGetFunc
+	closeGet func() error
+	kvCh     chan kvOrErr
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+	start    sync.Once
+	stop     sync.Once
+	err      error
+}
+
+// NewPrefetchKVGetter returns a PrefetchKVGetter which buffers up to size pairs read from get.  closeGet, which may be
+// nil, is called when the PrefetchKVGetter is closed.  The background goroutine is started by the first call to Get.
+func NewPrefetchKVGetter(get KVGetFunc, closeGet func() error, size int) (*PrefetchKVGetter, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid prefetch size %d", size)
+	}
+
+	return &PrefetchKVGetter{
+		get:      get,
+		closeGet: closeGet,
+		kvCh:     make(chan kvOrErr, size),
+		stopCh:   make(chan struct{}),
+	}, nil
+}
+
+// NewPrefetchingDoltMapIter returns a DoltMapIter which reads up to size pairs ahead of the rows being converted
+func NewPrefetchingDoltMapIter(ctx context.Context, keyValGet KVGetFunc, closeKVGetter func() error, conv *KVToSqlRowConverter, size int) (*DoltMapIter, error) {
+	prefetcher, err := NewPrefetchKVGetter(keyValGet, closeKVGetter, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDoltMapIter(ctx, prefetcher.Get, prefetcher.Close, conv), nil
+}
+
+// Get returns the next key value pair.  It has the signature of a KVGetFunc.  The context of the first call is used
+// for every read made by the background goroutine.
+func (p *PrefetchKVGetter) Get(ctx context.Context) (types.Tuple, types.Tuple, error) {
+	if p.err != nil {
+		return types.Tuple{}, types.Tuple{}, p.err
+	}
+
+	select {
+	case <-p.stopCh:
+		return types.Tuple{}, types.Tuple{}, errPrefetcherClosed
+	default:
+	}
+
+	p.start.Do(func() {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.prefetch(ctx)
+		}()
+	})
+
+	select {
+	case kv := <-p.kvCh:
+		if kv.err != nil {
+			p.err = kv.err
+			return types.Tuple{}, types.Tuple{}, kv.err
+		}
+
+		return kv.k, kv.v, nil
+	case <-p.stopCh:
+		return types.Tuple{}, types.Tuple{}, errPrefetcherClosed
+	case <-ctx.Done():
+		return types.Tuple{}, types.Tuple{}, ctx.Err()
+	}
+}
+
+// prefetch reads pairs into the buffer until an error is read or the getter is closed
+func (p *PrefetchKVGetter) prefetch(ctx context.Context) {
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		default:
+		}
+
+		k, v, err := p.get(ctx)
+
+		select {
+		case p.kvCh <- kvOrErr{k, v, err}:
+		case <-p.stopCh:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Close stops the background goroutine, waiting for any read in progress to finish, and then closes the wrapped
+// KVGetFunc.  It is safe to call Close before all pairs have been read.
+func (p *PrefetchKVGetter) Close() error {
+	p.stop.Do(func() {
+		close(p.stopCh)
+	})
+
+	p.wg.Wait()
+
+	if p.closeGet != nil {
+		return p.closeGet()
+	}
+
+	return nil
+}
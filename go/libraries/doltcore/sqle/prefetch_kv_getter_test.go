@@ -0,0 +1,143 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// slowKVGetFunc returns the pairs given, sleeping before each read, followed by finalErr.  calls counts the reads made.
+func slowKVGetFunc(kvs []types.Tuple, delay time.Duration, finalErr error, calls *int32) KVGetFunc {
+	get := kvGetFuncForTuples(kvs...)
+	return func(ctx context.Context) (types.Tuple, types.Tuple, error) {
+		atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+
+		k, v, err := get(ctx)
+
+		if err == io.EOF {
+			return k, v, finalErr
+		}
+
+		return k, v, err
+	}
+}
+
+func waitForCalls(t *testing.T, calls *int32, expected int32) {
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(calls) < expected {
+		require.True(t, time.Now().Before(deadline), "expected %d reads but only saw %d", expected, atomic.LoadInt32(calls))
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPrefetchingDoltMapIter(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t,
+		[]interface{}{1, 10, "a"},
+		[]interface{}{2, 20, "b"},
+		[]interface{}{3, 30, "c"},
+		[]interface{}{4, 40, "d"},
+		[]interface{}{5, 50, "e"},
+	)
+
+	_, err = NewPrefetchKVGetter(kvGetFuncForTuples(kvs...), nil, 0)
+	assert.Error(t, err)
+
+	t.Run("reads ahead while rows are converted", func(t *testing.T) {
+		var calls int32
+		closed := 0
+		dmi, err := NewPrefetchingDoltMapIter(ctx, slowKVGetFunc(kvs, 5*time.Millisecond, io.EOF, &calls), func() error { closed++; return nil }, conv, 2)
+		require.NoError(t, err)
+
+		r, err := dmi.Next()
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{int64(1), int64(10), "a"}, r)
+
+		// while the caller holds the first row the prefetcher fills its buffer of 2 and reads one more pair which it
+		// waits to add to the buffer
+		waitForCalls(t, &calls, 4)
+
+		rows := drainRowIter(t, dmi)
+		assert.Equal(t, []sql.Row{
+			{int64(2), int64(20), "b"},
+			{int64(3), int64(30), "c"},
+			{int64(4), int64(40), "d"},
+			{int64(5), int64(50), "e"},
+		}, rows)
+
+		_, err = dmi.Next()
+		assert.Equal(t, io.EOF, err)
+
+		require.NoError(t, dmi.Close(sql.NewEmptyContext()))
+		assert.Equal(t, 1, closed)
+	})
+
+	t.Run("errors are returned in order", func(t *testing.T) {
+		var calls int32
+		readErr := errors.New("read failed")
+		dmi, err := NewPrefetchingDoltMapIter(ctx, slowKVGetFunc(kvs[:4], 0, readErr, &calls), nil, conv, 4)
+		require.NoError(t, err)
+
+		for i := 1; i <= 2; i++ {
+			r, err := dmi.Next()
+			require.NoError(t, err)
+			assert.Equal(t, int64(i), r[0])
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err = dmi.Next()
+			assert.Equal(t, readErr, err)
+		}
+
+		require.NoError(t, dmi.Close(sql.NewEmptyContext()))
+	})
+
+	t.Run("close before all rows are read", func(t *testing.T) {
+		var calls int32
+		closed := 0
+		dmi, err := NewPrefetchingDoltMapIter(ctx, slowKVGetFunc(kvs, 0, io.EOF, &calls), func() error { closed++; return nil }, conv, 1)
+		require.NoError(t, err)
+
+		_, err = dmi.Next()
+		require.NoError(t, err)
+
+		// the prefetcher is blocked waiting for room in its full buffer when the iterator is closed
+		waitForCalls(t, &calls, 3)
+		require.NoError(t, dmi.Close(sql.NewEmptyContext()))
+		assert.Equal(t, 1, closed)
+
+		callsAtClose := atomic.LoadInt32(&calls)
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, callsAtClose, atomic.LoadInt32(&calls))
+
+		_, err = dmi.Next()
+		assert.Error(t, err)
+	})
+}
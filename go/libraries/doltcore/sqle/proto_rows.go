@@ -0,0 +1,268 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+var (
+	protoInt32Kinds  = []protoreflect.Kind{protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind}
+	protoInt64Kinds  = []protoreflect.Kind{protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind}
+	protoUint32Kinds = []protoreflect.Kind{protoreflect.Uint32Kind, protoreflect.Fixed32Kind}
+	protoUint64Kinds = []protoreflect.Kind{protoreflect.Uint64Kind, protoreflect.Fixed64Kind}
+)
+
+// ProtoRowMapper maps the rows produced by a KVToSqlRowConverter to dynamic protobuf messages of a given descriptor so
+// that query results can be streamed as typed messages without a hand written mapping for each table.  Each column is
+// stored in the field with the same name, matched case-insensitively if there is no exact match.  Fields which no
+// column maps to are left unset.
+//
+// Columns are stored in fields of the following kinds:
+//   - int columns in int32, sint32 and sfixed32 fields, or in their 64 bit equivalents
+//   - uint columns in uint32 and fixed32 fields, or in their 64 bit equivalents.  bit columns need 64 bit fields
+//   - year columns in any signed integer field
+//   - bool columns in bool fields
+//   - float columns in float fields, and in double fields
+//   - varbinary and inlineblob columns in bytes fields
+//   - all other columns in string fields, with datetimes formatted as RFC 3339
+//
+// Fields of 32 bit kinds only accept columns whose values fit in 32 bits.  NULL values leave the field unset, so every
+// nullable column must be mapped to a field which tracks presence, such as a proto2 or proto3 optional field.
+type ProtoRowMapper struct {
+	desc   protoreflect.MessageDescriptor
+	fields []protoreflect.FieldDescriptor
+	names  []string
+}
+
+// NewProtoRowMapper returns a ProtoRowMapper which maps the rows produced by conv to messages of the descriptor given.
+// An error naming the column is returned if a column has no matching field, or has a field it can't be stored in.
+func NewProtoRowMapper(conv *KVToSqlRowConverter, desc protoreflect.MessageDescriptor) (*ProtoRowMapper, error) {
+	m := &ProtoRowMapper{
+		desc:   desc,
+		fields: make([]protoreflect.FieldDescriptor, conv.rowSize),
+		names:  make([]string, conv.rowSize),
+	}
+
+	for _, idx := range conv.tagToSqlColIdx {
+		col := conv.cols[idx]
+		field := protoFieldForName(desc, col.Name)
+
+		if field == nil {
+			return nil, fmt.Errorf("column '%s' has no matching field in message %s", col.Name, desc.FullName())
+		}
+
+		if field.Cardinality() == protoreflect.Repeated {
+			return nil, fmt.Errorf("column '%s' cannot be stored in repeated field %s", col.Name, field.FullName())
+		}
+
+		if !protoKindAllowed(col.TypeInfo, field.Kind()) {
+			return nil, fmt.Errorf("column '%s' of type %s cannot be stored in field %s of kind %s", col.Name, col.TypeInfo.String(), field.FullName(), field.Kind())
+		}
+
+		if col.IsNullable() && !field.HasPresence() {
+			return nil, fmt.Errorf("column '%s' is nullable but field %s cannot be left unset", col.Name, field.FullName())
+		}
+
+		m.fields[idx] = field
+		m.names[idx] = col.Name
+	}
+
+	return m, nil
+}
+
+// Message returns a new message holding the values of the row given
+func (m *ProtoRowMapper) Message(r sql.Row) (*dynamicpb.Message, error) {
+	msg := dynamicpb.NewMessage(m.desc)
+
+	for idx, field := range m.fields {
+		if field == nil || idx >= len(r) || r[idx] == nil {
+			continue
+		}
+
+		val, err := protoValue(field.Kind(), r[idx])
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot store column '%s' in field %s: %w", m.names[idx], field.FullName(), err)
+		}
+
+		msg.Set(field, val)
+	}
+
+	return msg, nil
+}
+
+// ProtoMessageIter returns the rows of a sql.RowIter as protobuf messages
+type ProtoMessageIter struct {
+	itr    sql.RowIter
+	mapper *ProtoRowMapper
+}
+
+// NewProtoMessageIter returns a ProtoMessageIter which maps the rows of itr using the mapper given
+func NewProtoMessageIter(itr sql.RowIter, mapper *ProtoRowMapper) *ProtoMessageIter {
+	return &ProtoMessageIter{itr: itr, mapper: mapper}
+}
+
+// Next returns the message for the next row until all rows are returned at which point (nil, io.EOF) is returned.
+func (pmi *ProtoMessageIter) Next() (*dynamicpb.Message, error) {
+	r, err := pmi.itr.Next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pmi.mapper.Message(r)
+}
+
+// Close closes the underlying sql.RowIter
+func (pmi *ProtoMessageIter) Close(ctx *sql.Context) error {
+	return pmi.itr.Close(ctx)
+}
+
+func protoFieldForName(desc protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := desc.Fields()
+
+	if field := fields.ByName(protoreflect.Name(name)); field != nil {
+		return field
+	}
+
+	for i := 0; i < fields.Len(); i++ {
+		if strings.EqualFold(string(fields.Get(i).Name()), name) {
+			return fields.Get(i)
+		}
+	}
+
+	return nil
+}
+
+// protoKindAllowed returns true if values of the type given can be stored in a field of the kind given
+func protoKindAllowed(ti typeinfo.TypeInfo, kind protoreflect.Kind) bool {
+	var allowed []protoreflect.Kind
+	switch ti.GetTypeIdentifier() {
+	case typeinfo.IntTypeIdentifier:
+		allowed = protoInt64Kinds
+		if ti.ToSqlType().Type() != query.Type_INT64 {
+			allowed = append(allowed, protoInt32Kinds...)
+		}
+	case typeinfo.UintTypeIdentifier:
+		allowed = protoUint64Kinds
+		if ti.ToSqlType().Type() != query.Type_UINT64 {
+			allowed = append(allowed, protoUint32Kinds...)
+		}
+	case typeinfo.BitTypeIdentifier:
+		allowed = protoUint64Kinds
+	case typeinfo.YearTypeIdentifier:
+		allowed = append(protoInt32Kinds, protoInt64Kinds...)
+	case typeinfo.BoolTypeIdentifier:
+		allowed = []protoreflect.Kind{protoreflect.BoolKind}
+	case typeinfo.FloatTypeIdentifier:
+		allowed = []protoreflect.Kind{protoreflect.DoubleKind}
+		if ti.ToSqlType().Type() == query.Type_FLOAT32 {
+			allowed = append(allowed, protoreflect.FloatKind)
+		}
+	case typeinfo.VarBinaryTypeIdentifier, typeinfo.InlineBlobTypeIdentifier:
+		allowed = []protoreflect.Kind{protoreflect.BytesKind}
+	case typeinfo.UnknownTypeIdentifier, typeinfo.TupleTypeIdentifier:
+		return false
+	default:
+		allowed = []protoreflect.Kind{protoreflect.StringKind}
+	}
+
+	for _, k := range allowed {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// protoValue converts a non-NULL sql value to a value for a field of the kind given
+func protoValue(kind protoreflect.Kind, val interface{}) (protoreflect.Value, error) {
+	rv := reflect.ValueOf(val)
+
+	switch kind {
+	case protoreflect.BoolKind:
+		switch {
+		case rv.Kind() == reflect.Bool:
+			return protoreflect.ValueOfBool(rv.Bool()), nil
+		case isIntKind(rv.Kind()):
+			return protoreflect.ValueOfBool(rv.Int() != 0), nil
+		case isUintKind(rv.Kind()):
+			return protoreflect.ValueOfBool(rv.Uint() != 0), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if isIntKind(rv.Kind()) {
+			if rv.Int() < math.MinInt32 || rv.Int() > math.MaxInt32 {
+				return protoreflect.Value{}, fmt.Errorf("value %d overflows %s", rv.Int(), kind)
+			}
+
+			return protoreflect.ValueOfInt32(int32(rv.Int())), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if isIntKind(rv.Kind()) {
+			return protoreflect.ValueOfInt64(rv.Int()), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if isUintKind(rv.Kind()) {
+			if rv.Uint() > math.MaxUint32 {
+				return protoreflect.Value{}, fmt.Errorf("value %d overflows %s", rv.Uint(), kind)
+			}
+
+			return protoreflect.ValueOfUint32(uint32(rv.Uint())), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if isUintKind(rv.Kind()) {
+			return protoreflect.ValueOfUint64(rv.Uint()), nil
+		}
+	case protoreflect.FloatKind:
+		if isFloatKind(rv.Kind()) {
+			return protoreflect.ValueOfFloat32(float32(rv.Float())), nil
+		}
+	case protoreflect.DoubleKind:
+		if isFloatKind(rv.Kind()) {
+			return protoreflect.ValueOfFloat64(rv.Float()), nil
+		}
+	case protoreflect.StringKind:
+		switch v := val.(type) {
+		case string:
+			return protoreflect.ValueOfString(v), nil
+		case time.Time:
+			return protoreflect.ValueOfString(v.Format(time.RFC3339Nano)), nil
+		case fmt.Stringer:
+			return protoreflect.ValueOfString(v.String()), nil
+		}
+	case protoreflect.BytesKind:
+		switch v := val.(type) {
+		case string:
+			return protoreflect.ValueOfBytes([]byte(v)), nil
+		case []byte:
+			return protoreflect.ValueOfBytes(v), nil
+		}
+	}
+
+	return protoreflect.Value{}, fmt.Errorf("value of type %T cannot be stored in a field of kind %s", val, kind)
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+type protoTestField struct {
+	name     string
+	typ      descriptorpb.FieldDescriptorProto_Type
+	optional bool
+}
+
+// protoTestDescriptor builds a message descriptor named Row with the fields given
+func protoTestDescriptor(t *testing.T, syntax string, fields ...protoTestField) protoreflect.MessageDescriptor {
+	msg := &descriptorpb.DescriptorProto{Name: proto.String("Row")}
+	for i, f := range fields {
+		label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		fieldProto := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(f.name),
+			JsonName: proto.String(f.name),
+			Number:   proto.Int32(int32(i + 1)),
+			Label:    &label,
+			Type:     f.typ.Enum(),
+		}
+
+		if f.optional && syntax == "proto3" {
+			fieldProto.Proto3Optional = proto.Bool(true)
+			fieldProto.OneofIndex = proto.Int32(int32(len(msg.OneofDecl)))
+			msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + f.name)})
+		}
+
+		msg.Field = append(msg.Field, fieldProto)
+	}
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:        proto.String("row.proto"),
+		Package:     proto.String("test"),
+		Syntax:      proto.String(syntax),
+		MessageType: []*descriptorpb.DescriptorProto{msg},
+	}, nil)
+	require.NoError(t, err)
+
+	return fd.Messages().Get(0)
+}
+
+func TestProtoRowMapper(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	desc := protoTestDescriptor(t, "proto3",
+		protoTestField{"id", descriptorpb.FieldDescriptorProto_TYPE_SINT64, false},
+		protoTestField{"Name", descriptorpb.FieldDescriptorProto_TYPE_STRING, true},
+		protoTestField{"age", descriptorpb.FieldDescriptorProto_TYPE_FIXED64, true},
+		protoTestField{"score", descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, true},
+		protoTestField{"created", descriptorpb.FieldDescriptorProto_TYPE_STRING, true},
+		protoTestField{"unmapped", descriptorpb.FieldDescriptorProto_TYPE_BOOL, true},
+	)
+
+	mapper, err := NewProtoRowMapper(conv, desc)
+	require.NoError(t, err)
+
+	k1, v1 := mapIterTestTuples(t, 1, types.String("bill"), types.Uint(32), types.Float(1.5), types.Timestamp(created))
+	k2, v2 := mapIterTestTuples(t, -2, nil, types.Uint(0), nil, nil)
+	itr := NewProtoMessageIter(NewDoltMapIter(ctx, kvGetFuncForTuples(k1, v1, k2, v2), nil, conv), mapper)
+
+	var decoded []*dynamicpb.Message
+	for {
+		msg, err := itr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := proto.Marshal(msg)
+		require.NoError(t, err)
+
+		roundTripped := dynamicpb.NewMessage(desc)
+		require.NoError(t, proto.Unmarshal(data, roundTripped))
+		decoded = append(decoded, roundTripped)
+	}
+
+	require.Len(t, decoded, 2)
+	fields := desc.Fields()
+
+	first := decoded[0]
+	assert.Equal(t, int64(1), first.Get(fields.ByName("id")).Int())
+	assert.Equal(t, "bill", first.Get(fields.ByName("Name")).String())
+	assert.Equal(t, uint64(32), first.Get(fields.ByName("age")).Uint())
+	assert.Equal(t, 1.5, first.Get(fields.ByName("score")).Float())
+	assert.Equal(t, "2020-01-02T03:04:05Z", first.Get(fields.ByName("created")).String())
+	assert.False(t, first.Has(fields.ByName("unmapped")))
+
+	second := decoded[1]
+	assert.Equal(t, int64(-2), second.Get(fields.ByName("id")).Int())
+	assert.False(t, second.Has(fields.ByName("Name")))
+	assert.True(t, second.Has(fields.ByName("age")), "a zero value should be set rather than unset")
+	assert.False(t, second.Has(fields.ByName("score")))
+	assert.False(t, second.Has(fields.ByName("created")))
+}
+
+func TestProtoRowMapperMismatches(t *testing.T) {
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	validFields := func() []protoTestField {
+		return []protoTestField{
+			{"id", descriptorpb.FieldDescriptorProto_TYPE_INT64, false},
+			{"name", descriptorpb.FieldDescriptorProto_TYPE_STRING, true},
+			{"age", descriptorpb.FieldDescriptorProto_TYPE_UINT64, true},
+			{"score", descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, true},
+			{"created", descriptorpb.FieldDescriptorProto_TYPE_STRING, true},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		syntax      string
+		fields      func() []protoTestField
+		expectedErr string
+	}{
+		{
+			name:   "proto2",
+			syntax: "proto2",
+			fields: validFields,
+		},
+		{
+			name:   "missing field",
+			syntax: "proto3",
+			fields: func() []protoTestField {
+				return validFields()[:4]
+			},
+			expectedErr: "column 'created' has no matching field in message test.Row",
+		},
+		{
+			name:   "wrong kind",
+			syntax: "proto3",
+			fields: func() []protoTestField {
+				fields := validFields()
+				fields[3].typ = descriptorpb.FieldDescriptorProto_TYPE_FLOAT
+				return fields
+			},
+			expectedErr: "column 'score' of type Float64 cannot be stored in field test.Row.score of kind float",
+		},
+		{
+			name:   "narrower int",
+			syntax: "proto3",
+			fields: func() []protoTestField {
+				fields := validFields()
+				fields[0].typ = descriptorpb.FieldDescriptorProto_TYPE_INT32
+				return fields
+			},
+			expectedErr: "column 'id' of type Int64 cannot be stored in field test.Row.id of kind int32",
+		},
+		{
+			name:   "nullable without presence",
+			syntax: "proto3",
+			fields: func() []protoTestField {
+				fields := validFields()
+				fields[1].optional = false
+				return fields
+			},
+			expectedErr: "column 'name' is nullable but field test.Row.name cannot be left unset",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewProtoRowMapper(conv, protoTestDescriptor(t, test.syntax, test.fields()...))
+
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
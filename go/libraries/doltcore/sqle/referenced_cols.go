@@ -0,0 +1,143 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// NewKVToSqlRowConverterForReferencedCols returns a KVToSqlRowConverter which decodes exactly the columns of sch an
+// expression tree references, leaving the others unread.  outputTags are the columns which are projected, in the order
+// they are output, and readOnlyTags are the columns which are only needed to evaluate filters.  The rows produced by the
+// converter hold the output columns followed by the read-only columns, so that the predicates of a DoltMapIter given to
+// FilterRows can use them, while the rows returned by the DoltMapIter hold only the output columns.  A read-only tag
+// which is also an output tag is only output.  An error is returned if a tag is not a column of sch or is output more
+// than once.
+func NewKVToSqlRowConverterForReferencedCols(nbf *types.NomsBinFormat, sch schema.Schema, outputTags, readOnlyTags []uint64, opts ...KVToSqlRowConverterOption) (*KVToSqlRowConverter, error) {
+	allCols := sch.GetAllCols()
+	tagToSqlColIdx := make(map[uint64]int, len(outputTags)+len(readOnlyTags))
+	cols := make([]schema.Column, 0, len(outputTags)+len(readOnlyTags))
+	for i, tags := range [][]uint64{outputTags, readOnlyTags} {
+		isOutput := i == 0
+		for _, tag := range tags {
+			col, ok := allCols.GetByTag(tag)
+
+			if !ok {
+				return nil, fmt.Errorf("tag %d is not a column of the schema", tag)
+			}
+
+			if _, ok := tagToSqlColIdx[tag]; ok {
+				if isOutput {
+					return nil, fmt.Errorf("column '%s' is output more than once", col.Name)
+				}
+
+				continue
+			}
+
+			tagToSqlColIdx[tag] = len(cols)
+			cols = append(cols, col)
+		}
+	}
+
+	conv, err := NewKVToSqlRowConverter(nbf, tagToSqlColIdx, cols, len(cols), opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	conv.numOutputCols = len(outputTags)
+	return conv, nil
+}
+
+// Projection is the minimal set of columns of a table which must be decoded to produce the rows of a sql.Schema.  It
+// is returned by ProjectionForSqlSchema.
+type Projection struct {
+	// TagToSqlColIdx maps the tag of each column which is decoded to its index in the rows of the sql.Schema
+	TagToSqlColIdx map[uint64]int
+	// Cols holds the decoded column written to each index of a row.  The indexes of missing columns hold the zero
+	// schema.Column.
+	Cols []schema.Column
+	// Missing are the names of the columns of the sql.Schema which are not columns of the table, in sql.Schema order
+	Missing []string
+}
+
+// ProjectionForSqlSchema returns the columns of sch which must be decoded to produce rows of sqlSch, matching columns
+// by name ignoring case, so that callers decode no column which is not output.  Unlike TagToSqlColIdxForSqlSchema a
+// column of sqlSch which is not in sch is not an error, and is reported in the projection's Missing columns instead.
+// Converters created from the projection leave the values of missing columns NULL, so callers must decide whether that
+// is acceptable.  An error is returned if a column of sch is output more than once.
+func ProjectionForSqlSchema(sch schema.Schema, sqlSch sql.Schema) (*Projection, error) {
+	allCols := sch.GetAllCols()
+	proj := &Projection{
+		TagToSqlColIdx: make(map[uint64]int, len(sqlSch)),
+		Cols:           make([]schema.Column, len(sqlSch)),
+	}
+
+	for i, sqlCol := range sqlSch {
+		col, ok := allCols.GetByNameCaseInsensitive(sqlCol.Name)
+
+		if !ok {
+			proj.Missing = append(proj.Missing, sqlCol.Name)
+			continue
+		}
+
+		if _, ok := proj.TagToSqlColIdx[col.Tag]; ok {
+			return nil, fmt.Errorf("column '%s' is output more than once", col.Name)
+		}
+
+		proj.TagToSqlColIdx[col.Tag] = i
+		proj.Cols[i] = col
+	}
+
+	return proj, nil
+}
+
+// Tags returns the tags of the columns which are decoded in increasing order
+func (proj *Projection) Tags() []uint64 {
+	tags := make([]uint64, 0, len(proj.TagToSqlColIdx))
+	for tag := range proj.TagToSqlColIdx {
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+	return tags
+}
+
+// NewConverter returns a KVToSqlRowConverter which decodes the projection's columns into rows of the sql.Schema it was
+// computed for
+func (proj *Projection) NewConverter(nbf *types.NomsBinFormat, opts ...KVToSqlRowConverterOption) (*KVToSqlRowConverter, error) {
+	return NewKVToSqlRowConverter(nbf, proj.TagToSqlColIdx, proj.Cols, len(proj.Cols), opts...)
+}
+
+// NumOutputCols returns the number of leading columns of each converted row which a DoltMapIter returns.  It is less
+// than the number of columns converted when the converter decodes read-only columns for filtering.
+func (conv *KVToSqlRowConverter) NumOutputCols() int {
+	return conv.numOutputCols
+}
+
+// outputRow returns the output columns of a converted row
+func (conv *KVToSqlRowConverter) outputRow(r sql.Row) sql.Row {
+	if len(r) > conv.numOutputCols {
+		return r[:conv.numOutputCols]
+	}
+
+	return r
+}
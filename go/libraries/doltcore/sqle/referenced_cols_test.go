@@ -0,0 +1,102 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNewKVToSqlRowConverterForReferencedCols(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...))
+
+	_, err := NewKVToSqlRowConverterForReferencedCols(types.Format_Default, sch, []uint64{mapIterNameTag, 99}, nil)
+	assert.Error(t, err)
+	_, err = NewKVToSqlRowConverterForReferencedCols(types.Format_Default, sch, []uint64{mapIterNameTag, mapIterNameTag}, nil)
+	assert.Error(t, err)
+
+	// age is only referenced by a filter, and name by both the projection and the filter
+	conv, err := NewKVToSqlRowConverterForReferencedCols(types.Format_Default, sch, []uint64{mapIterNameTag, mapIterPKTag}, []uint64{mapIterAgeTag, mapIterNameTag})
+	require.NoError(t, err)
+	assert.Equal(t, 2, conv.NumOutputCols())
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var kvs []types.Tuple
+	for i, name := range []string{"bill", "rob", "john", "andy"} {
+		k, v := mapIterTestTuples(t, int64(i), types.String(name), types.Uint(uint64(20+i*5)), types.Float(2.5), types.Timestamp(created))
+		kvs = append(kvs, k, v)
+	}
+
+	// the converter decodes the read-only column after the output columns
+	r, err := conv.ConvertKVTuplesToSqlRow(kvs[0], kvs[1])
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{"bill", int64(0), uint64(20)}, r)
+
+	// the iterator filters on it but doesn't return it
+	dmi := NewDoltMapIter(context.Background(), kvGetFuncForTuples(kvs...), nil, conv)
+	dmi.FilterRows(func(r sql.Row) bool { return r[2].(uint64) >= 30 && r[0] != "andy" })
+	assert.Equal(t, []sql.Row{{"john", int64(2)}}, drainRowIter(t, dmi))
+
+	// without read-only columns rows are returned in full
+	conv, err = NewKVToSqlRowConverterForReferencedCols(types.Format_Default, sch, []uint64{mapIterScoreTag}, nil)
+	require.NoError(t, err)
+	dmi = NewDoltMapIter(context.Background(), kvGetFuncForTuples(kvs...), nil, conv)
+	assert.Len(t, drainRowIter(t, dmi), 4)
+}
+
+func TestProjectionForSqlSchema(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(mapIterTestCols...))
+	k, v := mapIterTestTuples(t, 7, types.String("bill"), types.Uint(32), types.Float(2.5), nil)
+
+	t.Run("subset", func(t *testing.T) {
+		proj, err := ProjectionForSqlSchema(sch, sql.Schema{{Name: "AGE"}, {Name: "id"}})
+		require.NoError(t, err)
+		assert.Empty(t, proj.Missing)
+		assert.Equal(t, []uint64{mapIterPKTag, mapIterAgeTag}, proj.Tags())
+		assert.Equal(t, map[uint64]int{mapIterAgeTag: 0, mapIterPKTag: 1}, proj.TagToSqlColIdx)
+
+		conv, err := proj.NewConverter(types.Format_Default)
+		require.NoError(t, err)
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{uint64(32), int64(7)}, r)
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		proj, err := ProjectionForSqlSchema(sch, sql.Schema{{Name: "name"}, {Name: "nickname"}, {Name: "score"}, {Name: "height"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"nickname", "height"}, proj.Missing)
+		assert.Equal(t, []uint64{mapIterNameTag, mapIterScoreTag}, proj.Tags())
+
+		conv, err := proj.NewConverter(types.Format_Default)
+		require.NoError(t, err)
+		r, err := conv.ConvertKVTuplesToSqlRow(k, v)
+		require.NoError(t, err)
+		assert.Equal(t, sql.Row{"bill", nil, 2.5, nil}, r)
+	})
+
+	t.Run("output more than once", func(t *testing.T) {
+		_, err := ProjectionForSqlSchema(sch, sql.Schema{{Name: "name"}, {Name: "Name"}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// KVPredicate decides from the key and value tuples of a row, before it is converted, whether the row is kept.  As no
+// values are converted for rows which are dropped, it is the cheaper filter when a decision can be made from stored
+// values.
+type KVPredicate func(k, v types.Tuple) (bool, error)
+
+// RowPredicate decides from a converted row whether it is emitted.  It complements KVPredicate for decisions which
+// need SQL values or several columns, such as computed expressions.
+type RowPredicate func(r sql.Row) bool
+
+// ErrFilteredByKey is returned when converting a row whose key columns don't pass the key predicate of a converter
+// created with WithKeyPredicate.  DoltMapIter, its pipeline, ColumnValueIter, MergeIter and DiffIter skip these rows.
+// Code converting rows itself, such as with ConvertKVTuplesToSqlRow, must check for it.  KeyComparator and the index
+// row iterators create converters of their own, without a key predicate, so they never see it.
+var ErrFilteredByKey = errors.New("row filtered out by key predicate")
+
+// WithKeyPredicate makes the converter lazy about value tuples.  The key columns are read first and pred is called with
+// the row holding them, and the value tuple is only read if pred returns true, so no values are decoded for the rows
+// of a selective predicate which are dropped.  Only the positions of the key columns being converted are set in the
+// row pred is given, others are nil or hold the zero values of WithMissingValuesAsZero.  Rows which don't pass are not
+// converted and ErrFilteredByKey is returned in their place.
+func WithKeyPredicate(pred RowPredicate) KVToSqlRowConverterOption {
+	return func(conv *KVToSqlRowConverter) error {
+		conv.keyPred = pred
+		return nil
+	}
+}
+
+// TagValuePredicate returns a KVPredicate which keeps the rows where the column with the tag given holds a value for
+// which match returns true.  The column is looked for in the key tuple and then in the value tuple, and a column which
+// is in neither, as NULLs are stored, is matched as types.NullValue.
+func TagValuePredicate(tag uint64, match func(val types.Value) bool) KVPredicate {
+	return func(k, v types.Tuple) (bool, error) {
+		for _, tup := range []types.Tuple{k, v} {
+			val, ok, err := tupleValForTag(tup, tag)
+
+			if err != nil {
+				return false, err
+			}
+
+			if ok {
+				return match(val), nil
+			}
+		}
+
+		return match(types.NullValue), nil
+	}
+}
+
+// tupleValForTag returns the value stored with the tag given in a tuple of alternating tags and values
+func tupleValForTag(tup types.Tuple, tag uint64) (types.Value, bool, error) {
+	var val types.Value
+	found := false
+	isTag := true
+	err := tup.IterFields(func(_ uint64, field types.Value) (stop bool, err error) {
+		if isTag {
+			fieldTag, ok := field.(types.Uint)
+
+			if !ok {
+				return false, errors.New("Encountered unexpected kind while attempting to read tag")
+			}
+
+			found = uint64(fieldTag) == tag
+		} else if found {
+			val = field
+			return true, nil
+		}
+
+		isTag = !isTag
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return val, found, nil
+}
+
+// NewFilteredKVGetter returns a KVGetFunc which returns the key value pairs read from get for which pred returns true
+func NewFilteredKVGetter(get KVGetFunc, pred KVPredicate) KVGetFunc {
+	return func(ctx context.Context) (types.Tuple, types.Tuple, error) {
+		for {
+			k, v, err := get(ctx)
+
+			if err != nil {
+				return k, v, err
+			}
+
+			keep, err := pred(k, v)
+
+			if err != nil {
+				return types.Tuple{}, types.Tuple{}, err
+			}
+
+			if keep {
+				return k, v, nil
+			}
+		}
+	}
+}
+
+// FilterRows causes the iterator to skip the converted rows for which pred returns false.  Predicates are evaluated
+// after conversion, so rows can be filtered on their SQL values, and can be combined with a KVPredicate applied to the
+// iterator's source with NewFilteredKVGetter, which drops rows before they are converted.  Calling FilterRows more than
+// once keeps only the rows passing every predicate.  Skipped rows are not included in column stats, and Cursor
+// returns the position of the last row emitted.
+func (dmi *DoltMapIter) FilterRows(pred RowPredicate) {
+	dmi.predicates = append(dmi.predicates, pred)
+}
+
+func (dmi *DoltMapIter) passesRowPredicates(r sql.Row) bool {
+	for _, pred := range dmi.predicates {
+		if !pred(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDoltMapIterFilters(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t,
+		[]interface{}{1, 10, "apple"},
+		[]interface{}{2, 20, "avocado"},
+		[]interface{}{3, nil, "apricot"},
+		[]interface{}{4, 40, "banana"},
+		[]interface{}{5, 50, nil},
+		[]interface{}{6, 60, "almond"},
+	)
+
+	// drops rows whose a is NULL or less than 20 before they are converted
+	aAtLeast20 := TagValuePredicate(1, func(val types.Value) bool {
+		i, ok := val.(types.Int)
+		return ok && i >= 20
+	})
+
+	// drops converted rows whose b doesn't start with an a
+	bStartsWithA := func(r sql.Row) bool {
+		b, ok := r[2].(string)
+		return ok && strings.HasPrefix(b, "a")
+	}
+
+	dmi := NewDoltMapIter(ctx, NewFilteredKVGetter(kvGetFuncForTuples(kvs...), aAtLeast20), nil, conv)
+	dmi.FilterRows(bStartsWithA)
+	assert.Equal(t, []sql.Row{{int64(2), int64(20), "avocado"}, {int64(6), int64(60), "almond"}}, drainRowIter(t, dmi))
+
+	// each filter alone
+	dmi = NewDoltMapIter(ctx, NewFilteredKVGetter(kvGetFuncForTuples(kvs...), aAtLeast20), nil, conv)
+	assert.Len(t, drainRowIter(t, dmi), 4)
+
+	dmi = NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	dmi.FilterRows(bStartsWithA)
+	assert.Len(t, drainRowIter(t, dmi), 4)
+
+	// predicates compose, and NextWithRawTuples skips rows as Next does
+	dmi = NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	dmi.FilterRows(bStartsWithA)
+	dmi.FilterRows(func(r sql.Row) bool { return r[0].(int64)%2 == 1 })
+
+	r, _, _, err := dmi.NextWithRawTuples()
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), int64(10), "apple"}, r)
+	assert.Equal(t, []sql.Row{{int64(3), nil, "apricot"}}, drainRowIter(t, dmi))
+
+	// the key tuple is searched too
+	keyIs4 := TagValuePredicate(0, func(val types.Value) bool { return val.Equals(types.Int(4)) })
+	dmi = NewDoltMapIter(ctx, NewFilteredKVGetter(kvGetFuncForTuples(kvs...), keyIs4), nil, conv)
+	assert.Equal(t, []sql.Row{{int64(4), int64(40), "banana"}}, drainRowIter(t, dmi))
+}
+
+func TestWithKeyPredicate(t *testing.T) {
+	ctx := context.Background()
+	evenKeys := func(r sql.Row) bool {
+		return r[0].(int64)%2 == 0
+	}
+
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols, WithKeyPredicate(evenKeys))
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t,
+		[]interface{}{1, 10, "apple"},
+		[]interface{}{2, 20, "avocado"},
+		[]interface{}{3, 30, "banana"},
+		[]interface{}{4, nil, "cherry"},
+	)
+
+	// the value tuples of odd keys can't be decoded, so they must not be read
+	badVal, err := types.NewTuple(types.Format_Default, types.String("not a tag"))
+	require.NoError(t, err)
+	kvs[1], kvs[5] = badVal, badVal
+
+	dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+	assert.Equal(t, []sql.Row{{int64(2), int64(20), "avocado"}, {int64(4), nil, "cherry"}}, drainRowIter(t, dmi))
+
+	_, err = conv.ConvertKVTuplesToSqlRow(kvs[0], kvs[1])
+	assert.Equal(t, ErrFilteredByKey, err)
+
+	// the predicate sees only key columns
+	var seen []sql.Row
+	conv, err = NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols, WithKeyPredicate(func(r sql.Row) bool {
+		seen = append(seen, append(sql.Row{}, r...))
+		return true
+	}))
+	require.NoError(t, err)
+	_, err = conv.ConvertKVTuplesToSqlRow(kvs[2], kvs[3])
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(2), nil, nil}}, seen)
+
+	itr, err := NewColumnValueIter(ctx, types.Format_Default, mergeTestCols[0], kvGetFuncForTuples(kvs...), nil, WithKeyPredicate(evenKeys))
+	require.NoError(t, err)
+	var ids []interface{}
+	for {
+		id, err := itr.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []interface{}{int64(2), int64(4)}, ids)
+}
+
+func TestKeyPredicateMergeAndDiff(t *testing.T) {
+	ctx := context.Background()
+	evenKeys := func(r sql.Row) bool {
+		return r[0].(int64)%2 == 0
+	}
+
+	kc, err := NewKeyComparator(types.Format_Default, mergeTestSchema(), SortKeyCol{Tag: 0})
+	require.NoError(t, err)
+	newConv := func(opts ...KVToSqlRowConverterOption) *KVToSqlRowConverter {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols, opts...)
+		require.NoError(t, err)
+		return conv
+	}
+
+	left := mergeTestKVs(t, []interface{}{1, 10, "a"}, []interface{}{2, 20, "b"}, []interface{}{3, 30, "c"})
+	right := mergeTestKVs(t, []interface{}{2, 21, "b"}, []interface{}{4, 40, "d"}, []interface{}{5, 50, "e"})
+
+	itr := NewMergeIter(ctx, kc, newConv(WithKeyPredicate(evenKeys)), kvGetFuncForTuples(left...), kvGetFuncForTuples(right...))
+	assert.Equal(t, []sql.Row{
+		{int64(2), int64(20), "b"},
+		{int64(2), int64(21), "b"},
+		{int64(4), int64(40), "d"},
+	}, drainRowIter(t, itr))
+
+	itr = NewMergeIter(ctx, kc, newConv(WithKeyPredicate(evenKeys)), kvGetFuncForTuples(left...), kvGetFuncForTuples(right...))
+	itr.SetTieBreak(TieBreakLastWins)
+	assert.Equal(t, []sql.Row{{int64(2), int64(21), "b"}, {int64(4), int64(40), "d"}}, drainRowIter(t, itr))
+
+	fromKVs := mergeTestKVs(t, []interface{}{1, 10, "a"}, []interface{}{3, 30, "c"}, []interface{}{4, 40, "d"})
+	toKVs := mergeTestKVs(t, []interface{}{3, 31, "c"}, []interface{}{4, 41, "d"}, []interface{}{5, 50, "e"})
+
+	diffs := drainDiffIter(t, NewDiffIter(ctx, kc, newConv(WithKeyPredicate(evenKeys)), newConv(WithKeyPredicate(evenKeys)), kvGetFuncForTuples(fromKVs...), kvGetFuncForTuples(toKVs...)))
+	assert.Equal(t, []DiffRow{
+		{Type: DiffModified, From: sql.Row{int64(4), int64(40), "d"}, To: sql.Row{int64(4), int64(41), "d"}, ChangedTags: []uint64{1}},
+	}, diffs)
+
+	// a key filtered out on only one side leaves a modified row out, but not an added one
+	diffs = drainDiffIter(t, NewDiffIter(ctx, kc, newConv(WithKeyPredicate(evenKeys)), newConv(), kvGetFuncForTuples(fromKVs...), kvGetFuncForTuples(toKVs...)))
+	assert.Equal(t, []DiffRow{
+		{Type: DiffModified, From: sql.Row{int64(4), int64(40), "d"}, To: sql.Row{int64(4), int64(41), "d"}, ChangedTags: []uint64{1}},
+		{Type: DiffAdded, To: sql.Row{int64(5), int64(50), "e"}},
+	}, diffs)
+}
+
+func BenchmarkKeyPredicate(b *testing.B) {
+	ctx := context.Background()
+	kvs := columnValueTestKVs(b, 10000)
+	onePercent := func(r sql.Row) bool {
+		return r[0].(int64)%100 == 0
+	}
+
+	b.Run("row predicate", func(b *testing.B) {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+		require.NoError(b, err)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+			dmi.FilterRows(onePercent)
+			benchmarkDrain(b, dmi)
+		}
+	})
+
+	b.Run("key predicate", func(b *testing.B) {
+		conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithKeyPredicate(onePercent))
+		require.NoError(b, err)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dmi := NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv)
+			benchmarkDrain(b, dmi)
+		}
+	})
+}
+
+func benchmarkDrain(b *testing.B, dmi *DoltMapIter) {
+	for {
+		_, err := dmi.Next()
+
+		if err == io.EOF {
+			return
+		}
+
+		require.NoError(b, err)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// RowNumberIter wraps a sql.RowIter and inserts a 1-based int64 row number into each row it returns.  Numbers are
+// assigned per row emitted, so the rows a keyless table returns for a row with a cardinality greater than one are each
+// numbered.  Numbering starts over at 1 for each new RowNumberIter.
+type RowNumberIter struct {
+	itr    sql.RowIter
+	idx    int
+	rowNum int64
+}
+
+var _ sql.RowIter = (*RowNumberIter)(nil)
+
+// NewRowNumberIter returns a RowNumberIter which inserts the row number at index idx of each row, shifting the values
+// at idx and beyond one position to the right.  An idx of 0 prepends the row number.
+func NewRowNumberIter(itr sql.RowIter, idx int) (*RowNumberIter, error) {
+	if idx < 0 {
+		return nil, fmt.Errorf("invalid row number index %d", idx)
+	}
+
+	return &RowNumberIter{itr: itr, idx: idx}, nil
+}
+
+// Next returns the next row with its row number inserted until all rows are returned at which point (nil, io.EOF) is
+// returned.
+func (rni *RowNumberIter) Next() (sql.Row, error) {
+	r, err := rni.itr.Next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if rni.idx > len(r) {
+		return nil, fmt.Errorf("row number index %d is out of range for a row with %d columns", rni.idx, len(r))
+	}
+
+	rni.rowNum++
+
+	numbered := make(sql.Row, len(r)+1)
+	copy(numbered, r[:rni.idx])
+	numbered[rni.idx] = rni.rowNum
+	copy(numbered[rni.idx+1:], r[rni.idx:])
+
+	return numbered, nil
+}
+
+// Close closes the wrapped iterator
+func (rni *RowNumberIter) Close(ctx *sql.Context) error {
+	return rni.itr.Close(ctx)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRowNumberIter(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	kvs := mergeTestKVs(t, []interface{}{1, 10, "a"}, []interface{}{2, 20, "b"}, []interface{}{3, 30, "c"})
+	itr, err := NewRowNumberIter(NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv), 0)
+	require.NoError(t, err)
+
+	expected := []sql.Row{
+		{int64(1), int64(1), int64(10), "a"},
+		{int64(2), int64(2), int64(20), "b"},
+		{int64(3), int64(3), int64(30), "c"},
+	}
+	assert.Equal(t, expected, drainRowIter(t, itr))
+
+	itr, err = NewRowNumberIter(NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv), 3)
+	require.NoError(t, err)
+
+	expected = []sql.Row{
+		{int64(1), int64(10), "a", int64(1)},
+		{int64(2), int64(20), "b", int64(2)},
+		{int64(3), int64(30), "c", int64(3)},
+	}
+	assert.Equal(t, expected, drainRowIter(t, itr))
+}
+
+func TestRowNumberIterCardinality(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	vrw := dEnv.DoltDB.ValueReadWriter()
+
+	sch := dtestutils.CreateSchema(schema.NewColumn("c0", 0, types.IntKind, false))
+	require.True(t, schema.IsKeyless(sch))
+
+	var tups []types.Value
+	for _, card := range []uint64{1, 3} {
+		c0 := types.Int(card * 100)
+		id, err := types.UUIDHashedFromValues(vrw.Format(), types.Uint(0), c0)
+		require.NoError(t, err)
+		k, err := types.NewTuple(vrw.Format(), id)
+		require.NoError(t, err)
+		v, err := types.NewTuple(vrw.Format(), types.Uint(schema.KeylessRowCardinalityTag), types.Uint(card), types.Uint(0), c0)
+		require.NoError(t, err)
+		tups = append(tups, k, v)
+	}
+
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, dtestutils.MustMap(t, vrw, tups...), dtestutils.MustMap(t, vrw), nil)
+	require.NoError(t, err)
+	rdr, err := table.NewBufferedTableReader(ctx, tbl)
+	require.NoError(t, err)
+
+	itr, err := NewRowNumberIter(&doltTableRowIter{ctx: ctx, reader: rdr}, 0)
+	require.NoError(t, err)
+	rows := drainRowIter(t, itr)
+
+	require.Len(t, rows, 4)
+	counts := make(map[int64]int)
+	for i, r := range rows {
+		assert.Equal(t, int64(i+1), r[0])
+		counts[r[1].(int64)]++
+	}
+	assert.Equal(t, map[int64]int{100: 1, 300: 3}, counts)
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// RowRing holds the last n rows added to it, such as for printing the tail of a stream of rows.  Each row is copied
+// into a buffer which is reused once the row falls out of the ring, so memory is bounded by the n largest rows.
+type RowRing struct {
+	rows []sql.Row
+	// next is the position the next row is written to
+	next int
+	// numAdded is the total number of rows added
+	numAdded uint64
+}
+
+// NewRowRing returns a RowRing which holds up to n rows.  An error is returned if n is less than 1.
+func NewRowRing(n int) (*RowRing, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid row ring size %d", n)
+	}
+
+	return &RowRing{rows: make([]sql.Row, n)}, nil
+}
+
+// Add copies r into the ring, replacing the oldest row if the ring is full
+func (rr *RowRing) Add(r sql.Row) {
+	buf := rr.rows[rr.next][:0]
+	rr.rows[rr.next] = append(buf, r...)
+	rr.next = (rr.next + 1) % len(rr.rows)
+	rr.numAdded++
+}
+
+// NumAdded returns the total number of rows added to the ring, including those which are no longer held
+func (rr *RowRing) NumAdded() uint64 {
+	return rr.numAdded
+}
+
+// Rows returns the rows held by the ring in the order they were added.  The rows returned share their buffers with
+// the ring, so they are only valid until the next call to Add.
+func (rr *RowRing) Rows() []sql.Row {
+	if rr.numAdded < uint64(len(rr.rows)) {
+		return append([]sql.Row(nil), rr.rows[:rr.next]...)
+	}
+
+	ordered := make([]sql.Row, 0, len(rr.rows))
+	ordered = append(ordered, rr.rows[rr.next:]...)
+	return append(ordered, rr.rows[:rr.next]...)
+}
+
+// TailRows reads every row from itr and returns the last n of them in order, holding no more than n rows at a time.
+// itr is closed once it has been read.
+func TailRows(ctx *sql.Context, itr sql.RowIter, n int) (rows []sql.Row, err error) {
+	defer func() {
+		closeErr := itr.Close(ctx)
+
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	rr, err := NewRowRing(n)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		r, err := itr.Next()
+
+		if err == io.EOF {
+			return rr.Rows(), nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		rr.Add(r)
+	}
+}
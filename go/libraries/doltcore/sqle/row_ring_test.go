@@ -0,0 +1,72 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailRows(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+
+	var rows []sql.Row
+	for i := int64(0); i < 10; i++ {
+		rows = append(rows, sql.Row{i, "row"})
+	}
+
+	_, err := TailRows(ctx, sql.RowsToRowIter(rows...), 0)
+	assert.Error(t, err)
+
+	tail, err := TailRows(ctx, sql.RowsToRowIter(rows...), 3)
+	require.NoError(t, err)
+	assert.Equal(t, rows[7:], tail)
+
+	tail, err = TailRows(ctx, sql.RowsToRowIter(rows...), 10)
+	require.NoError(t, err)
+	assert.Equal(t, rows, tail)
+
+	// a stream shorter than n returns every row
+	tail, err = TailRows(ctx, sql.RowsToRowIter(rows[:2]...), 5)
+	require.NoError(t, err)
+	assert.Equal(t, rows[:2], tail)
+
+	tail, err = TailRows(ctx, sql.RowsToRowIter(), 5)
+	require.NoError(t, err)
+	assert.Empty(t, tail)
+}
+
+func TestRowRingReusesBuffers(t *testing.T) {
+	rr, err := NewRowRing(2)
+	require.NoError(t, err)
+
+	// rows which are reused by their producer are copied
+	r := sql.Row{int64(0)}
+	for i := int64(0); i < 5; i++ {
+		r[0] = i
+		rr.Add(r)
+	}
+
+	assert.Equal(t, uint64(5), rr.NumAdded())
+	assert.Equal(t, []sql.Row{{int64(3)}, {int64(4)}}, rr.Rows())
+
+	first := rr.Rows()[0]
+	rr.Add(sql.Row{int64(5)})
+	assert.Equal(t, []sql.Row{{int64(4)}, {int64(5)}}, rr.Rows())
+	assert.Equal(t, &first[0], &rr.Rows()[1][0], "the buffer of the oldest row should be reused")
+}
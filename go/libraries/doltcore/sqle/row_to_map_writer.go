@@ -0,0 +1,130 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DefaultRowToMapBatchSize is the number of rows a RowToMapWriter buffers before applying them to its map
+const DefaultRowToMapBatchSize = 256 * 1024
+
+// SqlRowToKVConverter converts sql.Rows with a value for every column of a schema, in schema order, to the key and
+// value tuples stored in a table's row data.  It is the inverse of a KVToSqlRowConverter for all columns.
+type SqlRowToKVConverter struct {
+	vrw types.ValueReadWriter
+	sch schema.Schema
+}
+
+// NewSqlRowToKVConverter returns a SqlRowToKVConverter for the schema given.  Keyless schemas are not supported.
+func NewSqlRowToKVConverter(vrw types.ValueReadWriter, sch schema.Schema) (*SqlRowToKVConverter, error) {
+	if schema.IsKeyless(sch) {
+		return nil, errors.New("cannot convert sql rows to key value pairs for a keyless schema")
+	}
+
+	return &SqlRowToKVConverter{vrw, sch}, nil
+}
+
+// ConvertSqlRowToKV returns the key and value tuples for the sql.Row given
+func (conv *SqlRowToKVConverter) ConvertSqlRowToKV(ctx context.Context, r sql.Row) (types.Tuple, types.Tuple, error) {
+	if len(r) != conv.sch.GetAllCols().Size() {
+		return types.Tuple{}, types.Tuple{}, fmt.Errorf("row has %d values but the schema has %d columns", len(r), conv.sch.GetAllCols().Size())
+	}
+
+	k, v, _, err := sqlutil.DoltKeyValueAndMappingFromSqlRow(ctx, conv.vrw, r, conv.sch)
+	return k, v, err
+}
+
+// RowToMapWriter streams sql.Rows into a types.Map.  Rows are converted as they are written and applied to the map in
+// batches so the whole import is never held in memory.
+type RowToMapWriter struct {
+	conv      *SqlRowToKVConverter
+	vrw       types.ValueReadWriter
+	med       *types.MapEditor
+	batchSize int64
+	numRows   int64
+}
+
+// NewRowToMapWriter returns a RowToMapWriter which adds rows to the map m, applying its edits every batchSize rows.  A
+// batchSize of 0 or less uses DefaultRowToMapBatchSize.
+func NewRowToMapWriter(vrw types.ValueReadWriter, conv *SqlRowToKVConverter, m types.Map, batchSize int) *RowToMapWriter {
+	if batchSize <= 0 {
+		batchSize = DefaultRowToMapBatchSize
+	}
+
+	return &RowToMapWriter{
+		conv:      conv,
+		vrw:       vrw,
+		med:       m.Edit(),
+		batchSize: int64(batchSize),
+	}
+}
+
+// WriteRow converts the row given and adds it to the map.  Conversion errors include the position and values of the
+// row which failed.
+func (wr *RowToMapWriter) WriteRow(ctx context.Context, r sql.Row) error {
+	k, v, err := wr.conv.ConvertSqlRowToKV(ctx, r)
+
+	if err != nil {
+		return fmt.Errorf("failed to convert row %d %v: %w", wr.numRows, r, err)
+	}
+
+	wr.med.Set(k, v)
+	wr.numRows++
+
+	if wr.med.NumEdits() >= wr.batchSize {
+		return wr.flush(ctx)
+	}
+
+	return nil
+}
+
+func (wr *RowToMapWriter) flush(ctx context.Context) error {
+	m, err := wr.med.Map(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	wr.med = m.Edit()
+	return nil
+}
+
+// Map applies any buffered rows and writes the resulting map, returning it along with a ref to it.  The writer can
+// continue to be used afterwards.
+func (wr *RowToMapWriter) Map(ctx context.Context) (types.Map, types.Ref, error) {
+	m, err := wr.med.Map(ctx)
+
+	if err != nil {
+		return types.EmptyMap, types.Ref{}, err
+	}
+
+	wr.med = m.Edit()
+	ref, err := wr.vrw.WriteValue(ctx, m)
+
+	if err != nil {
+		return types.EmptyMap, types.Ref{}, err
+	}
+
+	return m, ref, nil
+}
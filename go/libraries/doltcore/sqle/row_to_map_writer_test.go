@@ -0,0 +1,80 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRowToMapWriter(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	sch := mergeTestSchema()
+
+	rowConv, err := NewSqlRowToKVConverter(vrw, sch)
+	require.NoError(t, err)
+	m, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+
+	rows := []sql.Row{
+		{int64(3), int64(30), "c"},
+		{int64(1), int64(10), "a"},
+		{int64(5), nil, "e"},
+		{int64(2), int64(20), nil},
+		{int64(4), int64(40), "d"},
+	}
+
+	wr := NewRowToMapWriter(vrw, rowConv, m, 2)
+	for _, r := range rows {
+		require.NoError(t, wr.WriteRow(ctx, r))
+	}
+
+	m, ref, err := wr.Map(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(rows)), m.Len())
+
+	val, err := vrw.ReadValue(ctx, ref.TargetHash())
+	require.NoError(t, err)
+	assert.True(t, m.Equals(val))
+
+	mapItr, err := m.Iterator(ctx)
+	require.NoError(t, err)
+	conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), mergeTestCols)
+	require.NoError(t, err)
+	dmi := NewDoltMapIter(ctx, GetGetFuncForMapIter(vrw.Format(), mapItr), nil, conv)
+
+	expected := []sql.Row{
+		{int64(1), int64(10), "a"},
+		{int64(2), int64(20), nil},
+		{int64(3), int64(30), "c"},
+		{int64(4), int64(40), "d"},
+		{int64(5), nil, "e"},
+	}
+	assert.Equal(t, expected, drainRowIter(t, dmi))
+
+	err = wr.WriteRow(ctx, sql.Row{nil, int64(60), "f"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 5")
+
+	err = wr.WriteRow(ctx, sql.Row{int64(6)})
+	assert.Error(t, err)
+}
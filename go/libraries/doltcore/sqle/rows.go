@@ -91,7 +91,12 @@ func newKeyedRowIter(ctx context.Context, tbl *DoltTable, projectedCols []string
 		}
 	}
 
-	conv := NewKVToSqlRowConverter(tbl.nbf, tagToSqlColIdx, cols, len(cols))
+	conv, err := NewKVToSqlRowConverter(tbl.nbf, tagToSqlColIdx, cols, len(cols))
+
+	if err != nil {
+		return nil, err
+	}
+
 	return NewDoltMapIter(ctx, mapIter.NextTuple, nil, conv), nil
 }
 
@@ -0,0 +1,154 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlfmt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+// DefaultInsertBatchSize is the number of rows in each INSERT statement written by an InsertBatchWriter unless told
+// otherwise
+const DefaultInsertBatchSize = 1000
+
+// InsertBatchWriter writes sql.Rows, such as those produced by a KVToSqlRowConverter, as multi-row INSERT statements
+// into a table for logical exports.  Each statement is written on its own line and holds up to batchSize rows.  Values
+// are written as SQL literals for the type of their column, with NULLs as NULL and the values of binary columns as hex
+// literals, so the output can be run with dolt sql.
+type InsertBatchWriter struct {
+	wr         io.Writer
+	cols       []schema.Column
+	batchSize  int
+	prefix     string
+	stmt       strings.Builder
+	rowsInStmt int
+}
+
+// NewInsertBatchWriter returns an InsertBatchWriter which writes rows with the columns of sch, in order, as INSERT
+// statements into the table with the name given.  A batchSize of 0 or less uses DefaultInsertBatchSize.
+func NewInsertBatchWriter(wr io.Writer, tableName string, sch schema.Schema, batchSize int) *InsertBatchWriter {
+	if batchSize <= 0 {
+		batchSize = DefaultInsertBatchSize
+	}
+
+	cols := sch.GetAllCols().GetColumns()
+	colNames := make([]string, len(cols))
+	for i, col := range cols {
+		colNames[i] = QuoteIdentifier(col.Name)
+	}
+
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", QuoteIdentifier(tableName), strings.Join(colNames, ","))
+	return &InsertBatchWriter{wr: wr, cols: cols, batchSize: batchSize, prefix: prefix}
+}
+
+// WriteSqlRow adds a row to the current INSERT statement, writing the statement once it holds batchSize rows
+func (w *InsertBatchWriter) WriteSqlRow(r sql.Row) error {
+	if len(r) != len(w.cols) {
+		return fmt.Errorf("row has %d values but the schema has %d columns", len(r), len(w.cols))
+	}
+
+	lits := make([]string, len(w.cols))
+	for i, col := range w.cols {
+		var err error
+		lits[i], err = SqlValueAsLiteral(col, r[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.rowsInStmt == 0 {
+		w.stmt.WriteString(w.prefix)
+	} else {
+		w.stmt.WriteRune(',')
+	}
+
+	w.stmt.WriteRune('(')
+	w.stmt.WriteString(strings.Join(lits, ","))
+	w.stmt.WriteRune(')')
+	w.rowsInStmt++
+
+	if w.rowsInStmt >= w.batchSize {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// Flush ends the current INSERT statement, if it holds any rows, and writes it
+func (w *InsertBatchWriter) Flush() error {
+	if w.rowsInStmt == 0 {
+		return nil
+	}
+
+	w.stmt.WriteRune(';')
+	err := iohelp.WriteLine(w.wr, w.stmt.String())
+	w.stmt.Reset()
+	w.rowsInStmt = 0
+
+	return err
+}
+
+// Close writes the last INSERT statement.  The underlying writer is not closed.
+func (w *InsertBatchWriter) Close() error {
+	return w.Flush()
+}
+
+// SqlValueAsLiteral returns the SQL literal for a value of the column given as it is returned by a sql.RowIter.  NULLs
+// are written as NULL, the values of binary columns as hex literals, and other values quoted and escaped as needed for
+// the SQL type of the column.  An error is returned for values which have no literal, such as NaN.
+func SqlValueAsLiteral(col schema.Column, val interface{}) (string, error) {
+	if val == nil {
+		return "NULL", nil
+	}
+
+	switch col.TypeInfo.GetTypeIdentifier() {
+	case typeinfo.VarBinaryTypeIdentifier, typeinfo.InlineBlobTypeIdentifier:
+		switch b := val.(type) {
+		case string:
+			return "X'" + strings.ToUpper(hex.EncodeToString([]byte(b))) + "'", nil
+		case []byte:
+			return "X'" + strings.ToUpper(hex.EncodeToString(b)) + "'", nil
+		default:
+			return "", fmt.Errorf("column '%s' has a %T value which cannot be written as a binary literal", col.Name, val)
+		}
+	case typeinfo.FloatTypeIdentifier:
+		if f, ok := val.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return "", fmt.Errorf("column '%s' has the value %v which has no SQL literal", col.Name, f)
+		} else if f, ok := val.(float32); ok && (math.IsNaN(float64(f)) || math.IsInf(float64(f), 0)) {
+			return "", fmt.Errorf("column '%s' has the value %v which has no SQL literal", col.Name, f)
+		}
+	}
+
+	sqlVal, err := col.TypeInfo.ToSqlType().SQL(val)
+
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	sqlVal.EncodeSQL(buf)
+	return buf.String(), nil
+}
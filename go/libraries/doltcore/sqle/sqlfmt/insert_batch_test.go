@@ -0,0 +1,110 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlfmt
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+func insertBatchTestSchema(t *testing.T) schema.Schema {
+	sqlTypes := []struct {
+		name string
+		typ  sql.Type
+	}{
+		{"id", sql.Int64},
+		{"name", sql.MustCreateStringWithDefaults(sqltypes.VarChar, 64)},
+		{"score", sql.Float64},
+		{"price", sql.MustCreateDecimalType(10, 2)},
+		{"created", sql.Datetime},
+		{"active", sql.Boolean},
+		{"data", sql.MustCreateBinary(sqltypes.VarBinary, 16)},
+		{"blob", sql.LongBlob},
+	}
+
+	cols := make([]schema.Column, len(sqlTypes))
+	for i, st := range sqlTypes {
+		ti, err := typeinfo.FromSqlType(st.typ)
+		require.NoError(t, err)
+		cols[i], err = schema.NewColumnWithTypeInfo(st.name, uint64(i), ti, i == 0, "", false, "")
+		require.NoError(t, err)
+	}
+
+	return schema.MustSchemaFromCols(schema.NewColCollection(cols...))
+}
+
+func TestInsertBatchWriter(t *testing.T) {
+	sch := insertBatchTestSchema(t)
+	created := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	rows := []sql.Row{
+		{int64(1), "bill", 2.5, "10.50", created, int8(1), "\x00\xff", "blob"},
+		{int64(2), `it's a "quote" \ here`, nil, nil, nil, nil, nil, nil},
+		{int64(3), "", -1.25, "0.00", created, int8(0), "", ""},
+	}
+
+	var buf bytes.Buffer
+	wr := NewInsertBatchWriter(&buf, "my`table", sch, 2)
+	for _, r := range rows {
+		require.NoError(t, wr.WriteSqlRow(r))
+	}
+	require.NoError(t, wr.Close())
+
+	expected := "" +
+		"INSERT INTO `my``table` (`id`,`name`,`score`,`price`,`created`,`active`,`data`,`blob`) VALUES " +
+		"(1,'bill',2.5,10.50,'2021-03-04 05:06:07',1,X'00FF',X'626C6F62')," +
+		"(2,'it\\'s a \\\"quote\\\" \\\\ here',NULL,NULL,NULL,NULL,NULL,NULL);\n" +
+		"INSERT INTO `my``table` (`id`,`name`,`score`,`price`,`created`,`active`,`data`,`blob`) VALUES " +
+		"(3,'',-1.25,0.00,'2021-03-04 05:06:07',0,X'',X'');\n"
+	assert.Equal(t, expected, buf.String())
+
+	stmts := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, stmts, 2)
+	for _, stmt := range stmts {
+		parsed, err := sqlparser.Parse(stmt)
+		require.NoError(t, err)
+		insert, ok := parsed.(*sqlparser.Insert)
+		require.True(t, ok)
+		assert.Equal(t, "my`table", insert.Table.Name.String())
+	}
+}
+
+func TestInsertBatchWriterErrors(t *testing.T) {
+	sch := insertBatchTestSchema(t)
+
+	var buf bytes.Buffer
+	wr := NewInsertBatchWriter(&buf, "t", sch, 0)
+	assert.Error(t, wr.WriteSqlRow(sql.Row{int64(1)}))
+	assert.Error(t, wr.WriteSqlRow(sql.Row{int64(1), "a", math.NaN(), nil, nil, nil, nil, nil}))
+	assert.Error(t, wr.WriteSqlRow(sql.Row{int64(1), "a", math.Inf(1), nil, nil, nil, nil, nil}))
+	require.NoError(t, wr.Close())
+	assert.Empty(t, buf.String())
+
+	// rows which fail leave nothing behind in the statement
+	require.NoError(t, wr.WriteSqlRow(sql.Row{int64(1), "a", nil, nil, nil, nil, nil, nil}))
+	require.NoError(t, wr.Close())
+	assert.Equal(t, "INSERT INTO `t` (`id`,`name`,`score`,`price`,`created`,`active`,`data`,`blob`) VALUES (1,'a',NULL,NULL,NULL,NULL,NULL,NULL);\n", buf.String())
+}
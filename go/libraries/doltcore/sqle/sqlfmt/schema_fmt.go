@@ -125,6 +125,42 @@ func FmtForeignKey(fk doltdb.ForeignKey, sch, parentSch schema.Schema) string {
 	return sb.String()
 }
 
+// CreateTableStmt returns a CREATE TABLE statement for a table with the given name and schema.  Each column is
+// rendered by FmtCol with its SQL type, nullability and default, followed by a primary key clause naming the primary
+// key columns in order.  Keyless schemas have no primary key clause.  Column tags are not included.
+func CreateTableStmt(tableName string, sch schema.Schema) string {
+	var b strings.Builder
+	b.WriteString("CREATE TABLE ")
+	b.WriteString(QuoteIdentifier(tableName))
+	b.WriteString(" (\n")
+
+	first := true
+	_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if !first {
+			b.WriteString(",\n")
+		}
+
+		b.WriteString(FmtCol(2, 0, 0, col))
+		first = false
+		return false, nil
+	})
+
+	if sch.GetPKCols().Size() > 0 {
+		pkNames := make([]string, 0, sch.GetPKCols().Size())
+		_ = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+			pkNames = append(pkNames, QuoteIdentifier(col.Name))
+			return false, nil
+		})
+
+		b.WriteString(",\n  PRIMARY KEY (")
+		b.WriteString(strings.Join(pkNames, ","))
+		b.WriteString(")")
+	}
+
+	b.WriteString("\n);")
+	return b.String()
+}
+
 func DropTableStmt(tableName string) string {
 	var b strings.Builder
 	b.WriteString("DROP TABLE ")
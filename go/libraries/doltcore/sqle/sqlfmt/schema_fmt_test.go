@@ -15,12 +15,17 @@
 package sqlfmt
 
 import (
-	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
-	"github.com/dolthub/dolt/go/store/types"
-
 	"testing"
 
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 func TestFmtCol(t *testing.T) {
@@ -68,3 +73,39 @@ func TestFmtCol(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTableStmt(t *testing.T) {
+	varcharType, err := typeinfo.FromSqlType(sql.MustCreateStringWithDefaults(sqltypes.VarChar, 64))
+	require.NoError(t, err)
+
+	regionCol, err := schema.NewColumnWithTypeInfo("region", 3, varcharType, true, "", false, "", schema.NotNullConstraint{})
+	require.NoError(t, err)
+	idCol, err := schema.NewColumnWithTypeInfo("id", 7, typeinfo.Int32Type, true, "", true, "", schema.NotNullConstraint{})
+	require.NoError(t, err)
+	nameCol, err := schema.NewColumnWithTypeInfo("full name", 12, varcharType, false, `'unknown'`, false, "", schema.NotNullConstraint{})
+	require.NoError(t, err)
+	scoreCol, err := schema.NewColumnWithTypeInfo("score", 20, typeinfo.Float64Type, false, "", false, "player's score")
+	require.NoError(t, err)
+	createdCol, err := schema.NewColumnWithTypeInfo("created`at", 21, typeinfo.DatetimeType, false, "", false, "")
+	require.NoError(t, err)
+
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(regionCol, idCol, nameCol, scoreCol, createdCol))
+
+	const expected = "CREATE TABLE `high scores` (\n" +
+		"  `region` VARCHAR(64) NOT NULL,\n" +
+		"  `id` INT NOT NULL AUTO_INCREMENT,\n" +
+		"  `full name` VARCHAR(64) NOT NULL DEFAULT 'unknown',\n" +
+		"  `score` DOUBLE COMMENT 'player\\'s score',\n" +
+		"  `created``at` DATETIME,\n" +
+		"  PRIMARY KEY (`region`,`id`)\n" +
+		");"
+	stmt := CreateTableStmt("high scores", sch)
+	assert.Equal(t, expected, stmt)
+
+	_, err = sqlparser.Parse(stmt)
+	assert.NoError(t, err)
+
+	keyless := schema.NewColCollection(scoreCol, createdCol)
+	assert.Equal(t, "CREATE TABLE `t` (\n  `score` DOUBLE COMMENT 'player\\'s score',\n  `created``at` DATETIME\n);",
+		CreateTableStmt("t", schema.UnkeyedSchemaFromCols(keyless)))
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+// typedJSONNullType is the type written for NULL values by a TypedJSONLRowWriter
+const typedJSONNullType = "null"
+
+// TypedJSONLRowWriter writes the rows produced by a KVToSqlRowConverter to an io.Writer as JSON lines, one JSON object
+// from column name to value per line, in which every value is wrapped with its type as {"t": <type>, "v": <value>}.
+// The types of the values survive without the schema, which makes it an export for consumers, such as a generic diff
+// viewer, which receive the rows alone.  It is more verbose than a plain JSON export and must be chosen explicitly.
+//
+// The type is the identifier of the column's typeinfo, such as int, varstring or datetime, and values are written as:
+//   - NULL as {"t": "null"} without a value
+//   - ints, uints, years and bits as numbers, and bools as booleans
+//   - floats as numbers, other than NaN and infinities which are written as the strings NaN, +Inf and -Inf
+//   - varbinary and inlineblob columns, including LazyBlobs, as base64 strings
+//   - datetimes as RFC 3339 strings
+//   - all other columns, including decimals, as strings in their textual form
+type TypedJSONLRowWriter struct {
+	wr    io.Writer
+	names [][]byte
+	types []typeinfo.Identifier
+	buf   []byte
+}
+
+// NewTypedJSONLRowWriter returns a TypedJSONLRowWriter which writes the rows produced by conv to wr.  Positions in the
+// rows which no column maps to are left out of the written objects.
+func NewTypedJSONLRowWriter(wr io.Writer, conv *KVToSqlRowConverter) (*TypedJSONLRowWriter, error) {
+	tw := &TypedJSONLRowWriter{
+		wr:    wr,
+		names: make([][]byte, conv.rowSize),
+		types: make([]typeinfo.Identifier, conv.rowSize),
+	}
+
+	for _, idx := range conv.tagToSqlColIdx {
+		col := conv.cols[idx]
+		name, err := json.Marshal(col.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tw.names[idx] = name
+		tw.types[idx] = col.TypeInfo.GetTypeIdentifier()
+	}
+
+	return tw, nil
+}
+
+// WriteRow writes the row given as a line holding a JSON object.  The row must have been produced by the converter the
+// writer was created with.
+func (tw *TypedJSONLRowWriter) WriteRow(ctx context.Context, r sql.Row) error {
+	tw.buf = append(tw.buf[:0], '{')
+
+	first := true
+	for idx, name := range tw.names {
+		if name == nil {
+			continue
+		}
+
+		if !first {
+			tw.buf = append(tw.buf, ',')
+		}
+
+		first = false
+		tw.buf = append(tw.buf, name...)
+		tw.buf = append(tw.buf, ':')
+
+		val := SqlRowValue(r, idx)
+
+		var err error
+		tw.buf, err = appendTypedJSONValue(ctx, tw.buf, tw.types[idx], val)
+
+		if err != nil {
+			return fmt.Errorf("failed to write column %s: %w", name, err)
+		}
+	}
+
+	tw.buf = append(tw.buf, '}', '\n')
+	_, err := tw.wr.Write(tw.buf)
+	return err
+}
+
+// WriteRows writes every row of itr, returning the number of rows written.  The iterator is not closed.
+func (tw *TypedJSONLRowWriter) WriteRows(ctx *sql.Context, itr sql.RowIter) (int64, error) {
+	var numRows int64
+	for {
+		r, err := itr.Next()
+
+		if err == io.EOF {
+			return numRows, nil
+		} else if err != nil {
+			return numRows, err
+		}
+
+		err = tw.WriteRow(ctx, r)
+
+		if err != nil {
+			return numRows, err
+		}
+
+		numRows++
+	}
+}
+
+// appendTypedJSONValue appends a converted value of a column of the type given, wrapped with its type
+func appendTypedJSONValue(ctx context.Context, buf []byte, typ typeinfo.Identifier, val interface{}) ([]byte, error) {
+	if val == nil {
+		return append(buf, `{"t":"`+typedJSONNullType+`"}`...), nil
+	}
+
+	buf = append(buf, `{"t":"`...)
+	buf = append(buf, typ...)
+	buf = append(buf, `","v":`...)
+
+	var err error
+	switch typ {
+	case typeinfo.VarBinaryTypeIdentifier, typeinfo.InlineBlobTypeIdentifier:
+		buf, err = appendBase64JSONValue(ctx, buf, val)
+	case typeinfo.BoolTypeIdentifier:
+		var b bool
+		b, err = typedJSONBool(val)
+		buf = strconv.AppendBool(buf, b)
+	default:
+		buf, err = appendTypedJSONScalar(buf, val)
+	}
+
+	if err != nil {
+		return buf, err
+	}
+
+	return append(buf, '}'), nil
+}
+
+// appendBase64JSONValue appends the value of a binary column as a base64 JSON string
+func appendBase64JSONValue(ctx context.Context, buf []byte, val interface{}) ([]byte, error) {
+	var data []byte
+	switch v := val.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case *LazyBlob:
+		rd, err := v.NewReader(ctx)
+
+		if err != nil {
+			return buf, err
+		}
+
+		data, err = ioutil.ReadAll(rd)
+
+		if err != nil {
+			return buf, err
+		}
+	default:
+		return buf, fmt.Errorf("cannot encode value of type %T as binary", val)
+	}
+
+	buf = append(buf, '"')
+	buf = append(buf, base64.StdEncoding.EncodeToString(data)...)
+	return append(buf, '"'), nil
+}
+
+// appendTypedJSONScalar appends a value of a column which isn't binary or boolean
+func appendTypedJSONScalar(buf []byte, val interface{}) ([]byte, error) {
+	switch v := val.(type) {
+	case int8:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(buf, v, 10), nil
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(buf, v, 10), nil
+	case uint:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case float32:
+		return appendTypedJSONFloat(buf, float64(v), 32), nil
+	case float64:
+		return appendTypedJSONFloat(buf, v, 64), nil
+	case time.Time:
+		return appendJSONString(buf, v.Format(time.RFC3339Nano))
+	case string:
+		return appendJSONString(buf, v)
+	case fmt.Stringer:
+		return appendJSONString(buf, v.String())
+	default:
+		return buf, fmt.Errorf("cannot encode value of type %T as JSON", val)
+	}
+}
+
+// typedJSONBool returns whether the value of a bool column, which is stored as an integer, is true
+func typedJSONBool(val interface{}) (bool, error) {
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+	case int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint:
+		return fmt.Sprint(v) != "0", nil
+	default:
+		return false, fmt.Errorf("cannot encode value of type %T as a boolean", val)
+	}
+}
+
+// appendTypedJSONFloat appends a float as a number, or NaN and infinities, which JSON numbers can't hold, as strings
+func appendTypedJSONFloat(buf []byte, f float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(buf, `"NaN"`...)
+	case math.IsInf(f, 1):
+		return append(buf, `"+Inf"`...)
+	case math.IsInf(f, -1):
+		return append(buf, `"-Inf"`...)
+	default:
+		return strconv.AppendFloat(buf, f, 'g', -1, bitSize)
+	}
+}
+
+func appendJSONString(buf []byte, str string) ([]byte, error) {
+	data, err := json.Marshal(str)
+
+	if err != nil {
+		return buf, err
+	}
+
+	return append(buf, data...), nil
+}
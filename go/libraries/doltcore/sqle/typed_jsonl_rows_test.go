@@ -0,0 +1,107 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestTypedJSONLRowWriter(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	newColWithTypeInfo := func(name string, tag uint64, ti typeinfo.TypeInfo) schema.Column {
+		col, err := schema.NewColumnWithTypeInfo(name, tag, ti, tag == 0, "", false, "")
+		require.NoError(t, err)
+		return col
+	}
+	newCol := func(name string, tag uint64, sqlType sql.Type) schema.Column {
+		ti, err := typeinfo.FromSqlType(sqlType)
+		require.NoError(t, err)
+		return newColWithTypeInfo(name, tag, ti)
+	}
+
+	cols := []schema.Column{
+		newCol("id", 0, sql.Int64),
+		newCol("count", 1, sql.Uint32),
+		newCol("score", 2, sql.Float64),
+		newCol("name", 3, sql.MustCreateStringWithDefaults(sqltypes.VarChar, 20)),
+		newCol("created", 4, sql.Datetime),
+		newCol("price", 5, sql.MustCreateDecimalType(10, 2)),
+		newColWithTypeInfo("active", 6, typeinfo.BoolType),
+		newCol("data", 7, sql.MustCreateBinary(sqltypes.VarBinary, 10)),
+	}
+
+	created := time.Date(2021, 3, 14, 15, 9, 26, 535000000, time.UTC)
+	var kvs []types.Tuple
+	for _, vals := range [][]interface{}{
+		{int64(1), uint32(300), 2.5, `say "hi"`, created, "12.50", true, string([]byte{0, 1, 0xff})},
+		{int64(-2), nil, math.NaN(), nil, nil, nil, false, nil},
+	} {
+		var taggedVals []types.Value
+		for i, val := range vals {
+			if val == nil {
+				continue
+			}
+
+			nomsVal, err := cols[i].TypeInfo.ConvertValueToNomsValue(ctx, vrw, val)
+			require.NoError(t, err)
+			taggedVals = append(taggedVals, types.Uint(cols[i].Tag), nomsVal)
+		}
+
+		k, err := types.NewTuple(vrw.Format(), taggedVals[:2]...)
+		require.NoError(t, err)
+		v, err := types.NewTuple(vrw.Format(), taggedVals[2:]...)
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	conv, err := NewKVToSqlRowConverterForCols(vrw.Format(), cols)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	tw, err := NewTypedJSONLRowWriter(&buf, conv)
+	require.NoError(t, err)
+	n, err := tw.WriteRows(sql.NewEmptyContext(), NewDoltMapIter(ctx, kvGetFuncForTuples(kvs...), nil, conv))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"id":{"t":"int","v":1},"count":{"t":"uint","v":300},"score":{"t":"float","v":2.5},`+
+		`"name":{"t":"varstring","v":"say \"hi\""},"created":{"t":"datetime","v":"2021-03-14T15:09:26.535Z"},`+
+		`"price":{"t":"decimal","v":"12.50"},"active":{"t":"bool","v":true},"data":{"t":"inlineblob","v":"AAH/"}}`, lines[0])
+	assert.Equal(t, `{"id":{"t":"int","v":-2},"count":{"t":"null"},"score":{"t":"float","v":"NaN"},"name":{"t":"null"},`+
+		`"created":{"t":"null"},"price":{"t":"null"},"active":{"t":"bool","v":false},"data":{"t":"null"}}`, lines[1])
+
+	for _, line := range lines {
+		assert.True(t, json.Valid([]byte(line)))
+	}
+}
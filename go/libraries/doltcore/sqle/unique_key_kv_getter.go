@@ -0,0 +1,220 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DuplicateKeyError is returned by a UniqueKeyKVGetter which reads a key that it has already read
+type DuplicateKeyError struct {
+	// Key is the human readable encoding of the duplicated key tuple
+	Key string
+}
+
+// Error returns a message naming the duplicated key
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate primary key %s", e.Key)
+}
+
+// DuplicateKeyFunc is called by a UniqueKeyKVGetter for each duplicate key it reads.  If it returns nil the duplicate
+// pair is skipped and reading continues, otherwise the error is returned.
+type DuplicateKeyFunc func(ctx context.Context, k, v types.Tuple) error
+
+// PossibleDuplicateKeyFunc is called by an approximate UniqueKeyKVGetter for each key which may have been read before.
+// As the key may not actually be a duplicate the pair is still returned, unless the function returns an error, which is
+// returned in its place.  A caller which must reject duplicates can confirm the key exactly, such as by looking it up
+// in the table being written, and return an error only when it is.
+type PossibleDuplicateKeyFunc func(ctx context.Context, k, v types.Tuple) error
+
+// keySet records the hashes of keys that have been read
+type keySet interface {
+	// insert adds h to the set, returning true if it may already have been present
+	insert(h hash.Hash) bool
+}
+
+type exactKeySet hash.HashSet
+
+func (s exactKeySet) insert(h hash.Hash) bool {
+	if hash.HashSet(s).Has(h) {
+		return true
+	}
+
+	hash.HashSet(s).Insert(h)
+	return false
+}
+
+// bloomKeySet is a bloom filter over key hashes.  Its memory use is fixed when it is created.
+type bloomKeySet struct {
+	bits    []uint64
+	numBits uint64
+	numHash uint64
+}
+
+func newBloomKeySet(expectedKeys uint64, fpRate float64) *bloomKeySet {
+	if expectedKeys == 0 {
+		expectedKeys = 1
+	}
+
+	numBits := uint64(math.Ceil(-float64(expectedKeys) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	numBits = maxU64(numBits, 64)
+	numHash := maxU64(uint64(math.Round(float64(numBits)/float64(expectedKeys)*math.Ln2)), 1)
+
+	return &bloomKeySet{
+		bits:    make([]uint64, (numBits+63)/64),
+		numBits: numBits,
+		numHash: numHash,
+	}
+}
+
+func (s *bloomKeySet) insert(h hash.Hash) bool {
+	// key hashes are already uniformly distributed, so the bit positions are derived from two halves of the hash using
+	// double hashing rather than hashing again
+	h1 := binary.BigEndian.Uint64(h[:8])
+	h2 := binary.BigEndian.Uint64(h[8:16]) | 1
+
+	present := true
+	for i := uint64(0); i < s.numHash; i++ {
+		bit := (h1 + i*h2) % s.numBits
+		word, mask := bit/64, uint64(1)<<(bit%64)
+
+		if s.bits[word]&mask == 0 {
+			present = false
+			s.bits[word] |= mask
+		}
+	}
+
+	return present
+}
+
+// UniqueKeyKVGetter wraps a KVGetFunc and verifies that no key is read more than once, which is useful when verifying
+// a stream of rows being imported.  Keys are tracked by the hash of their key tuple.  Exact checking holds the hash of
+// every key read in memory and skips or rejects duplicates.  Approximate checking uses a fixed amount of memory, but
+// may report a key that was not actually duplicated at the configured false positive rate, so it only reports possible
+// duplicates and never drops or rejects a pair itself.
+type UniqueKeyKVGetter struct {
+	nbf           *types.NomsBinFormat
+	get           KVGetFunc
+	closeGet      func() error
+	keys          keySet
+	onDup         DuplicateKeyFunc
+	approximate   bool
+	onPossibleDup PossibleDuplicateKeyFunc
+	possibleDups  uint64
+}
+
+// NewExactUniqueKeyKVGetter returns a UniqueKeyKVGetter which remembers the hash of every key read from get.  onDup,
+// which may be nil, is called for each duplicate.  When it is nil a *DuplicateKeyError is returned for the first
+// duplicate.  closeGet, which may be nil, is called when the UniqueKeyKVGetter is closed.
+func NewExactUniqueKeyKVGetter(nbf *types.NomsBinFormat, get KVGetFunc, closeGet func() error, onDup DuplicateKeyFunc) *UniqueKeyKVGetter {
+	return &UniqueKeyKVGetter{
+		nbf:      nbf,
+		get:      get,
+		closeGet: closeGet,
+		keys:     exactKeySet(hash.NewHashSet()),
+		onDup:    onDup,
+	}
+}
+
+// NewApproximateUniqueKeyKVGetter returns a UniqueKeyKVGetter which tracks the keys read from get using a bloom filter
+// sized so that, after expectedKeys keys are read, a key that was not duplicated is reported with a probability of
+// about fpRate.  Every pair read from get is returned.  onPossibleDup, which may be nil, is called for each key which
+// may be a duplicate, and PossibleDuplicates counts them.  closeGet is as for NewExactUniqueKeyKVGetter.
+func NewApproximateUniqueKeyKVGetter(nbf *types.NomsBinFormat, get KVGetFunc, closeGet func() error, expectedKeys uint64, fpRate float64, onPossibleDup PossibleDuplicateKeyFunc) (*UniqueKeyKVGetter, error) {
+	if fpRate <= 0 || fpRate >= 1 {
+		return nil, fmt.Errorf("invalid false positive rate %v", fpRate)
+	}
+
+	return &UniqueKeyKVGetter{
+		nbf:           nbf,
+		get:           get,
+		closeGet:      closeGet,
+		keys:          newBloomKeySet(expectedKeys, fpRate),
+		approximate:   true,
+		onPossibleDup: onPossibleDup,
+	}, nil
+}
+
+// PossibleDuplicates returns the number of keys an approximate UniqueKeyKVGetter has read which may be duplicates
+func (u *UniqueKeyKVGetter) PossibleDuplicates() uint64 {
+	return u.possibleDups
+}
+
+// Get returns the next key value pair whose key has not been read before, or with approximate checking the next key
+// value pair.  It has the signature of a KVGetFunc.
+func (u *UniqueKeyKVGetter) Get(ctx context.Context) (types.Tuple, types.Tuple, error) {
+	for {
+		k, v, err := u.get(ctx)
+
+		if err != nil {
+			return types.Tuple{}, types.Tuple{}, err
+		}
+
+		h, err := k.Hash(u.nbf)
+
+		if err != nil {
+			return types.Tuple{}, types.Tuple{}, err
+		}
+
+		if !u.keys.insert(h) {
+			return k, v, nil
+		}
+
+		if u.approximate {
+			u.possibleDups++
+
+			if u.onPossibleDup != nil {
+				err = u.onPossibleDup(ctx, k, v)
+
+				if err != nil {
+					return types.Tuple{}, types.Tuple{}, err
+				}
+			}
+
+			return k, v, nil
+		}
+
+		if u.onDup == nil {
+			keyStr, err := types.EncodedValue(ctx, k)
+
+			if err != nil {
+				return types.Tuple{}, types.Tuple{}, err
+			}
+
+			return types.Tuple{}, types.Tuple{}, &DuplicateKeyError{Key: keyStr}
+		}
+
+		err = u.onDup(ctx, k, v)
+
+		if err != nil {
+			return types.Tuple{}, types.Tuple{}, err
+		}
+	}
+}
+
+// Close closes the wrapped KVGetFunc
+func (u *UniqueKeyKVGetter) Close() error {
+	if u.closeGet != nil {
+		return u.closeGet()
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestUniqueKeyKVGetter(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	unique := mergeTestKVs(t,
+		[]interface{}{1, 10, "a"},
+		[]interface{}{2, 20, "b"},
+		[]interface{}{3, 30, "c"},
+	)
+	withDup := mergeTestKVs(t,
+		[]interface{}{1, 10, "a"},
+		[]interface{}{2, 20, "b"},
+		[]interface{}{1, 30, "c"},
+		[]interface{}{4, 40, "d"},
+	)
+
+	t.Run("no duplicates", func(t *testing.T) {
+		u := NewExactUniqueKeyKVGetter(types.Format_Default, kvGetFuncForTuples(unique...), nil, nil)
+		rows := drainRowIter(t, NewDoltMapIter(ctx, u.Get, u.Close, conv))
+		assert.Equal(t, []sql.Row{{int64(1), int64(10), "a"}, {int64(2), int64(20), "b"}, {int64(3), int64(30), "c"}}, rows)
+	})
+
+	t.Run("error on first duplicate", func(t *testing.T) {
+		u := NewExactUniqueKeyKVGetter(types.Format_Default, kvGetFuncForTuples(withDup...), nil, nil)
+
+		for i := 0; i < 2; i++ {
+			_, _, err = u.Get(ctx)
+			require.NoError(t, err)
+		}
+
+		_, _, err = u.Get(ctx)
+		var dupErr *DuplicateKeyError
+		require.True(t, errors.As(err, &dupErr))
+		assert.Contains(t, dupErr.Error(), "1")
+	})
+
+	t.Run("duplicates routed to callback", func(t *testing.T) {
+		var dups []types.Tuple
+		onDup := func(ctx context.Context, k, v types.Tuple) error {
+			dups = append(dups, k)
+			return nil
+		}
+
+		u := NewExactUniqueKeyKVGetter(types.Format_Default, kvGetFuncForTuples(withDup...), nil, onDup)
+		rows := drainRowIter(t, NewDoltMapIter(ctx, u.Get, u.Close, conv))
+		assert.Equal(t, []sql.Row{{int64(1), int64(10), "a"}, {int64(2), int64(20), "b"}, {int64(4), int64(40), "d"}}, rows)
+		require.Len(t, dups, 1)
+		assert.True(t, dups[0].Equals(withDup[4]))
+	})
+
+	t.Run("callback error stops iteration", func(t *testing.T) {
+		stopErr := errors.New("stop")
+		u := NewExactUniqueKeyKVGetter(types.Format_Default, kvGetFuncForTuples(withDup...), nil, func(ctx context.Context, k, v types.Tuple) error {
+			return stopErr
+		})
+
+		dmi := NewDoltMapIter(ctx, u.Get, u.Close, conv)
+		for i := 0; i < 2; i++ {
+			_, err = dmi.Next()
+			require.NoError(t, err)
+		}
+
+		_, err = dmi.Next()
+		assert.Equal(t, stopErr, err)
+	})
+
+	t.Run("approximate reports possible duplicates without dropping them", func(t *testing.T) {
+		var possibleDups []types.Tuple
+		u, err := NewApproximateUniqueKeyKVGetter(types.Format_Default, kvGetFuncForTuples(withDup...), nil, 100, 0.001, func(ctx context.Context, k, v types.Tuple) error {
+			possibleDups = append(possibleDups, k)
+			return nil
+		})
+		require.NoError(t, err)
+
+		rows := drainRowIter(t, NewDoltMapIter(ctx, u.Get, u.Close, conv))
+		assert.Len(t, rows, 4)
+		require.Len(t, possibleDups, 1)
+		assert.True(t, possibleDups[0].Equals(withDup[4]))
+		assert.Equal(t, uint64(1), u.PossibleDuplicates())
+
+		// without a callback possible duplicates are only counted
+		u, err = NewApproximateUniqueKeyKVGetter(types.Format_Default, kvGetFuncForTuples(withDup...), nil, 100, 0.001, nil)
+		require.NoError(t, err)
+		assert.Len(t, drainRowIter(t, NewDoltMapIter(ctx, u.Get, u.Close, conv)), 4)
+		assert.Equal(t, uint64(1), u.PossibleDuplicates())
+	})
+
+	t.Run("approximate callback error stops iteration", func(t *testing.T) {
+		stopErr := errors.New("confirmed duplicate")
+		u, err := NewApproximateUniqueKeyKVGetter(types.Format_Default, kvGetFuncForTuples(withDup...), nil, 100, 0.001, func(ctx context.Context, k, v types.Tuple) error {
+			return stopErr
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, _, err = u.Get(ctx)
+			require.NoError(t, err)
+		}
+
+		_, _, err = u.Get(ctx)
+		assert.Equal(t, stopErr, err)
+	})
+}
+
+func TestApproximateUniqueKeyKVGetterFalsePositive(t *testing.T) {
+	ctx := context.Background()
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mergeTestCols)
+	require.NoError(t, err)
+
+	// a filter sized for a single key is saturated by 200 unique keys, so some of them are false positives
+	var rows [][]interface{}
+	for i := 0; i < 200; i++ {
+		rows = append(rows, []interface{}{i, i, "v"})
+	}
+
+	reported := 0
+	u, err := NewApproximateUniqueKeyKVGetter(types.Format_Default, kvGetFuncForTuples(mergeTestKVs(t, rows...)...), nil, 1, 0.5, func(ctx context.Context, k, v types.Tuple) error {
+		reported++
+		return nil
+	})
+	require.NoError(t, err)
+
+	converted := drainRowIter(t, NewDoltMapIter(ctx, u.Get, u.Close, conv))
+	require.Greater(t, reported, 0)
+	assert.Equal(t, uint64(reported), u.PossibleDuplicates())
+
+	// every unique row is still returned
+	require.Len(t, converted, len(rows))
+	for i, r := range converted {
+		assert.Equal(t, int64(i), r[0])
+	}
+}
+
+func TestApproximateUniqueKeyKVGetterFalsePositiveRate(t *testing.T) {
+	const numKeys = 10000
+	const fpRate = 0.01
+
+	_, err := NewApproximateUniqueKeyKVGetter(types.Format_Default, nil, nil, numKeys, 0, nil)
+	assert.Error(t, err)
+	_, err = NewApproximateUniqueKeyKVGetter(types.Format_Default, nil, nil, numKeys, 1, nil)
+	assert.Error(t, err)
+
+	i := 0
+	get := func(ctx context.Context) (types.Tuple, types.Tuple, error) {
+		if i >= numKeys {
+			return types.Tuple{}, types.Tuple{}, io.EOF
+		}
+
+		k, err := types.NewTuple(types.Format_Default, types.Uint(0), types.Int(i))
+		i++
+		return k, types.EmptyTuple(types.Format_Default), err
+	}
+
+	falsePositives := 0
+	u, err := NewApproximateUniqueKeyKVGetter(types.Format_Default, get, nil, numKeys, fpRate, func(ctx context.Context, k, v types.Tuple) error {
+		falsePositives++
+		return nil
+	})
+	require.NoError(t, err)
+
+	numRead := 0
+	for {
+		_, _, err := u.Get(context.Background())
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		numRead++
+	}
+
+	// keys reported as possible duplicates are still returned
+	assert.Equal(t, numKeys, numRead)
+
+	// every key is unique so each reported duplicate is a false positive.  Allow some slack over the configured rate.
+	assert.Less(t, float64(falsePositives), 2*numKeys*fpRate)
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DoltStructTag is the struct tag naming the column a field is read from by UnmarshalKV
+const DoltStructTag = "dolt"
+
+// UnmarshalKV converts the key and value given and stores the converted values in the struct pointed to by dest.  Each
+// field with a `dolt:"colname"` tag is set from the column with that name, matched case-insensitively.  Fields without
+// a tag, or tagged "-", are left untouched.  Pointer fields, including pointers to pointers, are allocated as needed
+// and NULL values set them to nil.  A NULL read into a field that is not a pointer is an error.  Values are stored in
+// fields of the converted value's type or, for numbers and strings, of another type of the same kind that can hold
+// the value.  Errors name the field which could not be set.
+func (conv *KVToSqlRowConverter) UnmarshalKV(k, v types.Value, dest interface{}) error {
+	destVal := reflect.ValueOf(dest)
+
+	if destVal.Kind() != reflect.Ptr || destVal.IsNil() || destVal.Elem().Kind() != reflect.Struct {
+		return errors.New("unmarshal destination must be a non-nil pointer to a struct")
+	}
+
+	r, err := conv.ConvertKVToSqlRow(k, v)
+
+	if err != nil {
+		return err
+	}
+
+	structVal := destVal.Elem()
+	structType := structVal.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		colName, ok := field.Tag.Lookup(DoltStructTag)
+
+		if !ok || colName == "-" {
+			continue
+		}
+
+		idx, ok := conv.colIdxForName(colName)
+
+		if !ok {
+			return fmt.Errorf("field %s is tagged with column '%s' which is not being converted", field.Name, colName)
+		}
+
+		fieldVal := structVal.Field(i)
+
+		if !fieldVal.CanSet() {
+			return fmt.Errorf("field %s is tagged with column '%s' but is not exported", field.Name, colName)
+		}
+
+		err = setField(fieldVal, SqlRowValue(r, idx))
+
+		if err != nil {
+			return fmt.Errorf("cannot set field %s from column '%s': %w", field.Name, colName, err)
+		}
+	}
+
+	return nil
+}
+
+// colIdxForName returns the index of the converted column with the name given, matched case-insensitively
+func (conv *KVToSqlRowConverter) colIdxForName(name string) (int, bool) {
+	for _, idx := range conv.tagToSqlColIdx {
+		if strings.EqualFold(conv.cols[idx].Name, name) {
+			return idx, true
+		}
+	}
+
+	return 0, false
+}
+
+func setField(fieldVal reflect.Value, val interface{}) error {
+	if val == nil {
+		if fieldVal.Kind() != reflect.Ptr {
+			return errors.New("NULL cannot be stored in a field that is not a pointer")
+		}
+
+		fieldVal.Set(reflect.Zero(fieldVal.Type()))
+		return nil
+	}
+
+	for fieldVal.Kind() == reflect.Ptr {
+		if fieldVal.IsNil() {
+			fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+		}
+
+		fieldVal = fieldVal.Elem()
+	}
+
+	src := reflect.ValueOf(val)
+	fieldType := fieldVal.Type()
+
+	if src.Type().AssignableTo(fieldType) {
+		fieldVal.Set(src)
+		return nil
+	}
+
+	switch {
+	case isIntKind(src.Kind()) && isIntKind(fieldType.Kind()):
+		if fieldVal.OverflowInt(src.Int()) {
+			return fmt.Errorf("value %d overflows %s", src.Int(), fieldType)
+		}
+
+		fieldVal.SetInt(src.Int())
+	case isUintKind(src.Kind()) && isUintKind(fieldType.Kind()):
+		if fieldVal.OverflowUint(src.Uint()) {
+			return fmt.Errorf("value %d overflows %s", src.Uint(), fieldType)
+		}
+
+		fieldVal.SetUint(src.Uint())
+	case isFloatKind(src.Kind()) && isFloatKind(fieldType.Kind()):
+		if fieldVal.OverflowFloat(src.Float()) {
+			return fmt.Errorf("value %v overflows %s", src.Float(), fieldType)
+		}
+
+		fieldVal.SetFloat(src.Float())
+	case src.Kind() == reflect.String && fieldType.Kind() == reflect.String:
+		fieldVal.SetString(src.String())
+	default:
+		return fmt.Errorf("value of type %s cannot be stored in a field of type %s", src.Type(), fieldType)
+	}
+
+	return nil
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+type unmarshalTestPerson struct {
+	ID      int32     `dolt:"id"`
+	Name    *string   `dolt:"NAME"`
+	Age     **uint8   `dolt:"age"`
+	Score   *float64  `dolt:"score"`
+	Created time.Time `dolt:"created"`
+	Skipped string    `dolt:"-"`
+	Other   string
+}
+
+func TestUnmarshalKV(t *testing.T) {
+	conv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols)
+	require.NoError(t, err)
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("all values", func(t *testing.T) {
+		k, v := mapIterTestTuples(t, 7, types.String("bill"), types.Uint(32), types.Float(9.5), types.Timestamp(created))
+
+		p := unmarshalTestPerson{Skipped: "skipped", Other: "other"}
+		require.NoError(t, conv.UnmarshalKV(k, v, &p))
+
+		assert.Equal(t, int32(7), p.ID)
+		require.NotNil(t, p.Name)
+		assert.Equal(t, "bill", *p.Name)
+		require.NotNil(t, p.Age)
+		require.NotNil(t, *p.Age)
+		assert.Equal(t, uint8(32), **p.Age)
+		require.NotNil(t, p.Score)
+		assert.Equal(t, 9.5, *p.Score)
+		assert.True(t, created.Equal(p.Created))
+		assert.Equal(t, "skipped", p.Skipped)
+		assert.Equal(t, "other", p.Other)
+	})
+
+	t.Run("nulls into pointers", func(t *testing.T) {
+		k, v := mapIterTestTuples(t, 7, nil, nil, nil, types.Timestamp(created))
+
+		name, score := "stale", 1.0
+		p := unmarshalTestPerson{Name: &name, Score: &score}
+		require.NoError(t, conv.UnmarshalKV(k, v, &p))
+
+		assert.Nil(t, p.Name)
+		assert.Nil(t, p.Age)
+		assert.Nil(t, p.Score)
+	})
+
+	t.Run("trailing nulls trimmed", func(t *testing.T) {
+		trimConv, err := NewKVToSqlRowConverterForCols(types.Format_Default, mapIterTestCols, WithTrailingNullsTrimmed())
+		require.NoError(t, err)
+
+		var dest struct {
+			ID      int32      `dolt:"id"`
+			Name    *string    `dolt:"name"`
+			Score   *float64   `dolt:"score"`
+			Created *time.Time `dolt:"created"`
+		}
+
+		// the converted row ends after the name
+		k, v := mapIterTestTuples(t, 7, types.String("bill"))
+		require.NoError(t, trimConv.UnmarshalKV(k, v, &dest))
+
+		assert.Equal(t, int32(7), dest.ID)
+		require.NotNil(t, dest.Name)
+		assert.Equal(t, "bill", *dest.Name)
+		assert.Nil(t, dest.Score)
+		assert.Nil(t, dest.Created)
+	})
+
+	t.Run("null into non pointer", func(t *testing.T) {
+		k, v := mapIterTestTuples(t, 7, types.String("bill"))
+		err := conv.UnmarshalKV(k, v, &unmarshalTestPerson{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field Created")
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		k, v := mapIterTestTuples(t, 7, types.String("bill"), types.Uint(300), nil, types.Timestamp(created))
+		err := conv.UnmarshalKV(k, v, &unmarshalTestPerson{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field Age")
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		var dest struct {
+			Name int64 `dolt:"name"`
+		}
+
+		k, v := mapIterTestTuples(t, 7, types.String("bill"))
+		err := conv.UnmarshalKV(k, v, &dest)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field Name")
+	})
+
+	t.Run("tag for unknown column", func(t *testing.T) {
+		var dest struct {
+			Missing string `dolt:"missing"`
+		}
+
+		k, v := mapIterTestTuples(t, 7, types.String("bill"))
+		err := conv.UnmarshalKV(k, v, &dest)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field Missing")
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		k, v := mapIterTestTuples(t, 7, types.String("bill"))
+		assert.Error(t, conv.UnmarshalKV(k, v, unmarshalTestPerson{}))
+		assert.Error(t, conv.UnmarshalKV(k, v, (*unmarshalTestPerson)(nil)))
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// ErrSortWindowExceeded is returned by a WindowSortIter which reads a row that belongs before a row it has already
+// emitted
+var ErrSortWindowExceeded = errors.New("row is out of order by more than the sort window")
+
+// WindowSortIter emits the rows of an inner sql.RowIter whose rows are nearly in the order of a list of sort columns,
+// such as the rows of a merge with minor reordering, in sorted order.  It holds the next window rows in a min heap and
+// emits the smallest, so any row which is out of place by less than window rows is corrected without the cost of an
+// ExternalSortIter.  If a row is further out of place than that an error wrapping ErrSortWindowExceeded is returned.
+// Equal rows are emitted in the order they were read.
+type WindowSortIter struct {
+	itr       sql.RowIter
+	sch       sql.Schema
+	sortCols  []SortColumn
+	window    int
+	rows      *windowSortHeap
+	numRead   uint64
+	exhausted bool
+	last      sql.Row
+}
+
+var _ sql.RowIter = (*WindowSortIter)(nil)
+
+// NewWindowSortIter returns a WindowSortIter which sorts the rows of itr, which are rows of the schema sch, by sortCols
+// holding at most window rows in memory
+func NewWindowSortIter(itr sql.RowIter, sch sql.Schema, sortCols []SortColumn, window int) (*WindowSortIter, error) {
+	err := validateSortColumns(sch, sortCols)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid sort window %d", window)
+	}
+
+	return &WindowSortIter{
+		itr:      itr,
+		sch:      sch,
+		sortCols: sortCols,
+		window:   window,
+		rows:     &windowSortHeap{sch: sch, sortCols: sortCols, rows: make([]windowSortRow, 0, window)},
+	}, nil
+}
+
+// Next returns the next row in sorted order until all rows are returned at which point (nil, io.EOF) is returned
+func (wsi *WindowSortIter) Next() (sql.Row, error) {
+	for !wsi.exhausted && wsi.rows.Len() < wsi.window {
+		r, err := wsi.itr.Next()
+
+		if err == io.EOF {
+			wsi.exhausted = true
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		heap.Push(wsi.rows, windowSortRow{r, wsi.numRead})
+		wsi.numRead++
+
+		if wsi.rows.err != nil {
+			return nil, wsi.rows.err
+		}
+	}
+
+	if wsi.rows.Len() == 0 {
+		return nil, io.EOF
+	}
+
+	next := heap.Pop(wsi.rows).(windowSortRow)
+
+	if wsi.rows.err != nil {
+		return nil, wsi.rows.err
+	}
+
+	if wsi.last != nil {
+		cmp, err := compareSortRows(wsi.sch, wsi.sortCols, next.r, wsi.last)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp < 0 {
+			return nil, fmt.Errorf("%w of %d rows: row %d %v belongs before the already emitted row %v", ErrSortWindowExceeded, wsi.window, next.seq, next.r, wsi.last)
+		}
+	}
+
+	wsi.last = next.r
+	return next.r, nil
+}
+
+// Close closes the inner iterator
+func (wsi *WindowSortIter) Close(ctx *sql.Context) error {
+	wsi.rows.rows = nil
+	return wsi.itr.Close(ctx)
+}
+
+// windowSortRow is a buffered row along with its position in the input
+type windowSortRow struct {
+	r   sql.Row
+	seq uint64
+}
+
+// windowSortHeap is a min heap of buffered rows
+type windowSortHeap struct {
+	sch      sql.Schema
+	sortCols []SortColumn
+	rows     []windowSortRow
+	err      error
+}
+
+var _ heap.Interface = (*windowSortHeap)(nil)
+
+func (h *windowSortHeap) Len() int {
+	return len(h.rows)
+}
+
+func (h *windowSortHeap) Less(i, j int) bool {
+	cmp, err := compareSortRows(h.sch, h.sortCols, h.rows[i].r, h.rows[j].r)
+
+	if err != nil && h.err == nil {
+		h.err = err
+	}
+
+	if cmp == 0 {
+		return h.rows[i].seq < h.rows[j].seq
+	}
+
+	return cmp < 0
+}
+
+func (h *windowSortHeap) Swap(i, j int) {
+	h.rows[i], h.rows[j] = h.rows[j], h.rows[i]
+}
+
+func (h *windowSortHeap) Push(x interface{}) {
+	h.rows = append(h.rows, x.(windowSortRow))
+}
+
+func (h *windowSortHeap) Pop() interface{} {
+	last := h.rows[len(h.rows)-1]
+	h.rows = h.rows[:len(h.rows)-1]
+	return last
+}
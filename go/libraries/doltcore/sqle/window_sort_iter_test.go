@@ -0,0 +1,139 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var windowSortSch = sql.Schema{
+	{Name: "id", Type: sql.Int64},
+	{Name: "name", Type: sql.Text, Nullable: true},
+}
+
+func windowSortRows(ids ...int64) []sql.Row {
+	rows := make([]sql.Row, len(ids))
+	for i, id := range ids {
+		rows[i] = sql.Row{id, string(rune('a' + i))}
+	}
+
+	return rows
+}
+
+func TestWindowSortIter(t *testing.T) {
+	_, err := NewWindowSortIter(sql.RowsToRowIter(), windowSortSch, []SortColumn{{Idx: 0}}, 0)
+	assert.Error(t, err)
+	_, err = NewWindowSortIter(sql.RowsToRowIter(), windowSortSch, []SortColumn{{Idx: 2}}, 2)
+	assert.Error(t, err)
+
+	tests := []struct {
+		name     string
+		ids      []int64
+		sortCols []SortColumn
+		window   int
+		expected []sql.Row
+	}{
+		{
+			name:     "empty",
+			sortCols: []SortColumn{{Idx: 0}},
+			window:   3,
+		},
+		{
+			name:     "already sorted",
+			ids:      []int64{1, 2, 3, 4},
+			sortCols: []SortColumn{{Idx: 0}},
+			window:   1,
+			expected: windowSortRows(1, 2, 3, 4),
+		},
+		{
+			name:     "disorder within the window",
+			ids:      []int64{2, 1, 3, 6, 4, 5, 7},
+			sortCols: []SortColumn{{Idx: 0}},
+			window:   3,
+			expected: []sql.Row{{int64(1), "b"}, {int64(2), "a"}, {int64(3), "c"}, {int64(4), "e"}, {int64(5), "f"}, {int64(6), "d"}, {int64(7), "g"}},
+		},
+		{
+			name:     "ties keep input order",
+			ids:      []int64{2, 1, 2, 1},
+			sortCols: []SortColumn{{Idx: 0}},
+			window:   4,
+			expected: []sql.Row{{int64(1), "b"}, {int64(1), "d"}, {int64(2), "a"}, {int64(2), "c"}},
+		},
+		{
+			name:     "descending",
+			ids:      []int64{3, 4, 2, 1},
+			sortCols: []SortColumn{{Idx: 0, Descending: true}},
+			window:   2,
+			expected: []sql.Row{{int64(4), "b"}, {int64(3), "a"}, {int64(2), "c"}, {int64(1), "d"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			itr, err := NewWindowSortIter(sql.RowsToRowIter(windowSortRows(test.ids...)...), windowSortSch, test.sortCols, test.window)
+			require.NoError(t, err)
+
+			var rows []sql.Row
+			for {
+				r, err := itr.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				rows = append(rows, r)
+			}
+
+			assert.Equal(t, test.expected, rows)
+			assert.NoError(t, itr.Close(sql.NewEmptyContext()))
+		})
+	}
+}
+
+func TestWindowSortIterWindowExceeded(t *testing.T) {
+	// 1 is 3 rows out of place, so it can't be corrected by a window of 3
+	itr, err := NewWindowSortIter(sql.RowsToRowIter(windowSortRows(2, 3, 4, 1, 5)...), windowSortSch, []SortColumn{{Idx: 0}}, 3)
+	require.NoError(t, err)
+
+	r, err := itr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), r[0])
+
+	_, err = itr.Next()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrSortWindowExceeded))
+	assert.Contains(t, err.Error(), "window of 3 rows")
+
+	// a larger window corrects it
+	itr, err = NewWindowSortIter(sql.RowsToRowIter(windowSortRows(2, 3, 4, 1, 5)...), windowSortSch, []SortColumn{{Idx: 0}}, 4)
+	require.NoError(t, err)
+
+	var ids []interface{}
+	for {
+		r, err := itr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, r[0])
+	}
+
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5)}, ids)
+}
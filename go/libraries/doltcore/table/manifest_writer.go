@@ -0,0 +1,160 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ManifestExt is the extension appended to the path of an export to get the path of its manifest
+const ManifestExt = ".manifest.json"
+
+// ManifestPathForExport returns the path of the manifest written alongside the export at exportPath
+func ManifestPathForExport(exportPath string) string {
+	return exportPath + ManifestExt
+}
+
+// ExportManifest is the metadata written alongside an export so consumers can verify it
+type ExportManifest struct {
+	// RowCount is the number of rows exported
+	RowCount uint64 `json:"row_count"`
+	// Columns holds the metadata of each column of the exported schema, in schema order
+	Columns []ManifestColumn `json:"columns"`
+	// SHA256 is the hex encoded SHA-256 of the values of the rows exported in the order they were written.  It is
+	// computed from the stored values rather than the bytes written, so an export of the same rows has the same hash in
+	// every file format.
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestColumn is the metadata of a column of an ExportManifest
+type ManifestColumn struct {
+	// Name is the name of the column
+	Name string `json:"name"`
+	// NullCount is the number of NULL values exported in the column
+	NullCount uint64 `json:"null_count"`
+}
+
+// ManifestWriter is a TableWriteCloser which passes rows to another TableWriteCloser while accumulating an
+// ExportManifest of them, which is written as JSON to a file alongside the export when it is closed
+type ManifestWriter struct {
+	wr         TableWriteCloser
+	fs         filesys.WritableFS
+	path       string
+	rowCount   uint64
+	nullCounts []uint64
+	hash       hash.Hash
+}
+
+// NewManifestWriter returns a ManifestWriter which writes rows to wr, which exports to exportPath, and writes the
+// manifest of the export to the path returned by ManifestPathForExport for exportPath in fs when closed
+func NewManifestWriter(wr TableWriteCloser, fs filesys.WritableFS, exportPath string) *ManifestWriter {
+	return &ManifestWriter{
+		wr:         wr,
+		fs:         fs,
+		path:       ManifestPathForExport(exportPath),
+		nullCounts: make([]uint64, wr.GetSchema().GetAllCols().Size()),
+		hash:       sha256.New(),
+	}
+}
+
+// GetSchema gets the schema of the rows that this writer writes
+func (mw *ManifestWriter) GetSchema() schema.Schema {
+	return mw.wr.GetSchema()
+}
+
+// WriteRow writes a row to the underlying writer, and adds it to the manifest once it is written
+func (mw *ManifestWriter) WriteRow(ctx context.Context, r row.Row) error {
+	err := mw.wr.WriteRow(ctx, r)
+
+	if err != nil {
+		return err
+	}
+
+	nbf := r.Format()
+	var buf [8]byte
+	i := 0
+	_, err = r.IterSchema(mw.GetSchema(), func(tag uint64, val types.Value) (stop bool, err error) {
+		binary.BigEndian.PutUint64(buf[:], tag)
+		_, _ = mw.hash.Write(buf[:])
+
+		if types.IsNull(val) {
+			mw.nullCounts[i]++
+			_, _ = mw.hash.Write([]byte{0})
+		} else {
+			h, err := val.Hash(nbf)
+
+			if err != nil {
+				return false, err
+			}
+
+			_, _ = mw.hash.Write([]byte{1})
+			_, _ = mw.hash.Write(h[:])
+		}
+
+		i++
+		return false, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	mw.rowCount++
+	return nil
+}
+
+// Manifest returns the manifest of the rows written so far
+func (mw *ManifestWriter) Manifest() ExportManifest {
+	cols := mw.GetSchema().GetAllCols().GetColumns()
+	manifest := ExportManifest{
+		RowCount: mw.rowCount,
+		Columns:  make([]ManifestColumn, len(cols)),
+		SHA256:   hex.EncodeToString(mw.hash.Sum(nil)),
+	}
+
+	for i, col := range cols {
+		manifest.Columns[i] = ManifestColumn{Name: col.Name, NullCount: mw.nullCounts[i]}
+	}
+
+	return manifest
+}
+
+// Close closes the underlying writer and then writes the manifest.  No manifest is written if closing the underlying
+// writer fails, as the export can't be verified.
+func (mw *ManifestWriter) Close(ctx context.Context) error {
+	err := mw.wr.Close(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(mw.Manifest(), "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return mw.fs.WriteFile(mw.path, append(data, '\n'))
+}
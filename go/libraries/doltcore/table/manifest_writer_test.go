@@ -0,0 +1,96 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestManifestWriter(t *testing.T) {
+	ctx := context.Background()
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewColumn("score", 2, types.FloatKind, false),
+	))
+
+	var rows []row.Row
+	for i, vals := range []row.TaggedValues{
+		{0: types.Int(1), 1: types.String("bill"), 2: types.Float(2.5)},
+		{0: types.Int(2), 2: types.Float(3)},
+		{0: types.Int(3)},
+	} {
+		r, err := row.New(types.Format_Default, sch, vals)
+		require.NoError(t, err, "row %d", i)
+		rows = append(rows, r)
+	}
+
+	exportPath := filepath.Join("export", "data.csv")
+	fs := filesys.NewInMemFS(nil, nil, "")
+	imt := NewInMemTable(sch)
+	mw := NewManifestWriter(NewInMemTableWriter(imt), fs, exportPath)
+
+	for _, r := range rows {
+		require.NoError(t, mw.WriteRow(ctx, r))
+	}
+
+	require.NoError(t, mw.Close(ctx))
+	assert.Equal(t, len(rows), imt.NumRows())
+
+	data, err := fs.ReadFile(exportPath + ".manifest.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"row_count": 3,
+		"columns": [{"name": "id", "null_count": 0}, {"name": "name", "null_count": 2}, {"name": "score", "null_count": 1}],
+		"sha256": "3758c8ffa31df711f4b1425b2218a042e1c81c576cccb25c068e8691babb7e57"
+	}`, string(data))
+
+	// the hash depends on the values and their order
+	mw = NewManifestWriter(NewInMemTableWriter(NewInMemTable(sch)), fs, exportPath)
+	for i := len(rows) - 1; i >= 0; i-- {
+		require.NoError(t, mw.WriteRow(ctx, rows[i]))
+	}
+	assert.NotEqual(t, "3758c8ffa31df711f4b1425b2218a042e1c81c576cccb25c068e8691babb7e57", mw.Manifest().SHA256)
+	assert.Equal(t, uint64(3), mw.Manifest().RowCount)
+
+	t.Run("close error", func(t *testing.T) {
+		fs := filesys.NewInMemFS(nil, nil, "")
+		mw := NewManifestWriter(failingCloseWriter{NewInMemTableWriter(NewInMemTable(sch))}, fs, exportPath)
+		require.NoError(t, mw.WriteRow(ctx, rows[0]))
+		assert.Error(t, mw.Close(ctx))
+
+		exists, _ := fs.Exists(ManifestPathForExport(exportPath))
+		assert.False(t, exists)
+	})
+}
+
+type failingCloseWriter struct {
+	*InMemTableWriter
+}
+
+func (w failingCloseWriter) Close(ctx context.Context) error {
+	return errors.New("close failed")
+}
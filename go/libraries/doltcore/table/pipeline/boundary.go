@@ -0,0 +1,84 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+// BoundaryStage is the name of the transform stage created by BoundaryInserter.NamedTransform
+const BoundaryStage = "boundary"
+
+// SectionChangeFunc returns true if next, the row following prev in a sorted stream of rows, begins a new section
+type SectionChangeFunc func(prev, next RowWithProps) bool
+
+// SyntheticRowFunc returns the row to insert at the boundary between the section ending with prev and the section
+// beginning with next, such as a blank spacer or a subtotal placeholder
+type SyntheticRowFunc func(prev, next RowWithProps) RowWithProps
+
+// BoundaryInserter inserts synthetic rows into a stream of rows between the logical sections of a report.  Every row
+// it reads is passed on unchanged and in order, so rows are only ever added.  It is the building block for rendering
+// subtotals, where the synthetic row for a boundary is built from the rows of the section which ended.
+type BoundaryInserter struct {
+	changed SectionChangeFunc
+	synth   SyntheticRowFunc
+}
+
+// NewBoundaryInserter returns a BoundaryInserter which inserts the row returned by synth between each pair of
+// consecutive rows for which changed returns true.  No row is inserted before the first row or after the last.
+func NewBoundaryInserter(changed SectionChangeFunc, synth SyntheticRowFunc) *BoundaryInserter {
+	return &BoundaryInserter{changed: changed, synth: synth}
+}
+
+// InsertBoundaries reads rows from inChan until it is closed, or until stopChan is closed, writing each to outChan
+// preceded by a synthetic row when it begins a new section.  Used as the TransformFunc of a NamedTransform.
+func (bi *BoundaryInserter) InsertBoundaries(inChan <-chan RowWithProps, outChan chan<- RowWithProps, badRowChan chan<- *TransformRowFailure, stopChan <-chan struct{}) {
+	var prev RowWithProps
+	hasPrev := false
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case r, ok := <-inChan:
+			if !ok {
+				return
+			}
+
+			if hasPrev && bi.changed(prev, r) {
+				if !sendRow(outChan, stopChan, bi.synth(prev, r)) {
+					return
+				}
+			}
+
+			if !sendRow(outChan, stopChan, r) {
+				return
+			}
+
+			prev, hasPrev = r, true
+		}
+	}
+}
+
+// NamedTransform returns a NamedTransform which applies this BoundaryInserter to the rows of a pipeline
+func (bi *BoundaryInserter) NamedTransform() NamedTransform {
+	return NamedTransform{BoundaryStage, bi.InsertBoundaries}
+}
+
+// sendRow writes r to outChan, returning false if stopChan is closed first
+func sendRow(outChan chan<- RowWithProps, stopChan <-chan struct{}, r RowWithProps) bool {
+	select {
+	case outChan <- r:
+		return true
+	case <-stopChan:
+		return false
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestBoundaryInserter(t *testing.T) {
+	sch := schema.UnkeyedSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("section", 0, types.StringKind, false),
+		schema.NewColumn("item", 1, types.StringKind, false),
+	))
+
+	newRow := func(section, item string) RowWithProps {
+		r, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.String(section), 1: types.String(item)})
+		require.NoError(t, err)
+		return RowWithProps{r, NoProps}
+	}
+
+	spacerRow, err := row.New(types.Format_Default, sch, row.TaggedValues{})
+	require.NoError(t, err)
+	spacer := RowWithProps{spacerRow, NoProps.Set(map[string]interface{}{"spacer": true})}
+
+	sectionChanged := func(prev, next RowWithProps) bool {
+		prevSection, _ := prev.Row.GetColVal(0)
+		nextSection, _ := next.Row.GetColVal(0)
+		return !prevSection.Equals(nextSection)
+	}
+
+	var boundaries [][2]string
+	bi := NewBoundaryInserter(sectionChanged, func(prev, next RowWithProps) RowWithProps {
+		prevItem, _ := prev.Row.GetColVal(1)
+		nextItem, _ := next.Row.GetColVal(1)
+		boundaries = append(boundaries, [2]string{string(prevItem.(types.String)), string(nextItem.(types.String))})
+		return spacer
+	})
+
+	tests := []struct {
+		name       string
+		inRows     []RowWithProps
+		expected   []string
+		boundaries [][2]string
+	}{
+		{
+			name:     "empty",
+			expected: nil,
+		},
+		{
+			name:     "one section",
+			inRows:   []RowWithProps{newRow("a", "1"), newRow("a", "2")},
+			expected: []string{"a/1", "a/2"},
+		},
+		{
+			name:       "sections",
+			inRows:     []RowWithProps{newRow("a", "1"), newRow("a", "2"), newRow("b", "3"), newRow("c", "4"), newRow("c", "5")},
+			expected:   []string{"a/1", "a/2", "spacer", "b/3", "spacer", "c/4", "c/5"},
+			boundaries: [][2]string{{"2", "3"}, {"3", "4"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			boundaries = nil
+
+			inChan := make(chan RowWithProps, len(test.inRows))
+			outChan := make(chan RowWithProps, 2*len(test.inRows))
+			badRowChan := make(chan *TransformRowFailure, len(test.inRows))
+			stopChan := make(chan struct{})
+
+			for _, r := range test.inRows {
+				inChan <- r
+			}
+			close(inChan)
+
+			bi.NamedTransform().Func(inChan, outChan, badRowChan, stopChan)
+			close(outChan)
+
+			var results []string
+			for r := range outChan {
+				if _, isSpacer := r.Props.Get("spacer"); isSpacer {
+					results = append(results, "spacer")
+					continue
+				}
+
+				section, _ := r.Row.GetColVal(0)
+				item, _ := r.Row.GetColVal(1)
+				results = append(results, string(section.(types.String))+"/"+string(item.(types.String)))
+			}
+
+			assert.Empty(t, badRowChan)
+			assert.Equal(t, test.expected, results)
+			assert.Equal(t, test.boundaries, boundaries)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// CoalesceStage is the name of the transform stage created by CoalesceTransformer.NamedTransform
+const CoalesceStage = "coalesce"
+
+// CoalesceTransformer replaces NULL values in rows flowing through a pipeline with a default value for the column.
+// Columns without a default are left untouched.
+type CoalesceTransformer struct {
+	sch      schema.Schema
+	defaults map[uint64]types.Value
+}
+
+// NewCoalesceTransformer returns a CoalesceTransformer for rows of the schema given that replaces NULLs in each column
+// of defaults with the value for that column's tag.  An error is returned if a tag is not in the schema, or if a
+// default is NULL or is not of the column's kind.
+func NewCoalesceTransformer(sch schema.Schema, defaults map[uint64]types.Value) (*CoalesceTransformer, error) {
+	allCols := sch.GetAllCols()
+	for tag, val := range defaults {
+		col, ok := allCols.GetByTag(tag)
+
+		if !ok {
+			return nil, fmt.Errorf("cannot coalesce column with tag %d which is not in the schema", tag)
+		}
+
+		if types.IsNull(val) {
+			return nil, fmt.Errorf("default for column '%s' cannot be NULL", col.Name)
+		}
+
+		if val.Kind() != col.Kind {
+			return nil, fmt.Errorf("default for column '%s' is of kind %s but the column is of kind %s", col.Name, val.Kind().String(), col.Kind.String())
+		}
+	}
+
+	return &CoalesceTransformer{sch, defaults}, nil
+}
+
+// ProcessRow replaces the NULL values in the row given with their defaults.  Used as the transform function in a
+// NamedTransform.
+func (ct *CoalesceTransformer) ProcessRow(inRow row.Row, props ReadableMap) ([]*TransformedRowResult, string) {
+	var err error
+	outRow := inRow
+	for tag, val := range ct.defaults {
+		if currVal, ok := outRow.GetColVal(tag); ok && !types.IsNull(currVal) {
+			continue
+		}
+
+		outRow, err = outRow.SetColVal(tag, val, ct.sch)
+
+		if err != nil {
+			return nil, err.Error()
+		}
+	}
+
+	return []*TransformedRowResult{{RowData: outRow}}, ""
+}
+
+// NamedTransform returns a NamedTransform which applies this CoalesceTransformer to every row
+func (ct *CoalesceTransformer) NamedTransform() NamedTransform {
+	return NewNamedTransform(CoalesceStage, ct.ProcessRow)
+}
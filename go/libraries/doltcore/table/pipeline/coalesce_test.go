@@ -0,0 +1,79 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCoalesceTransformer(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewColumn("age", 2, types.UintKind, false),
+		schema.NewColumn("city", 3, types.StringKind, false),
+	))
+
+	_, err := NewCoalesceTransformer(sch, map[uint64]types.Value{1: types.Uint(0)})
+	assert.Error(t, err)
+	_, err = NewCoalesceTransformer(sch, map[uint64]types.Value{10: types.String("")})
+	assert.Error(t, err)
+
+	ct, err := NewCoalesceTransformer(sch, map[uint64]types.Value{1: types.String("unknown"), 2: types.Uint(0)})
+	require.NoError(t, err)
+
+	inRows := []row.TaggedValues{
+		{0: types.Int(1)},
+		{0: types.Int(2), 1: types.String("bill"), 2: types.Uint(32)},
+		{0: types.Int(3), 2: types.Uint(40), 3: types.String("Seattle")},
+	}
+	expected := []row.TaggedValues{
+		{0: types.Int(1), 1: types.String("unknown"), 2: types.Uint(0)},
+		{0: types.Int(2), 1: types.String("bill"), 2: types.Uint(32)},
+		{0: types.Int(3), 1: types.String("unknown"), 2: types.Uint(40), 3: types.String("Seattle")},
+	}
+
+	inChan := make(chan RowWithProps, len(inRows))
+	outChan := make(chan RowWithProps, len(inRows))
+	badRowChan := make(chan *TransformRowFailure, len(inRows))
+	stopChan := make(chan struct{})
+
+	for _, taggedVals := range inRows {
+		r, err := row.New(types.Format_Default, sch, taggedVals)
+		require.NoError(t, err)
+		inChan <- RowWithProps{r, NoProps}
+	}
+	close(inChan)
+
+	ct.NamedTransform().Func(inChan, outChan, badRowChan, stopChan)
+	close(outChan)
+
+	var results []row.TaggedValues
+	for r := range outChan {
+		taggedVals, err := row.GetTaggedVals(r.Row)
+		require.NoError(t, err)
+		results = append(results, taggedVals)
+	}
+
+	assert.Empty(t, badRowChan)
+	assert.Equal(t, expected, results)
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// detailsVariablePattern matches the parts of failure details which vary from row to row: quoted values and numbers
+var detailsVariablePattern = regexp.MustCompile(`"[^"]*"|'[^']*'|-?\d+(\.\d+)?`)
+
+// DetailsPattern returns the details of a failure with quoted values replaced by "?" and numbers replaced by N, so
+// that failures of the same kind on different rows, such as `value "abc" is too long` and `value "de" is too long`,
+// have the same pattern.
+func DetailsPattern(details string) string {
+	return detailsVariablePattern.ReplaceAllStringFunc(details, func(match string) string {
+		switch match[0] {
+		case '"':
+			return `"?"`
+		case '\'':
+			return `'?'`
+		default:
+			return "N"
+		}
+	})
+}
+
+// FailureGroup is a set of failures of the same transform whose details have the same DetailsPattern
+type FailureGroup struct {
+	TransformName string
+	Pattern       string
+	Count         int
+	// Samples holds the first failures of the group, up to the number of samples the FailureCollector keeps
+	Samples []*TransformRowFailure
+}
+
+type failureGroupKey struct {
+	transformName string
+	pattern       string
+}
+
+// FailureCollector groups the TransformRowFailures of a pipeline by transform and DetailsPattern and counts them, so a
+// flood of per row failures can be reported as a short summary of the distinct problems.  A FailureCollector is not
+// safe for concurrent use, but the callback returned by BadRowCallback is only called from one goroutine.
+type FailureCollector struct {
+	maxSamples int
+	groups     map[failureGroupKey]*FailureGroup
+	order      []*FailureGroup
+	total      int
+}
+
+// NewFailureCollector returns a FailureCollector which keeps up to maxSamples failures of each group
+func NewFailureCollector(maxSamples int) *FailureCollector {
+	return &FailureCollector{maxSamples: maxSamples, groups: make(map[failureGroupKey]*FailureGroup)}
+}
+
+// Add adds a failure to its group
+func (fc *FailureCollector) Add(trf *TransformRowFailure) {
+	key := failureGroupKey{trf.TransformName, DetailsPattern(trf.Details)}
+	group, ok := fc.groups[key]
+
+	if !ok {
+		group = &FailureGroup{TransformName: key.transformName, Pattern: key.pattern}
+		fc.groups[key] = group
+		fc.order = append(fc.order, group)
+	}
+
+	group.Count++
+	fc.total++
+
+	if len(group.Samples) < fc.maxSamples {
+		group.Samples = append(group.Samples, trf)
+	}
+}
+
+// Drain adds every failure read from badRowChan until it is closed
+func (fc *FailureCollector) Drain(badRowChan <-chan *TransformRowFailure) {
+	for trf := range badRowChan {
+		fc.Add(trf)
+	}
+}
+
+// BadRowCallback returns a BadRowCallback which adds each failure and never quits the pipeline, for use with
+// Pipeline.SetBadRowCallback
+func (fc *FailureCollector) BadRowCallback() BadRowCallback {
+	return func(trf *TransformRowFailure) (quit bool) {
+		fc.Add(trf)
+		return false
+	}
+}
+
+// Total returns the number of failures added
+func (fc *FailureCollector) Total() int {
+	return fc.total
+}
+
+// Top returns the n groups with the most failures, most first.  Groups with the same count are ordered by when their
+// first failure was added.  An n <= 0 returns every group.
+func (fc *FailureCollector) Top(n int) []FailureGroup {
+	groups := make([]FailureGroup, len(fc.order))
+	for i, group := range fc.order {
+		groups[i] = *group
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+
+	if n > 0 && n < len(groups) {
+		groups = groups[:n]
+	}
+
+	return groups
+}
+
+// Report returns a summary of the n groups with the most failures, listing the count and pattern of each along with
+// the details of its samples, each followed by the row which failed formatted with sch if the failure has one.  An
+// n <= 0 reports every group.
+func (fc *FailureCollector) Report(ctx context.Context, sch schema.Schema, n int) string {
+	if fc.total == 0 {
+		return "no rows failed"
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%d rows failed with %d distinct errors", fc.total, len(fc.order))
+
+	top := fc.Top(n)
+	if len(top) < len(fc.order) {
+		fmt.Fprintf(sb, ", the %d most common are", len(top))
+	}
+
+	sb.WriteString(":\n")
+
+	for _, group := range top {
+		fmt.Fprintf(sb, "%d x %s: %s\n", group.Count, group.TransformName, group.Pattern)
+
+		for _, sample := range group.Samples {
+			fmt.Fprintf(sb, "    %s\n", sample.Details)
+
+			if sample.Row != nil {
+				fmt.Fprintf(sb, "        %s\n", row.Fmt(ctx, sample.Row, sch))
+			}
+		}
+	}
+
+	return sb.String()
+}
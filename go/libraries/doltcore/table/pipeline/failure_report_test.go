@@ -0,0 +1,89 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDetailsPattern(t *testing.T) {
+	assert.Equal(t, `value "?" is too long for column N`, DetailsPattern(`value "abcdef" is too long for column 3`))
+	assert.Equal(t, `'?' cannot be parsed as N`, DetailsPattern(`'x1' cannot be parsed as -1.5`))
+	assert.Equal(t, "no variable parts", DetailsPattern("no variable parts"))
+}
+
+func TestFailureCollector(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("val", 1, types.StringKind, false),
+	))
+
+	badRowChan := make(chan *TransformRowFailure, 32)
+	for i := 0; i < 5; i++ {
+		r, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.Int(i), 1: types.String(fmt.Sprint(i * 1000))})
+		require.NoError(t, err)
+		badRowChan <- &TransformRowFailure{Row: r, TransformName: "fwt", Details: fmt.Sprintf(`value "%d" is too long`, i*1000)}
+	}
+	for i := 0; i < 3; i++ {
+		badRowChan <- &TransformRowFailure{TransformName: "reader", Details: fmt.Sprintf("bad line %d", i)}
+	}
+	// the same details from another transform are a separate group
+	badRowChan <- &TransformRowFailure{TransformName: "writer", Details: "bad line 7"}
+	badRowChan <- &TransformRowFailure{TransformName: "writer", Details: "disk full"}
+	close(badRowChan)
+
+	fc := NewFailureCollector(2)
+	fc.Drain(badRowChan)
+	assert.Equal(t, 10, fc.Total())
+
+	top := fc.Top(0)
+	require.Len(t, top, 4)
+	assert.Equal(t, "fwt", top[0].TransformName)
+	assert.Equal(t, `value "?" is too long`, top[0].Pattern)
+	assert.Equal(t, 5, top[0].Count)
+	require.Len(t, top[0].Samples, 2)
+	assert.Equal(t, `value "0" is too long`, top[0].Samples[0].Details)
+	assert.Equal(t, `value "1000" is too long`, top[0].Samples[1].Details)
+	assert.Equal(t, 3, top[1].Count)
+	assert.Equal(t, "writer", top[2].TransformName)
+	assert.Equal(t, "bad line N", top[2].Pattern)
+	assert.Equal(t, "disk full", top[3].Pattern)
+
+	expected := `10 rows failed with 4 distinct errors, the 2 most common are:
+5 x fwt: value "?" is too long
+    value "0" is too long
+        id:0 | val:"0"
+    value "1000" is too long
+        id:1 | val:"1000"
+3 x reader: bad line N
+    bad line 0
+    bad line 1
+`
+	assert.Equal(t, expected, fc.Report(context.Background(), sch, 2))
+
+	fc = NewFailureCollector(0)
+	assert.Equal(t, "no rows failed", fc.Report(context.Background(), sch, 5))
+	assert.False(t, fc.BadRowCallback()(&TransformRowFailure{TransformName: "fwt", Details: "x"}))
+	assert.Equal(t, "1 rows failed with 1 distinct errors:\n1 x fwt: x\n", fc.Report(context.Background(), sch, 5))
+}
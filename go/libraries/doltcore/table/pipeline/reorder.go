@@ -0,0 +1,128 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ColumnReorderStage is the name of the transform stage created by ColumnReorderTransformer.NamedTransform
+const ColumnReorderStage = "reorder_columns"
+
+// ReorderedSchemaProp is the property set on the first row emitted by a ColumnReorderTransformer.  Its value is the
+// schema.Schema of the reordered rows.
+const ReorderedSchemaProp = "reordered_schema"
+
+// ColumnReorderTransformer rewrites rows flowing through a pipeline so that their columns are in a new order.  Columns
+// which are not listed are dropped.  The reordered rows are for display and export, so the output schema has no
+// primary key.
+type ColumnReorderTransformer struct {
+	inSch      schema.Schema
+	outSch     schema.Schema
+	tags       []uint64
+	sentSchema bool
+}
+
+// NewColumnReorderTransformer returns a ColumnReorderTransformer which reorders rows of inSch so that their columns
+// are in the order of tags.  An error is returned if a tag is not in inSch or is listed more than once.
+func NewColumnReorderTransformer(inSch schema.Schema, tags []uint64) (*ColumnReorderTransformer, error) {
+	allCols := inSch.GetAllCols()
+	seen := make(map[uint64]bool, len(tags))
+	cols := make([]schema.Column, len(tags))
+	for i, tag := range tags {
+		col, ok := allCols.GetByTag(tag)
+
+		if !ok {
+			return nil, fmt.Errorf("cannot reorder column with tag %d which is not in the schema", tag)
+		}
+
+		if seen[tag] {
+			return nil, fmt.Errorf("column '%s' with tag %d is listed more than once", col.Name, tag)
+		}
+
+		seen[tag] = true
+		cols[i] = col
+	}
+
+	outSch := schema.UnkeyedSchemaFromCols(schema.NewColCollection(cols...))
+	return &ColumnReorderTransformer{inSch: inSch, outSch: outSch, tags: tags}, nil
+}
+
+// OutSch returns the schema of the reordered rows
+func (crt *ColumnReorderTransformer) OutSch() schema.Schema {
+	return crt.outSch
+}
+
+// ProcessRow returns the row given with its columns reordered.  Rows with a value for a column which is not in the
+// input schema, or with a value of the wrong kind for its column, are bad rows.  Used as the transform function in a
+// NamedTransform.
+func (crt *ColumnReorderTransformer) ProcessRow(inRow row.Row, props ReadableMap) ([]*TransformedRowResult, string) {
+	inCols := crt.inSch.GetAllCols()
+
+	var badRowDetails string
+	_, err := inRow.IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+		col, ok := inCols.GetByTag(tag)
+
+		if !ok {
+			badRowDetails = fmt.Sprintf("row has a value for tag %d which is not in the schema", tag)
+			return true, nil
+		}
+
+		if !types.IsNull(val) && val.Kind() != col.Kind {
+			badRowDetails = fmt.Sprintf("column '%s' is of kind %s but the row has a value of kind %s", col.Name, col.Kind.String(), val.Kind().String())
+			return true, nil
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	if badRowDetails != "" {
+		return nil, badRowDetails
+	}
+
+	taggedVals := make(row.TaggedValues, len(crt.tags))
+	for _, tag := range crt.tags {
+		if val, ok := inRow.GetColVal(tag); ok {
+			taggedVals[tag] = val
+		}
+	}
+
+	outRow, err := row.New(inRow.Format(), crt.outSch, taggedVals)
+
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	result := &TransformedRowResult{RowData: outRow}
+	if !crt.sentSchema {
+		result.PropertyUpdates = map[string]interface{}{ReorderedSchemaProp: crt.outSch}
+		crt.sentSchema = true
+	}
+
+	return []*TransformedRowResult{result}, ""
+}
+
+// NamedTransform returns a NamedTransform which applies this ColumnReorderTransformer to every row
+func (crt *ColumnReorderTransformer) NamedTransform() NamedTransform {
+	return NewNamedTransform(ColumnReorderStage, crt.ProcessRow)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestColumnReorderTransformer(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewColumn("age", 2, types.UintKind, false),
+		schema.NewColumn("city", 3, types.StringKind, false),
+	))
+
+	_, err := NewColumnReorderTransformer(sch, []uint64{3, 10})
+	assert.Error(t, err)
+	_, err = NewColumnReorderTransformer(sch, []uint64{3, 1, 3})
+	assert.Error(t, err)
+
+	crt, err := NewColumnReorderTransformer(sch, []uint64{3, 0, 1})
+	require.NoError(t, err)
+
+	var outNames []string
+	_ = crt.OutSch().GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		outNames = append(outNames, col.Name)
+		return false, nil
+	})
+	assert.Equal(t, []string{"city", "id", "name"}, outNames)
+
+	// a row with a column the transformer's schema doesn't have
+	otherSch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("extra", 9, types.StringKind, false),
+	))
+	badRow, err := row.New(types.Format_Default, otherSch, row.TaggedValues{0: types.Int(3), 9: types.String("?")})
+	require.NoError(t, err)
+
+	inRows := []row.TaggedValues{
+		{0: types.Int(1), 1: types.String("bill"), 2: types.Uint(32), 3: types.String("Seattle")},
+		{0: types.Int(2), 2: types.Uint(40)},
+	}
+	expected := []row.TaggedValues{
+		{0: types.Int(1), 1: types.String("bill"), 3: types.String("Seattle")},
+		{0: types.Int(2)},
+	}
+
+	inChan := make(chan RowWithProps, len(inRows)+1)
+	outChan := make(chan RowWithProps, len(inRows)+1)
+	badRowChan := make(chan *TransformRowFailure, len(inRows)+1)
+	stopChan := make(chan struct{})
+
+	for _, taggedVals := range inRows {
+		r, err := row.New(types.Format_Default, sch, taggedVals)
+		require.NoError(t, err)
+		inChan <- RowWithProps{r, NoProps}
+	}
+	inChan <- RowWithProps{badRow, NoProps}
+	close(inChan)
+
+	crt.NamedTransform().Func(inChan, outChan, badRowChan, stopChan)
+	close(outChan)
+	close(badRowChan)
+
+	var results []row.TaggedValues
+	var schProps []interface{}
+	for r := range outChan {
+		taggedVals, err := row.GetTaggedVals(r.Row)
+		require.NoError(t, err)
+		results = append(results, taggedVals)
+
+		schProp, _ := r.Props.Get(ReorderedSchemaProp)
+		schProps = append(schProps, schProp)
+	}
+
+	assert.Equal(t, expected, results)
+	assert.Equal(t, []interface{}{crt.OutSch(), nil}, schProps)
+
+	var failures []*TransformRowFailure
+	for failure := range badRowChan {
+		failures = append(failures, failure)
+	}
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, ColumnReorderStage, failures[0].TransformName)
+	assert.Equal(t, badRow, failures[0].Row)
+}
+
+func TestColumnReorderTransformerStop(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+	))
+
+	crt, err := NewColumnReorderTransformer(sch, []uint64{1, 0})
+	require.NoError(t, err)
+
+	r, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.Int(1)})
+	require.NoError(t, err)
+
+	inChan := make(chan RowWithProps, 1)
+	outChan := make(chan RowWithProps, 1)
+	badRowChan := make(chan *TransformRowFailure, 1)
+	stopChan := make(chan struct{})
+
+	inChan <- RowWithProps{r, NoProps}
+	close(stopChan)
+
+	crt.NamedTransform().Func(inChan, outChan, badRowChan, stopChan)
+	assert.Empty(t, outChan)
+}
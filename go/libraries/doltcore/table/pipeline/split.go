@@ -0,0 +1,184 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// ColumnGroup is a named set of columns which a ColumnGroupSplitter routes to their own output
+type ColumnGroup struct {
+	Name string
+	Tags []uint64
+}
+
+// columnGroupOutput is the schema and output channel of a ColumnGroup
+type columnGroupOutput struct {
+	ColumnGroup
+	sch schema.Schema
+	ch  chan RowWithProps
+}
+
+// ColumnGroupSplitter fans the rows of a wide table out into a narrower row for each of a number of column groups, for
+// example to export each subject area of a table to its own file.  Each group's rows are sent on its own channel with
+// the columns of the group in the order listed.  A row without a value for any of a group's columns is not sent to that
+// group.  Like the rows of a ColumnReorderTransformer, the rows of each group are for display and export, so the
+// group schemas have no primary key.
+type ColumnGroupSplitter struct {
+	inSch  schema.Schema
+	groups []*columnGroupOutput
+	byName map[string]*columnGroupOutput
+}
+
+// NewColumnGroupSplitter returns a ColumnGroupSplitter which splits rows of inSch into the groups given.  Each group's
+// output channel has a buffer of bufferSize rows.  An error is returned if two groups have the same name, if a group
+// has no columns, or if a tag is not in inSch or is listed more than once within a group.  A column may be in more than
+// one group.
+func NewColumnGroupSplitter(inSch schema.Schema, groups []ColumnGroup, bufferSize int) (*ColumnGroupSplitter, error) {
+	allCols := inSch.GetAllCols()
+	outputs := make([]*columnGroupOutput, len(groups))
+	byName := make(map[string]*columnGroupOutput, len(groups))
+	for i, group := range groups {
+		if _, ok := byName[group.Name]; ok {
+			return nil, fmt.Errorf("column group '%s' is listed more than once", group.Name)
+		}
+
+		if len(group.Tags) == 0 {
+			return nil, fmt.Errorf("column group '%s' has no columns", group.Name)
+		}
+
+		seen := make(map[uint64]bool, len(group.Tags))
+		cols := make([]schema.Column, len(group.Tags))
+		for j, tag := range group.Tags {
+			col, ok := allCols.GetByTag(tag)
+
+			if !ok {
+				return nil, fmt.Errorf("column group '%s' has tag %d which is not in the schema", group.Name, tag)
+			}
+
+			if seen[tag] {
+				return nil, fmt.Errorf("column '%s' with tag %d is listed more than once in column group '%s'", col.Name, tag, group.Name)
+			}
+
+			seen[tag] = true
+			cols[j] = col
+		}
+
+		outputs[i] = &columnGroupOutput{
+			ColumnGroup: group,
+			sch:         schema.UnkeyedSchemaFromCols(schema.NewColCollection(cols...)),
+			ch:          make(chan RowWithProps, bufferSize),
+		}
+		byName[group.Name] = outputs[i]
+	}
+
+	return &ColumnGroupSplitter{inSch: inSch, groups: outputs, byName: byName}, nil
+}
+
+// GroupSch returns the schema of the rows of the named group
+func (cgs *ColumnGroupSplitter) GroupSch(name string) (schema.Schema, bool) {
+	out, ok := cgs.byName[name]
+
+	if !ok {
+		return nil, false
+	}
+
+	return out.sch, true
+}
+
+// GroupChan returns the channel the rows of the named group are sent on.  It is closed once all rows have been split
+// or splitting stops.  The channels of every group must be read concurrently, as splitting blocks while any group's
+// channel is full.
+func (cgs *ColumnGroupSplitter) GroupChan(name string) (<-chan RowWithProps, bool) {
+	out, ok := cgs.byName[name]
+
+	if !ok {
+		return nil, false
+	}
+
+	return out.ch, true
+}
+
+// Split reads rows from inChan until it is closed, or until stopChan is closed, sending the columns of each row
+// belonging to each group to that group's channel.  Rows which can't be split are sent to badRowChan.  Every group's
+// channel is closed when Split returns.
+func (cgs *ColumnGroupSplitter) Split(inChan <-chan RowWithProps, badRowChan chan<- *TransformRowFailure, stopChan <-chan struct{}) {
+	defer func() {
+		for _, out := range cgs.groups {
+			close(out.ch)
+		}
+	}()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case r, ok := <-inChan:
+			if !ok {
+				return
+			}
+
+			if !cgs.splitRow(r, badRowChan, stopChan) {
+				return
+			}
+		}
+	}
+}
+
+// OutFunc returns an OutFunc which splits the rows coming out of a pipeline, stopping when the pipeline stops
+func (cgs *ColumnGroupSplitter) OutFunc() OutFunc {
+	return func(p *Pipeline, ch <-chan RowWithProps, badRowChan chan<- *TransformRowFailure) {
+		cgs.Split(ch, badRowChan, p.stopChan)
+	}
+}
+
+// splitRow sends the row given to each group it has values for.  false is returned if stopChan was closed.
+func (cgs *ColumnGroupSplitter) splitRow(r RowWithProps, badRowChan chan<- *TransformRowFailure, stopChan <-chan struct{}) bool {
+	for _, out := range cgs.groups {
+		taggedVals := make(row.TaggedValues, len(out.Tags))
+		for _, tag := range out.Tags {
+			if val, ok := r.Row.GetColVal(tag); ok {
+				taggedVals[tag] = val
+			}
+		}
+
+		if len(taggedVals) == 0 {
+			continue
+		}
+
+		groupRow, err := row.New(r.Row.Format(), out.sch, taggedVals)
+
+		if err != nil {
+			select {
+			case badRowChan <- &TransformRowFailure{Row: r.Row, TransformName: "split " + out.Name, Details: err.Error()}:
+			case <-stopChan:
+				return false
+			}
+
+			continue
+		}
+
+		select {
+		case out.ch <- RowWithProps{Row: groupRow, Props: r.Props}:
+		case <-stopChan:
+			return false
+		}
+	}
+
+	return true
+}
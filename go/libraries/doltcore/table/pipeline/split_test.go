@@ -0,0 +1,157 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestColumnGroupSplitter(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewColumn("age", 2, types.UintKind, false),
+		schema.NewColumn("street", 3, types.StringKind, false),
+		schema.NewColumn("city", 4, types.StringKind, false),
+		schema.NewColumn("zip", 5, types.StringKind, false),
+	))
+
+	_, err := NewColumnGroupSplitter(sch, []ColumnGroup{{"a", []uint64{0}}, {"a", []uint64{1}}}, 0)
+	assert.Error(t, err)
+	_, err = NewColumnGroupSplitter(sch, []ColumnGroup{{"a", nil}}, 0)
+	assert.Error(t, err)
+	_, err = NewColumnGroupSplitter(sch, []ColumnGroup{{"a", []uint64{0, 10}}}, 0)
+	assert.Error(t, err)
+	_, err = NewColumnGroupSplitter(sch, []ColumnGroup{{"a", []uint64{0, 1, 0}}}, 0)
+	assert.Error(t, err)
+
+	groups := []ColumnGroup{
+		{"person", []uint64{0, 1, 2}},
+		{"address", []uint64{3, 4, 5}},
+	}
+	cgs, err := NewColumnGroupSplitter(sch, groups, 0)
+	require.NoError(t, err)
+
+	addressSch, ok := cgs.GroupSch("address")
+	require.True(t, ok)
+	var addressCols []string
+	_ = addressSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		addressCols = append(addressCols, col.Name)
+		return false, nil
+	})
+	assert.Equal(t, []string{"street", "city", "zip"}, addressCols)
+
+	_, ok = cgs.GroupChan("other")
+	assert.False(t, ok)
+
+	inRows := []row.TaggedValues{
+		{0: types.Int(1), 1: types.String("bill"), 2: types.Uint(32), 3: types.String("1 Main St"), 4: types.String("Seattle"), 5: types.String("98101")},
+		// no address columns
+		{0: types.Int(2), 1: types.String("rob")},
+		// no person columns
+		{4: types.String("Portland")},
+	}
+
+	inChan := make(chan RowWithProps, len(inRows))
+	badRowChan := make(chan *TransformRowFailure, len(inRows))
+	stopChan := make(chan struct{})
+	for _, taggedVals := range inRows {
+		r, err := row.New(types.Format_Default, sch, taggedVals)
+		require.NoError(t, err)
+		inChan <- RowWithProps{r, NoProps}
+	}
+	close(inChan)
+
+	go cgs.Split(inChan, badRowChan, stopChan)
+
+	// every group must be read concurrently
+	output := make(map[string][]row.TaggedValues)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		ch, ok := cgs.GroupChan(group.Name)
+		require.True(t, ok)
+
+		wg.Add(1)
+		go func(name string, ch <-chan RowWithProps) {
+			defer wg.Done()
+			for r := range ch {
+				taggedVals := make(row.TaggedValues)
+				_, _ = r.Row.IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+					taggedVals[tag] = val
+					return false, nil
+				})
+
+				mu.Lock()
+				output[name] = append(output[name], taggedVals)
+				mu.Unlock()
+			}
+		}(group.Name, ch)
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string][]row.TaggedValues{
+		"person": {
+			{0: types.Int(1), 1: types.String("bill"), 2: types.Uint(32)},
+			{0: types.Int(2), 1: types.String("rob")},
+		},
+		"address": {
+			{3: types.String("1 Main St"), 4: types.String("Seattle"), 5: types.String("98101")},
+			{4: types.String("Portland")},
+		},
+	}, output)
+	assert.Empty(t, badRowChan)
+}
+
+func TestColumnGroupSplitterStop(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+	))
+
+	cgs, err := NewColumnGroupSplitter(sch, []ColumnGroup{{"ids", []uint64{0}}, {"names", []uint64{1}}}, 0)
+	require.NoError(t, err)
+
+	r, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.Int(1), 1: types.String("bill")})
+	require.NoError(t, err)
+
+	inChan := make(chan RowWithProps, 1)
+	inChan <- RowWithProps{r, NoProps}
+	stopChan := make(chan struct{})
+	done := make(chan struct{})
+
+	// nothing reads the group channels so splitting blocks until stopped
+	go func() {
+		cgs.Split(inChan, make(chan *TransformRowFailure), stopChan)
+		close(done)
+	}()
+
+	close(stopChan)
+	<-done
+
+	for _, name := range []string{"ids", "names"} {
+		ch, _ := cgs.GroupChan(name)
+		for range ch {
+		}
+	}
+}
@@ -38,10 +38,11 @@ const jsonFooter = `]}`
 var WriteBufSize = 256 * 1024
 
 type JSONWriter struct {
-	closer      io.Closer
-	bWr         *bufio.Writer
-	sch         schema.Schema
-	rowsWritten int
+	closer        io.Closer
+	bWr           *bufio.Writer
+	sch           schema.Schema
+	rowsWritten   int
+	floatSpecials typeinfo.FloatSpecialRendering
 }
 
 func OpenJSONWriter(path string, fs filesys.WritableFS, outSch schema.Schema) (*JSONWriter, error) {
@@ -66,7 +67,13 @@ func NewJSONWriter(wr io.WriteCloser, outSch schema.Schema) (*JSONWriter, error)
 	if err != nil {
 		return nil, err
 	}
-	return &JSONWriter{closer: wr, bWr: bwr, sch: outSch}, nil
+	return &JSONWriter{closer: wr, bWr: bwr, sch: outSch, floatSpecials: typeinfo.NullFloatSpecialRendering}, nil
+}
+
+// SetFloatSpecials sets how NaN and infinite values of float columns are written.  JSON numbers cannot represent
+// these values, so by default they are written as NULL and a non-nil representation is written as a string.
+func (jsonw *JSONWriter) SetFloatSpecials(rendering typeinfo.FloatSpecialRendering) {
+	jsonw.floatSpecials = rendering
 }
 
 func (jsonw *JSONWriter) GetSchema() schema.Schema {
@@ -100,12 +107,22 @@ func (jsonw *JSONWriter) WriteRow(ctx context.Context, r row.Row) error {
 			}
 			val = types.String(*v)
 
+		case typeinfo.FloatTypeIdentifier:
+			if f, ok := val.(types.Float); ok {
+				if rendered, isSpecial := jsonw.floatSpecials.Render(float64(f)); isSpecial {
+					if rendered == nil {
+						return false, nil
+					}
+
+					val = types.String(*rendered)
+				}
+			}
+
 		case typeinfo.BitTypeIdentifier,
 			typeinfo.BoolTypeIdentifier,
 			typeinfo.VarStringTypeIdentifier,
 			typeinfo.UintTypeIdentifier,
-			typeinfo.IntTypeIdentifier,
-			typeinfo.FloatTypeIdentifier:
+			typeinfo.IntTypeIdentifier:
 			// use primitive type
 		}
 
@@ -159,6 +176,18 @@ func (jsonw *JSONWriter) Close(ctx context.Context) error {
 
 }
 
+// Abort abandons the output of a failed export.  Rows still buffered are discarded, the closing of the JSON document
+// isn't written, and the underlying writer is aborted if it is an iohelp.Aborter and closed otherwise.
+func (jsonw *JSONWriter) Abort(ctx context.Context) error {
+	if jsonw.closer == nil {
+		return errors.New("already closed")
+	}
+
+	closer := jsonw.closer
+	jsonw.closer = nil
+	return iohelp.AbortOrClose(closer)
+}
+
 func marshalToJson(valMap interface{}) ([]byte, error) {
 	var jsonBytes []byte
 	var err error
@@ -0,0 +1,83 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWriterFloatSpecials(t *testing.T) {
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(
+		schema.Column{Name: "id", Tag: 0, Kind: types.IntKind, IsPartOfPK: true, TypeInfo: typeinfo.Int64Type},
+		schema.Column{Name: "score", Tag: 1, Kind: types.FloatKind, TypeInfo: typeinfo.Float64Type},
+	))
+	require.NoError(t, err)
+
+	var rows []row.Row
+	for i, f := range []float64{1.5, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		r, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.Int(i), 1: types.Float(f)})
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+
+	tests := []struct {
+		name      string
+		rendering *typeinfo.FloatSpecialRendering
+		expected  string
+	}{
+		{
+			name:     "default writes null",
+			expected: `{"rows": [{"id":0,"score":1.5},{"id":1},{"id":2},{"id":3}]}`,
+		},
+		{
+			name:      "strings",
+			rendering: &typeinfo.DefaultFloatSpecialRendering,
+			expected:  `{"rows": [{"id":0,"score":1.5},{"id":1,"score":"NaN"},{"id":2,"score":"+Inf"},{"id":3,"score":"-Inf"}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			fs := filesys.EmptyInMemFS("/")
+			wr, err := OpenJSONWriter("file.json", fs, sch)
+			require.NoError(t, err)
+
+			if test.rendering != nil {
+				wr.SetFloatSpecials(*test.rendering)
+			}
+
+			for _, r := range rows {
+				require.NoError(t, wr.WriteRow(ctx, r))
+			}
+			require.NoError(t, wr.Close(ctx))
+
+			data, err := fs.ReadFile("file.json")
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(data))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+// CSVEncoding is the character encoding a csv file is written in
+type CSVEncoding int
+
+const (
+	// UTF8Encoding writes UTF-8, which is the default
+	UTF8Encoding CSVEncoding = iota
+	// UTF16LEEncoding writes little endian UTF-16, which some spreadsheet tools expect
+	UTF16LEEncoding
+)
+
+// InvalidRunePolicy determines how a CSVWriter writes values which are not valid UTF-8
+type InvalidRunePolicy int
+
+const (
+	// WriteInvalidRunesUnchanged writes invalid byte sequences as they are when writing UTF-8.  As that isn't possible
+	// when transcoding, they are replaced by U+FFFD in other encodings.  This is the default.
+	WriteInvalidRunesUnchanged InvalidRunePolicy = iota
+	// ReplaceInvalidRunes writes U+FFFD in place of each invalid byte sequence
+	ReplaceInvalidRunes
+	// ErrorOnInvalidRunes fails the write with ErrInvalidUTF8
+	ErrorOnInvalidRunes
+)
+
+// ErrInvalidUTF8 is the error returned when writing a value which is not valid UTF-8 with the ErrorOnInvalidRunes
+// policy.  As output is buffered it is returned by the write which flushes the value, or at the latest by Close.
+var ErrInvalidUTF8 = errors.New("csv value is not valid UTF-8")
+
+const byteOrderMark = "\ufeff"
+
+// encodingWriteCloser writes to a WriteCloser through a transformer which encodes the output, flushing the transformer
+// before closing the underlying writer
+type encodingWriteCloser struct {
+	*transform.Writer
+	closer io.Closer
+}
+
+func (ewc encodingWriteCloser) Close() error {
+	err := ewc.Writer.Close()
+	errCl := ewc.closer.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return errCl
+}
+
+// Abort abandons the output without flushing the encoder, aborting the underlying writer if it is an iohelp.Aborter
+func (ewc encodingWriteCloser) Abort() error {
+	return iohelp.AbortOrClose(ewc.closer)
+}
+
+// newEncodingWriteCloser returns a WriteCloser which writes to wr in the encoding described by info, writing a byte
+// order mark first if info calls for one
+func newEncodingWriteCloser(wr io.WriteCloser, info *CSVFileInfo) (io.WriteCloser, error) {
+	var t transform.Transformer
+	switch info.Encoding {
+	case UTF8Encoding:
+		if info.InvalidRunes == ReplaceInvalidRunes {
+			t = unicode.UTF8.NewEncoder()
+		}
+	case UTF16LEEncoding:
+		t = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	default:
+		return nil, errors.New("unknown csv encoding")
+	}
+
+	if info.InvalidRunes == ErrorOnInvalidRunes {
+		if t == nil {
+			t = utf8Validator{}
+		} else {
+			t = transform.Chain(utf8Validator{}, t)
+		}
+	}
+
+	if t != nil {
+		wr = encodingWriteCloser{transform.NewWriter(wr, t), wr}
+	}
+
+	if info.WriteBOM {
+		_, err := io.WriteString(wr, byteOrderMark)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return wr, nil
+}
+
+// utf8Validator is a transform.Transformer which copies valid UTF-8 and fails with ErrInvalidUTF8 on anything else
+type utf8Validator struct {
+	transform.NopResetter
+}
+
+func (utf8Validator) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		r, size := utf8.DecodeRune(src[nSrc:])
+
+		if r == utf8.RuneError && size <= 1 {
+			if !atEOF && !utf8.FullRune(src[nSrc:]) {
+				// the rest of the rune is in the next write
+				err = transform.ErrShortSrc
+				break
+			}
+
+			err = ErrInvalidUTF8
+			break
+		}
+
+		if nDst+size > len(dst) {
+			err = transform.ErrShortDst
+			break
+		}
+
+		nDst += copy(dst[nDst:], src[nSrc:nSrc+size])
+		nSrc += size
+	}
+
+	return nDst, nSrc, err
+}
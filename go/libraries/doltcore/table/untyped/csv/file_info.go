@@ -14,6 +14,8 @@
 
 package csv
 
+import "github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+
 // CSVFileInfo describes a csv file
 type CSVFileInfo struct {
 	// Delim says which character is used as a field delimiter
@@ -24,11 +26,20 @@ type CSVFileInfo struct {
 	Columns []string
 	// EscapeQuotes says whether quotes should be escaped when parsing the csv
 	EscapeQuotes bool
+	// FloatSpecials, when not nil, determines how NaN and infinite values of float columns are written
+	FloatSpecials *typeinfo.FloatSpecialRendering
+	// WriteBOM says whether a byte order mark is written at the start of the csv, which some spreadsheet tools need in
+	// order to detect the encoding
+	WriteBOM bool
+	// Encoding is the character encoding the csv is written in
+	Encoding CSVEncoding
+	// InvalidRunes determines how values which are not valid UTF-8 are written
+	InvalidRunes InvalidRunePolicy
 }
 
 // NewCSVInfo creates a new CSVInfo struct with default values
 func NewCSVInfo() *CSVFileInfo {
-	return &CSVFileInfo{",", true, nil, true}
+	return &CSVFileInfo{Delim: ",", HasHeaderLine: true, EscapeQuotes: true}
 }
 
 // SetDelim sets the Delim member and returns the CSVFileInfo
@@ -54,3 +65,22 @@ func (info *CSVFileInfo) SetEscapeQuotes(escapeQuotes bool) *CSVFileInfo {
 	info.EscapeQuotes = escapeQuotes
 	return info
 }
+
+// SetFloatSpecials sets the FloatSpecials member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetFloatSpecials(rendering typeinfo.FloatSpecialRendering) *CSVFileInfo {
+	info.FloatSpecials = &rendering
+	return info
+}
+
+// SetWriteBOM sets the WriteBOM member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetWriteBOM(writeBOM bool) *CSVFileInfo {
+	info.WriteBOM = writeBOM
+	return info
+}
+
+// SetEncoding sets the Encoding and InvalidRunes members and returns the CSVFileInfo
+func (info *CSVFileInfo) SetEncoding(enc CSVEncoding, invalidRunes InvalidRunePolicy) *CSVFileInfo {
+	info.Encoding = enc
+	info.InvalidRunes = invalidRunes
+	return info
+}
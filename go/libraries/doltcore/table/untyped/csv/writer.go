@@ -28,6 +28,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -64,6 +65,11 @@ func OpenCSVWriter(path string, fs filesys.WritableFS, outSch schema.Schema, inf
 
 // NewCSVWriter writes rows to the given WriteCloser based on the Schema and CSVFileInfo provided
 func NewCSVWriter(wr io.WriteCloser, outSch schema.Schema, info *CSVFileInfo) (*CSVWriter, error) {
+	wr, err := newEncodingWriteCloser(wr, info)
+
+	if err != nil {
+		return nil, err
+	}
 
 	csvw := &CSVWriter{
 		wr:     bufio.NewWriterSize(wr, writeBufSize),
@@ -74,7 +80,7 @@ func NewCSVWriter(wr io.WriteCloser, outSch schema.Schema, info *CSVFileInfo) (*
 
 	if info.HasHeaderLine {
 		colNames := make([]*string, 0, outSch.GetAllCols().Size())
-		err := outSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		err = outSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 			nm := col.Name
 			colNames = append(colNames, &nm)
 			return false, nil
@@ -113,6 +119,13 @@ func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
 			return false, nil
 		}
 
+		if f, ok := val.(types.Float); ok && csvw.info.FloatSpecials != nil {
+			if rendered, isSpecial := csvw.info.FloatSpecials.Render(float64(f)); isSpecial {
+				colValStrs = append(colValStrs, rendered)
+				return false, nil
+			}
+		}
+
 		var v string
 		if val.Kind() == types.StringKind {
 			v = string(val.(types.String))
@@ -137,15 +150,32 @@ func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
 // Close should flush all writes, release resources being held
 func (csvw *CSVWriter) Close(ctx context.Context) error {
 	if csvw.wr != nil {
-		_ = csvw.wr.Flush()
+		errFl := csvw.wr.Flush()
 		errCl := csvw.closer.Close()
 		csvw.wr = nil
-		return errCl
+
+		if errCl != nil {
+			return errCl
+		}
+
+		return errFl
 	} else {
 		return errors.New("Already closed.")
 	}
 }
 
+// Abort abandons the output of a failed export.  Rows still buffered are discarded, and the underlying writer is aborted
+// if it is an iohelp.Aborter, such as an iohelp.GzipWriteCloser which would otherwise finalize a truncated file as a
+// valid one, and closed otherwise.
+func (csvw *CSVWriter) Abort(ctx context.Context) error {
+	if csvw.wr == nil {
+		return errors.New("Already closed.")
+	}
+
+	csvw.wr = nil
+	return iohelp.AbortOrClose(csvw.closer)
+}
+
 func (csvw *CSVWriter) write(record []*string) error {
 	return WriteCSVRow(csvw.wr, record, csvw.info.Delim, csvw.useCRLF)
 }
@@ -231,6 +261,55 @@ func WriteCSVRow(wr *bufio.Writer, record []*string, delim string, useCRLF bool)
 	return err
 }
 
+// streamBufSize is the number of bytes read at a time by WriteQuotedCSVField
+const streamBufSize = 32 * 1024
+
+// WriteQuotedCSVField writes a quoted field whose contents are read from rd, using the same escaping as WriteCSVRow.
+// The contents are streamed so that large values are never held in memory.  Because the contents aren't known ahead
+// of time the field is always quoted.  Delimiters and line endings around the field are left to the caller.
+func WriteQuotedCSVField(wr *bufio.Writer, rd io.Reader, useCRLF bool) error {
+	if err := wr.WriteByte('"'); err != nil {
+		return err
+	}
+
+	buf := make([]byte, streamBufSize)
+	for {
+		n, err := rd.Read(buf)
+
+		for _, b := range buf[:n] {
+			var wrErr error
+			switch b {
+			case '"':
+				_, wrErr = wr.WriteString(`""`)
+			case '\r':
+				if !useCRLF {
+					wrErr = wr.WriteByte('\r')
+				}
+			case '\n':
+				if useCRLF {
+					_, wrErr = wr.WriteString("\r\n")
+				} else {
+					wrErr = wr.WriteByte('\n')
+				}
+			default:
+				wrErr = wr.WriteByte(b)
+			}
+
+			if wrErr != nil {
+				return wrErr
+			}
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return wr.WriteByte('"')
+}
+
 // Below is the method comment from csv.Writer.fieldNeedsQuotes. It is relevant
 // to Dolt's quoting logic for NULLs and ""s, and for import/export compatibility
 //
@@ -15,13 +15,27 @@
 package csv
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/unicode"
+
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -134,3 +148,234 @@ Andy Anderson|27|
 		t.Errorf(`%s != %s`, results, expected)
 	}
 }
+
+func TestWriteQuotedCSVField(t *testing.T) {
+	tests := []struct {
+		in       string
+		useCRLF  bool
+		expected string
+	}{
+		{"", false, `""`},
+		{"plain", false, `"plain"`},
+		{`say "hi"`, false, `"say ""hi"""`},
+		{"a,b\nc", false, "\"a,b\nc\""},
+		{"a\r\nb", true, "\"a\r\nb\""},
+		{"a\nb", true, "\"a\r\nb\""},
+		{strings.Repeat(`"x`, streamBufSize), false, `"` + strings.Repeat(`""x`, streamBufSize) + `"`},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		wr := bufio.NewWriter(&buf)
+		err := WriteQuotedCSVField(wr, strings.NewReader(test.in), test.useCRLF)
+
+		if err == nil {
+			err = wr.Flush()
+		}
+
+		if err != nil {
+			t.Fatal("failed to write field", err)
+		}
+
+		if buf.String() != test.expected {
+			t.Errorf(`%q != %q`, buf.String(), test.expected)
+		}
+	}
+}
+
+func TestWriterFloatSpecials(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("score", 1, types.FloatKind, false),
+	))
+
+	var rows []row.Row
+	for i, f := range []float64{1.5, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		rows = append(rows, mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(i), 1: types.Float(f)})))
+	}
+
+	empty := ""
+	nan := "NaN"
+	tests := []struct {
+		name     string
+		info     *CSVFileInfo
+		expected string
+	}{
+		{
+			name:     "default",
+			info:     NewCSVInfo(),
+			expected: "id,score\n0,1.5\n1,NaN\n2,+Inf\n3,-Inf\n",
+		},
+		{
+			name:     "null",
+			info:     NewCSVInfo().SetFloatSpecials(typeinfo.NullFloatSpecialRendering),
+			expected: "id,score\n0,1.5\n1,\n2,\n3,\n",
+		},
+		{
+			name:     "custom",
+			info:     NewCSVInfo().SetFloatSpecials(typeinfo.FloatSpecialRendering{NaN: &nan, PosInf: &empty}),
+			expected: "id,score\n0,1.5\n1,NaN\n2,\"\"\n3,\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := filesys.NewInMemFS(nil, nil, root)
+			csvWr, err := OpenCSVWriter(path, fs, sch, test.info)
+
+			if err != nil {
+				t.Fatal("Could not open CSVWriter", err)
+			}
+
+			writeToCSV(csvWr, rows, t)
+
+			results, err := fs.ReadFile(path)
+			if string(results) != test.expected {
+				t.Errorf(`%q != %q`, results, test.expected)
+			}
+		})
+	}
+}
+
+func TestGzipWriter(t *testing.T) {
+	const expected = `name,age,title
+Bill Billerson,32,Senior Dufus
+Rob Robertson,25,Dufus
+John Johnson,21,""
+Andy Anderson,27,
+`
+
+	var compressed bytes.Buffer
+	gzw, err := iohelp.NewGzipWriteCloser(iohelp.NopWrCloser(&compressed), iohelp.WithGzipLevel(gzip.BestCompression), iohelp.WithGzipFlushInterval(32))
+	require.NoError(t, err)
+
+	csvWr, err := NewCSVWriter(gzw, outSch, NewCSVInfo())
+	require.NoError(t, err)
+
+	for _, r := range getSampleRows() {
+		require.NoError(t, csvWr.WriteRow(context.Background(), r))
+	}
+	require.NoError(t, csvWr.Close(context.Background()))
+
+	rd, err := gzip.NewReader(&compressed)
+	require.NoError(t, err)
+	results, err := ioutil.ReadAll(rd)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(results))
+}
+
+func TestGzipWriterAbort(t *testing.T) {
+	for _, info := range []*CSVFileInfo{NewCSVInfo(), NewCSVInfo().SetEncoding(UTF16LEEncoding, WriteInvalidRunesUnchanged)} {
+		var compressed bytes.Buffer
+		gzw, err := iohelp.NewGzipWriteCloser(iohelp.NopWrCloser(&compressed), iohelp.WithGzipFlushInterval(1))
+		require.NoError(t, err)
+
+		csvWr, err := NewCSVWriter(gzw, outSch, info)
+		require.NoError(t, err)
+
+		for _, r := range getSampleRows() {
+			require.NoError(t, csvWr.WriteRow(context.Background(), r))
+		}
+		require.NoError(t, csvWr.Abort(context.Background()))
+		assert.Error(t, csvWr.Close(context.Background()))
+
+		// an aborted export isn't a valid gzip file
+		rd, err := gzip.NewReader(&compressed)
+
+		if err == nil {
+			_, err = ioutil.ReadAll(rd)
+		}
+
+		assert.Error(t, err)
+	}
+}
+
+func writeEncodedCSV(t *testing.T, sch schema.Schema, info *CSVFileInfo, rows ...row.Row) ([]byte, error) {
+	var buf bytes.Buffer
+	csvWr, err := NewCSVWriter(iohelp.NopWrCloser(&buf), sch, info)
+	require.NoError(t, err)
+
+	for _, r := range rows {
+		err = csvWr.WriteRow(context.Background(), r)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = csvWr.Close(context.Background())
+	return buf.Bytes(), err
+}
+
+func TestWriterBOM(t *testing.T) {
+	results, err := writeEncodedCSV(t, outSch, NewCSVInfo().SetWriteBOM(true))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\xef\xbb\xbfname,age,title\n"), results)
+
+	results, err = writeEncodedCSV(t, outSch, NewCSVInfo().SetWriteBOM(true).SetEncoding(UTF16LEEncoding, WriteInvalidRunesUnchanged))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0xfe, 'n', 0}, results[:4])
+
+	// no BOM by default
+	results, err = writeEncodedCSV(t, outSch, NewCSVInfo())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("name,age,title\n"), results)
+}
+
+func TestWriterUTF16LE(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+	))
+
+	names := []string{"Zoë", "日本語", "emoji 🦆", "plain"}
+	var rows []row.Row
+	expected := "id,name\n"
+	for i, name := range names {
+		rows = append(rows, mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(i), 1: types.String(name)})))
+		expected += fmt.Sprintf("%d,%s\n", i, name)
+	}
+
+	results, err := writeEncodedCSV(t, sch, NewCSVInfo().SetWriteBOM(true).SetEncoding(UTF16LEEncoding, WriteInvalidRunesUnchanged), rows...)
+	require.NoError(t, err)
+
+	decoded, err := unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder().Bytes(results)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(decoded))
+}
+
+func TestWriterInvalidRunes(t *testing.T) {
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+	))
+	r := mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(1), 1: types.String("a\xffb")}))
+
+	results, err := writeEncodedCSV(t, sch, NewCSVInfo(), r)
+	require.NoError(t, err)
+	assert.Equal(t, "id,name\n1,a\xffb\n", string(results))
+
+	results, err = writeEncodedCSV(t, sch, NewCSVInfo().SetEncoding(UTF8Encoding, ReplaceInvalidRunes), r)
+	require.NoError(t, err)
+	assert.Equal(t, "id,name\n1,a�b\n", string(results))
+
+	results, err = writeEncodedCSV(t, sch, NewCSVInfo().SetEncoding(UTF16LEEncoding, WriteInvalidRunesUnchanged), r)
+	require.NoError(t, err)
+	decoded, err := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder().Bytes(results)
+	require.NoError(t, err)
+	assert.Equal(t, "id,name\n1,a�b\n", string(decoded))
+
+	for _, enc := range []CSVEncoding{UTF8Encoding, UTF16LEEncoding} {
+		_, err = writeEncodedCSV(t, sch, NewCSVInfo().SetEncoding(enc, ErrorOnInvalidRunes), r)
+		assert.True(t, errors.Is(err, ErrInvalidUTF8), "%v", err)
+	}
+
+	// valid multi byte runes pass the check
+	valid := mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(1), 1: types.String("日本語")}))
+	results, err = writeEncodedCSV(t, sch, NewCSVInfo().SetEncoding(UTF8Encoding, ErrorOnInvalidRunes), valid)
+	require.NoError(t, err)
+	assert.Equal(t, "id,name\n1,日本語\n", string(results))
+}
@@ -15,11 +15,217 @@
 package fwt
 
 import (
+	"bufio"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
+// HeaderRowProp is the property set on header rows re-emitted by an AutoSizingFWTTransformer configured with
+// WithHeaderInterval.  Writers use it to distinguish a repeated header from a data row.
+const HeaderRowProp = "fwt_header_row"
+
+// SummaryRowProp is the property set on the summary row emitted by an AutoSizingFWTTransformer configured with
+// WithSummaryRow.  Writers use it to draw a separating rule above the summary.
+const SummaryRowProp = "fwt_summary_row"
+
+// RawRowProp is the property set on rows emitted by an AutoSizingFWTTransformer configured with WithRawOutput.  Its
+// value is the separator rune writers should join the row's cells with instead of drawing a table.
+const RawRowProp = "fwt_raw_row"
+
+// GroupSeparatorProp is the property set on the rule rows emitted by an AutoSizingFWTTransformer configured with
+// WithGroupSeparators.  Writers draw a separating rule for these rows rather than writing their values, which are
+// those of the first row of the new group.
+const GroupSeparatorProp = "fwt_group_separator"
+
+// AutoSizingOption configures optional behavior of an AutoSizingFWTTransformer
+type AutoSizingOption func(asTr *AutoSizingFWTTransformer)
+
+// WithHeaderInterval causes the transformer to re-emit the header row before every n data rows after the first n.
+// The header must be the first row sent to the transformer, which is how callers inject it today.  Re-emitted headers
+// are formatted with the same final widths as the data and carry the HeaderRowProp property.  An n <= 0 disables
+// re-emission.
+func WithHeaderInterval(n int) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.headerInterval = n
+	}
+}
+
+// WithSummaryRow causes the transformer to emit the pre-computed summary row given, such as a line of totals, after all
+// other rows.  The summary is included when measuring column widths so a wide summary value widens its column rather
+// than breaking alignment.  The emitted summary carries the SummaryRowProp property.
+func WithSummaryRow(summary pipeline.RowWithProps) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.summary = &summary
+	}
+}
+
+// WithControlCharEscaping causes the transformer to expand tabs to tabWidth column tab stops and escape all other
+// control characters, including newlines, using the style given.  Values are rewritten before they are sampled so the
+// measured widths match the rendered output.  See ExpandTabsAndEscape.
+func WithControlCharEscaping(tabWidth int, style ControlCharStyle) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.escapeCtrlChars = true
+		asTr.tabWidth = tabWidth
+		asTr.ctrlCharStyle = style
+	}
+}
+
+// WithBoolRendering causes the transformer to print the values of each of the boolean columns with the tags given
+// using the strings of the BoolRendering.  Values are rewritten before they are sampled so the widths of the rendered
+// strings, such as 2 cell wide glyphs, are measured.  Columns without a rendering print their textual form.
+func WithBoolRendering(rendering BoolRendering, tags ...uint64) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		if asTr.boolRenderings == nil {
+			asTr.boolRenderings = make(map[uint64]BoolRendering, len(tags))
+		}
+
+		for _, tag := range tags {
+			asTr.boolRenderings[tag] = rendering
+		}
+	}
+}
+
+// WithFloatSpecialRendering causes the transformer to print the NaN and infinite values of each of the float columns
+// with the tags given using the representations of the FloatSpecialRendering.  Like boolean renderings, values are
+// rewritten before they are sampled so the rendered strings are measured.
+func WithFloatSpecialRendering(rendering typeinfo.FloatSpecialRendering, tags ...uint64) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		if asTr.floatSpecials == nil {
+			asTr.floatSpecials = make(map[uint64]typeinfo.FloatSpecialRendering, len(tags))
+		}
+
+		for _, tag := range tags {
+			asTr.floatSpecials[tag] = rendering
+		}
+	}
+}
+
+// WithFloatPrecision causes the transformer to print the values of float and double columns rounded to a number of
+// decimal places.  precisions maps the tags of columns to their number of places, and sch is the schema of the rows
+// before they were converted to strings, which is used to find the float columns.  Tags of columns of other types, such
+// as integers and decimals with a declared scale, are ignored, as are negative precisions.  Values are rewritten before
+// they are sampled so the widths of the rounded values are measured.  Columns without a precision print in full.
+func WithFloatPrecision(sch schema.Schema, precisions map[uint64]int) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		for tag, places := range precisions {
+			col, ok := sch.GetAllCols().GetByTag(tag)
+
+			if !ok || places < 0 || col.TypeInfo.GetTypeIdentifier() != typeinfo.FloatTypeIdentifier {
+				continue
+			}
+
+			if asTr.floatPrecisions == nil {
+				asTr.floatPrecisions = make(map[uint64]int, len(precisions))
+			}
+
+			asTr.floatPrecisions[tag] = places
+		}
+	}
+}
+
+// WithGroupSeparators causes the transformer to emit a rule row carrying the GroupSeparatorProp property before each
+// row whose value in the column with the tag given differs from the value in the row before it, so that the groups of
+// a report are set apart.  The rows are assumed to be sorted on the column.  Values are compared after they are
+// rewritten, and NULLs form a group of their own.  As with WithHeaderInterval the first row is taken to be the header,
+// so no rule separates it from the first group, and no rule is emitted directly after a re-emitted header or before
+// the summary row.  Rule rows are not emitted in raw output.
+func WithGroupSeparators(tag uint64) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.groupBy = true
+		asTr.groupTag = tag
+	}
+}
+
+// WithFillRune causes the transformer to pad the values of the columns with the tags given using fill rather than
+// spaces, for example '.' to draw dot leaders.  When no tags are given every column is padded with fill.  The width of
+// the fill rune is accounted for so columns stay aligned when it is two cells wide.
+func WithFillRune(fill rune, tags ...uint64) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		if len(tags) == 0 {
+			asTr.defaultFillRune = fill
+			return
+		}
+
+		if asTr.fillRunes == nil {
+			asTr.fillRunes = make(map[uint64]rune, len(tags))
+		}
+
+		for _, tag := range tags {
+			asTr.fillRunes[tag] = fill
+		}
+	}
+}
+
+// WithForcedWidths pins the width of each column in widths to the given number of characters regardless of the
+// sampled values.  Values longer than a forced width are handled using the transformer's TooLongBehavior.  Columns
+// without a forced width are sized by sampling.
+func WithForcedWidths(widths map[uint64]int) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.forcedWidths = widths
+	}
+}
+
+// WithMaxWidth caps the width of the columns with the tags given at max characters, however wide their sampled values
+// are, so that a column is never rendered wider than max even when there is room for it.  Values longer than max are
+// handled using the transformer's TooLongBehavior, while columns narrower than max and columns without a cap are sized
+// by sampling.  When no tags are given max caps every column without a cap of its own.  Forced widths set with
+// WithForcedWidths are not capped.
+func WithMaxWidth(max int, tags ...uint64) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		if len(tags) == 0 {
+			asTr.defaultMaxWidth = max
+			return
+		}
+
+		if asTr.maxWidths == nil {
+			asTr.maxWidths = make(map[uint64]int, len(tags))
+		}
+
+		for _, tag := range tags {
+			asTr.maxWidths[tag] = max
+		}
+	}
+}
+
+// WithEarlyFlush causes the transformer to stop sampling once the sampled column widths have not changed for k
+// consecutive rows.  The buffered rows are flushed immediately and the remaining rows are streamed using the widths
+// sampled so far, so output begins sooner when the widths settle early.  Later rows that are wider than the sampled
+// widths are handled using the transformer's TooLongBehavior.  A k <= 0 disables early flushing, which is the default.
+func WithEarlyFlush(k int) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.stableRowsToFlush = k
+	}
+}
+
+// WithRawOutput causes the transformer to emit rows for machine consumption rather than for display.  Rows are not
+// buffered, sampled, or padded.  Each cell is escaped as a CSV field delimited by sep, so cells containing sep, a
+// double quote, or a line break are quoted, and each row carries the RawRowProp property so writers join the cells
+// with sep.  Values are still rewritten by the rendering and escaping options, but header re-emission is ignored.
+func WithRawOutput(sep rune) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.raw = true
+		asTr.rawSep = sep
+	}
+}
+
+// WithRawOutputWhenNotTerminal applies WithRawOutput if the file descriptor given, typically that of stdout, is not a
+// terminal, so piped output can be parsed while interactive output is padded into columns.
+func WithRawOutputWhenNotTerminal(fd uintptr, sep rune) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		if !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd) {
+			WithRawOutput(sep)(asTr)
+		}
+	}
+}
+
 // AutoSizingFWTTransformer samples rows to automatically determine maximum column widths to provide to FWTTransformer.
 type AutoSizingFWTTransformer struct {
 	// The number of rows to sample to determine column widths
@@ -36,10 +242,66 @@ type AutoSizingFWTTransformer struct {
 	tooLngBhv TooLongBehavior
 	// The underlying fixed width transformer being assembled by row sampling.
 	fwtTr *FWTTransformer
+	// The number of data rows between re-emitted headers.  0 disables re-emission.
+	headerInterval int
+	// The formatted header row, captured when headerInterval is set
+	header *pipeline.RowWithProps
+	// The number of data rows emitted
+	dataRows int
+	// An optional summary row emitted after all other rows
+	summary *pipeline.RowWithProps
+	// When true tabs are expanded and control characters escaped before rows are sampled and formatted
+	escapeCtrlChars bool
+	// The distance between tab stops used when expanding tabs
+	tabWidth int
+	// The style used to escape control characters
+	ctrlCharStyle ControlCharStyle
+	// A map of column tag to the rendering used for the values of that boolean column
+	boolRenderings map[uint64]BoolRendering
+	// A map of column tag to the rendering used for the NaN and infinite values of that float column
+	floatSpecials map[uint64]typeinfo.FloatSpecialRendering
+	// A map of column tag to the number of decimal places the values of that float column are rounded to
+	floatPrecisions map[uint64]int
+	// A map of column tag to the layout the values of that temporal column are printed with
+	timeLayouts map[uint64]timeLayout
+	// A map of column tag to a width which takes precedence over the sampled width
+	forcedWidths map[uint64]int
+	// The width sampled widths are capped at for columns without an entry in maxWidths.  0 leaves them uncapped.
+	defaultMaxWidth int
+	// A map of column tag to the width the sampled width of that column is capped at
+	maxWidths map[uint64]int
+	// The rune used to pad columns without an entry in fillRunes.  0 pads with spaces.
+	defaultFillRune rune
+	// A map of column tag to the rune used to pad that column
+	fillRunes map[uint64]rune
+	// The number of consecutive rows without a width change after which sampling stops.  0 disables early flushing.
+	stableRowsToFlush int
+	// The number of consecutive sampled rows which haven't changed a width
+	stableRows int
+	// When true a rule row is emitted between rows with different values in the column with tag groupTag
+	groupBy  bool
+	groupTag uint64
+	// The number of rows, including the header, compared by value in groupTag
+	groupedRows int
+	// The groupTag value of the last row compared
+	lastGroupVal types.Value
+	// A map of column tag to the role of that column, which sets defaults for its alignment, rounding, and the handling
+	// of its values which are too long
+	roles map[uint64]ColumnRole
+	// A map of column tag to an alignment which takes precedence over the alignment of the column's role
+	alignments map[uint64]Alignment
+	// When true rows are emitted unpadded and escaped for parsing rather than formatted for display
+	raw bool
+	// The separator cells are delimited by when raw is true
+	rawSep rune
+	// The case the text of the header row is printed in
+	headerCase HeaderCase
+	// True once the first row, which is taken to be the header, has been handled
+	headerHandled bool
 }
 
-func NewAutoSizingFWTTransformer(sch schema.Schema, tooLngBhv TooLongBehavior, numSamples int) *AutoSizingFWTTransformer {
-	return &AutoSizingFWTTransformer{
+func NewAutoSizingFWTTransformer(sch schema.Schema, tooLngBhv TooLongBehavior, numSamples int, opts ...AutoSizingOption) *AutoSizingFWTTransformer {
+	asTr := &AutoSizingFWTTransformer{
 		numSamples:  numSamples,
 		printWidths: make(map[uint64]int, sch.GetAllCols().Size()),
 		maxRunes:    make(map[uint64]int, sch.GetAllCols().Size()),
@@ -47,6 +309,16 @@ func NewAutoSizingFWTTransformer(sch schema.Schema, tooLngBhv TooLongBehavior, n
 		sch:         sch,
 		tooLngBhv:   tooLngBhv,
 	}
+
+	for _, opt := range opts {
+		opt(asTr)
+	}
+
+	if asTr.raw {
+		asTr.rowBuffer = nil
+	}
+
+	return asTr
 }
 
 func (asTr *AutoSizingFWTTransformer) TransformToFWT(inChan <-chan pipeline.RowWithProps, outChan chan<- pipeline.RowWithProps, badRowChan chan<- *pipeline.TransformRowFailure, stopChan <-chan struct{}) {
@@ -70,29 +342,215 @@ RowLoop:
 		}
 	}
 
-	asTr.flush(outChan, badRowChan, stopChan)
+	if !asTr.raw {
+		asTr.flush(outChan, badRowChan, stopChan)
+	}
+
+	if asTr.summary != nil {
+		select {
+		case <-stopChan:
+			return
+		default:
+		}
+
+		summary := pipeline.RowWithProps{Row: asTr.summary.Row, Props: asTr.summary.Props.Set(map[string]interface{}{SummaryRowProp: true})}
+
+		if asTr.raw {
+			asTr.handleRow(summary, outChan, badRowChan, stopChan)
+		} else {
+			asTr.processRow(summary, outChan, badRowChan, stopChan)
+		}
+	}
+}
+
+// measureRow updates the maximum print widths and rune counts of each column with the values of the row given,
+// returning true if any of them grew
+func (asTr *AutoSizingFWTTransformer) measureRow(r pipeline.RowWithProps) (bool, error) {
+	widened := false
+	_, err := r.Row.IterSchema(asTr.sch, func(tag uint64, val types.Value) (stop bool, err error) {
+		if !types.IsNull(val) {
+			strVal := val.(types.String)
+			printWidth := StringWidth(string(strVal))
+			numRunes := len([]rune(string(strVal)))
+
+			if printWidth > asTr.printWidths[tag] {
+				asTr.printWidths[tag] = printWidth
+				widened = true
+			}
+
+			if numRunes > asTr.maxRunes[tag] {
+				asTr.maxRunes[tag] = numRunes
+				widened = true
+			}
+		}
+		return false, nil
+	})
+
+	return widened, err
+}
+
+// rewritesRows returns true if values need to be rewritten before they are sampled and formatted
+func (asTr *AutoSizingFWTTransformer) rewritesRows() bool {
+	return asTr.escapeCtrlChars || len(asTr.boolRenderings) > 0 || len(asTr.floatSpecials) > 0 || len(asTr.floatPrecisions) > 0 ||
+		len(asTr.timeLayouts) > 0 || len(asTr.roles) > 0
+}
+
+// rewriteRow returns the row given with the values of boolean columns and the special values of float columns rendered,
+// the other values of float columns rounded, the values of temporal columns formatted, and with tabs expanded and
+// control characters escaped in each value as configured.
+func (asTr *AutoSizingFWTTransformer) rewriteRow(r pipeline.RowWithProps) (pipeline.RowWithProps, error) {
+	taggedVals := make(row.TaggedValues)
+	changed := false
+	_, err := r.Row.IterSchema(asTr.sch, func(tag uint64, val types.Value) (stop bool, err error) {
+		if rendering, ok := asTr.boolRenderings[tag]; ok {
+			rendered := rendering.render(val)
+			changed = changed || rendered != val
+			val = rendered
+		}
+
+		if rendering, ok := asTr.floatSpecials[tag]; ok {
+			rendered := renderFloatSpecial(rendering, val)
+			changed = changed || rendered != val
+			val = rendered
+		}
+
+		if places, ok := asTr.floatPrecisions[tag]; ok {
+			rendered := renderFloatPrecision(places, val)
+			changed = changed || rendered != val
+			val = rendered
+		} else if places, ok := asTr.roles[tag].decimalPlaces(); ok {
+			rendered := roundDecimalPlaces(places, val)
+			changed = changed || rendered != val
+			val = rendered
+		}
+
+		if layout, ok := asTr.timeLayouts[tag]; ok {
+			rendered := layout.render(val)
+			changed = changed || rendered != val
+			val = rendered
+		}
+
+		if !types.IsNull(val) {
+			if asTr.escapeCtrlChars {
+				str := string(val.(types.String))
+				escaped := ExpandTabsAndEscape(str, asTr.tabWidth, asTr.ctrlCharStyle)
+				changed = changed || escaped != str
+				val = types.String(escaped)
+			}
+
+			taggedVals[tag] = val
+		}
+		return false, nil
+	})
+
+	if err != nil || !changed {
+		return r, err
+	}
+
+	rewritten, err := row.New(r.Row.Format(), asTr.sch, taggedVals)
+
+	if err != nil {
+		return r, err
+	}
+
+	return pipeline.RowWithProps{Row: rewritten, Props: r.Props}, nil
+}
+
+// rawRow returns the row given with each value escaped as a CSV field and the RawRowProp property set.  NULLs are
+// emitted as empty cells.
+func (asTr *AutoSizingFWTTransformer) rawRow(r pipeline.RowWithProps) (pipeline.RowWithProps, error) {
+	taggedVals := make(row.TaggedValues)
+	_, err := r.Row.IterSchema(asTr.sch, func(tag uint64, val types.Value) (stop bool, err error) {
+		var str string
+		if !types.IsNull(val) {
+			str = string(val.(types.String))
+		}
+
+		escaped, err := csvEscape(str, asTr.rawSep)
+
+		if err != nil {
+			return true, err
+		}
+
+		taggedVals[tag] = types.String(escaped)
+		return false, nil
+	})
+
+	if err != nil {
+		return r, err
+	}
+
+	escaped, err := row.New(r.Row.Format(), asTr.sch, taggedVals)
+
+	if err != nil {
+		return r, err
+	}
+
+	return pipeline.RowWithProps{Row: escaped, Props: r.Props.Set(map[string]interface{}{RawRowProp: asTr.rawSep})}, nil
+}
+
+// csvEscape returns str quoted as a CSV field, escaped as the csv package escapes fields, if it contains sep, a double
+// quote, or a line break
+func csvEscape(str string, sep rune) (string, error) {
+	if !strings.ContainsRune(str, sep) && !strings.ContainsAny(str, "\"\r\n") {
+		return str, nil
+	}
+
+	var sb strings.Builder
+	wr := bufio.NewWriter(&sb)
+	err := csv.WriteQuotedCSVField(wr, strings.NewReader(str), false)
+
+	if err != nil {
+		return "", err
+	}
+
+	err = wr.Flush()
+
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
 }
 
 func (asTr *AutoSizingFWTTransformer) handleRow(r pipeline.RowWithProps, outChan chan<- pipeline.RowWithProps, badRowChan chan<- *pipeline.TransformRowFailure, stopChan <-chan struct{}) {
-	if asTr.rowBuffer == nil {
-		asTr.processRow(r, outChan, badRowChan)
-	} else if asTr.numSamples <= 0 || len(asTr.rowBuffer) < asTr.numSamples {
-		_, err := r.Row.IterSchema(asTr.sch, func(tag uint64, val types.Value) (stop bool, err error) {
-			if !types.IsNull(val) {
-				strVal := val.(types.String)
-				printWidth := StringWidth(string(strVal))
-				numRunes := len([]rune(string(strVal)))
-
-				if printWidth > asTr.printWidths[tag] {
-					asTr.printWidths[tag] = printWidth
-				}
+	if !asTr.headerHandled {
+		asTr.headerHandled = true
 
-				if numRunes > asTr.maxRunes[tag] {
-					asTr.maxRunes[tag] = numRunes
-				}
+		if _, isSummary := r.Props.Get(SummaryRowProp); !isSummary && asTr.headerCase != AsIsHeaderCase {
+			var err error
+			r, err = asTr.caseHeader(r)
+
+			if err != nil {
+				badRowChan <- &pipeline.TransformRowFailure{Row: r.Row, TransformName: "fwt", Details: err.Error()}
+				return
 			}
-			return false, nil
-		})
+		}
+	}
+
+	if asTr.rewritesRows() {
+		var err error
+		r, err = asTr.rewriteRow(r)
+
+		if err != nil {
+			badRowChan <- &pipeline.TransformRowFailure{Row: r.Row, TransformName: "fwt", Details: err.Error()}
+			return
+		}
+	}
+
+	if asTr.raw {
+		raw, err := asTr.rawRow(r)
+
+		if err != nil {
+			badRowChan <- &pipeline.TransformRowFailure{Row: r.Row, TransformName: "fwt", Details: err.Error()}
+			return
+		}
+
+		sendRow(outChan, stopChan, raw)
+	} else if asTr.rowBuffer == nil {
+		asTr.processRow(r, outChan, badRowChan, stopChan)
+	} else if asTr.numSamples <= 0 || len(asTr.rowBuffer) < asTr.numSamples {
+		widened, err := asTr.measureRow(r)
 
 		if err != nil {
 			badRowChan <- &pipeline.TransformRowFailure{Row: r.Row, TransformName: "fwt", Details: err.Error()}
@@ -100,26 +558,104 @@ func (asTr *AutoSizingFWTTransformer) handleRow(r pipeline.RowWithProps, outChan
 		}
 
 		asTr.rowBuffer = append(asTr.rowBuffer, r)
+
+		if asTr.stableRowsToFlush > 0 {
+			if widened {
+				asTr.stableRows = 0
+			} else {
+				asTr.stableRows++
+			}
+
+			if asTr.stableRows >= asTr.stableRowsToFlush {
+				asTr.flush(outChan, badRowChan, stopChan)
+			}
+		}
 	} else {
+		// the buffer is full so this row is not sampled, but it must still be output after the buffered rows
 		asTr.flush(outChan, badRowChan, stopChan)
+		asTr.processRow(r, outChan, badRowChan, stopChan)
+	}
+}
+
+// capWidths reduces the sampled widths of the columns with a maximum width to that width
+func (asTr *AutoSizingFWTTransformer) capWidths() {
+	for _, tag := range asTr.sch.GetAllCols().Tags {
+		max, ok := asTr.maxWidths[tag]
+
+		if !ok {
+			max = asTr.defaultMaxWidth
+		}
+
+		if max <= 0 {
+			continue
+		}
+
+		if asTr.printWidths[tag] > max {
+			asTr.printWidths[tag] = max
+		}
+
+		if asTr.maxRunes[tag] > max {
+			asTr.maxRunes[tag] = max
+		}
 	}
 }
 
 func (asTr *AutoSizingFWTTransformer) flush(outChan chan<- pipeline.RowWithProps, badRowChan chan<- *pipeline.TransformRowFailure, stopChan <-chan struct{}) {
 	if asTr.fwtTr == nil {
+		if asTr.summary != nil {
+			var err error
+			if asTr.rewritesRows() {
+				var rewritten pipeline.RowWithProps
+				rewritten, err = asTr.rewriteRow(*asTr.summary)
+				asTr.summary = &rewritten
+			}
+
+			if err == nil {
+				_, err = asTr.measureRow(*asTr.summary)
+			}
+
+			if err != nil {
+				badRowChan <- &pipeline.TransformRowFailure{Row: asTr.summary.Row, TransformName: "fwt", Details: err.Error()}
+				asTr.summary = nil
+			}
+		}
+
+		if asTr.defaultMaxWidth > 0 || len(asTr.maxWidths) > 0 {
+			asTr.capWidths()
+		}
+
+		for tag, width := range asTr.forcedWidths {
+			asTr.printWidths[tag] = width
+			asTr.maxRunes[tag] = width
+		}
+
 		fwf := FixedWidthFormatterForSchema(asTr.sch, asTr.tooLngBhv, asTr.printWidths, asTr.maxRunes)
+
+		if asTr.defaultFillRune != 0 || len(asTr.fillRunes) > 0 {
+			fillRunes := make([]rune, 0, asTr.sch.GetAllCols().Size())
+			_ = asTr.sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+				fill, ok := asTr.fillRunes[tag]
+
+				if !ok {
+					fill = asTr.defaultFillRune
+				}
+
+				fillRunes = append(fillRunes, fill)
+				return false, nil
+			})
+
+			fwf = fwf.WithFillRunes(fillRunes)
+		}
+
+		if len(asTr.roles) > 0 || len(asTr.alignments) > 0 {
+			fwf = asTr.withRolesAndAlignments(fwf)
+		}
 		asTr.fwtTr = NewFWTTransformer(asTr.sch, fwf)
 	}
 
 	for i := 0; i < len(asTr.rowBuffer); i++ {
-		asTr.processRow(asTr.rowBuffer[i], outChan, badRowChan)
-
-		if i%100 == 0 {
-			select {
-			case <-stopChan:
-				return
-			default:
-			}
+		if !asTr.processRow(asTr.rowBuffer[i], outChan, badRowChan, stopChan) {
+			return
 		}
 	}
 
@@ -127,14 +663,21 @@ func (asTr *AutoSizingFWTTransformer) flush(outChan chan<- pipeline.RowWithProps
 	return
 }
 
-func (asTr *AutoSizingFWTTransformer) processRow(rowWithProps pipeline.RowWithProps, outChan chan<- pipeline.RowWithProps, badRowChan chan<- *pipeline.TransformRowFailure) {
+// processRow formats a row and sends it, along with any header due before it, to outChan.  It returns false if
+// stopChan is closed before the rows are sent, so a consumer which goes away without reading can't block it.
+func (asTr *AutoSizingFWTTransformer) processRow(rowWithProps pipeline.RowWithProps, outChan chan<- pipeline.RowWithProps, badRowChan chan<- *pipeline.TransformRowFailure, stopChan <-chan struct{}) bool {
 	rds, errMsg := asTr.fwtTr.Transform(rowWithProps.Row, rowWithProps.Props)
 
 	if errMsg != "" {
-		badRowChan <- &pipeline.TransformRowFailure{
+		select {
+		case badRowChan <- &pipeline.TransformRowFailure{
 			Row:           rowWithProps.Row,
 			TransformName: "Auto Sizing Fixed Width Transform",
 			Details:       errMsg,
+		}:
+			return true
+		case <-stopChan:
+			return false
 		}
 	} else if len(rds) == 1 {
 		propUpdates := rds[0].PropertyUpdates
@@ -145,6 +688,68 @@ func (asTr *AutoSizingFWTTransformer) processRow(rowWithProps pipeline.RowWithPr
 		}
 
 		outRow := pipeline.RowWithProps{Row: rds[0].RowData, Props: outProps}
-		outChan <- outRow
+		_, isSummary := outProps.Get(SummaryRowProp)
+		headerSent := false
+
+		if asTr.headerInterval > 0 && !isSummary {
+			if asTr.header == nil {
+				header := pipeline.RowWithProps{Row: outRow.Row, Props: outRow.Props.Set(map[string]interface{}{HeaderRowProp: true})}
+				asTr.header = &header
+
+				if asTr.groupBy {
+					asTr.groupChanged(rowWithProps)
+				}
+
+				return sendRow(outChan, stopChan, outRow)
+			}
+
+			if asTr.dataRows > 0 && asTr.dataRows%asTr.headerInterval == 0 {
+				if !sendRow(outChan, stopChan, *asTr.header) {
+					return false
+				}
+
+				headerSent = true
+			}
+
+			asTr.dataRows++
+		}
+
+		if asTr.groupBy && !isSummary && asTr.groupChanged(rowWithProps) && !headerSent {
+			rule := pipeline.RowWithProps{Row: outRow.Row, Props: outRow.Props.Set(map[string]interface{}{GroupSeparatorProp: true})}
+
+			if !sendRow(outChan, stopChan, rule) {
+				return false
+			}
+		}
+
+		return sendRow(outChan, stopChan, outRow)
+	}
+
+	return true
+}
+
+// groupChanged records the value of the grouping column of the row given and returns true if it differs from that of
+// the previous row.  The first two rows compared, the header and the first data row, never start a new group.
+func (asTr *AutoSizingFWTTransformer) groupChanged(r pipeline.RowWithProps) bool {
+	val, ok := r.Row.GetColVal(asTr.groupTag)
+
+	if !ok {
+		val = types.NullValue
+	}
+
+	prev := asTr.lastGroupVal
+	asTr.lastGroupVal = val
+	asTr.groupedRows++
+
+	return asTr.groupedRows > 2 && !prev.Equals(val)
+}
+
+// sendRow sends r to outChan unless stopChan is closed first, returning false if it was
+func sendRow(outChan chan<- pipeline.RowWithProps, stopChan <-chan struct{}, r pipeline.RowWithProps) bool {
+	select {
+	case outChan <- r:
+		return true
+	case <-stopChan:
+		return false
 	}
 }
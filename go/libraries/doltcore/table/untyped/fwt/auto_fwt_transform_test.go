@@ -15,12 +15,19 @@
 package fwt
 
 import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -92,6 +99,740 @@ func TestHandleRow(t *testing.T) {
 	}
 }
 
+func TestHeaderInterval(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "col1", "col2"),
+		testRow(t, "a", "1"),
+		testRow(t, "bb", "22"),
+		testRow(t, "ccc", "333"),
+		testRow(t, "dddddd", "4444"),
+		testRow(t, "e", "5"),
+	)
+
+	transformer := NewAutoSizingFWTTransformer(testSchema(), PrintAllWhenTooLong, 100, WithHeaderInterval(2))
+	inChan := make(chan pipeline.RowWithProps, len(inputRows))
+	outChan := make(chan pipeline.RowWithProps)
+	badRowChan := make(chan *pipeline.TransformRowFailure)
+	stopChan := make(chan struct{})
+
+	for _, r := range inputRows {
+		inChan <- r
+	}
+	close(inChan)
+
+	go func() {
+		transformer.TransformToFWT(inChan, outChan, badRowChan, stopChan)
+		close(outChan)
+	}()
+
+	var outputVals []string
+	var headerFlags []bool
+	for r := range outChan {
+		val, _ := r.Row.GetColVal(0)
+		outputVals = append(outputVals, string(val.(types.String)))
+		_, isHeader := r.Props.Get(HeaderRowProp)
+		headerFlags = append(headerFlags, isHeader)
+	}
+
+	assert.Equal(t, []string{"col1  ", "a     ", "bb    ", "col1  ", "ccc   ", "dddddd", "col1  ", "e     "}, outputVals)
+	assert.Equal(t, []bool{false, false, false, true, false, false, true, false}, headerFlags)
+}
+
+func TestSummaryRow(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "col1", "col2"),
+		testRow(t, "a", "1"),
+		testRow(t, "bb", "22"),
+	)
+
+	transformer := NewAutoSizingFWTTransformer(testSchema(), PrintAllWhenTooLong, 100, WithSummaryRow(testRow(t, "total", "1234567")))
+	inChan := make(chan pipeline.RowWithProps, len(inputRows))
+	outChan := make(chan pipeline.RowWithProps)
+	badRowChan := make(chan *pipeline.TransformRowFailure)
+	stopChan := make(chan struct{})
+
+	for _, r := range inputRows {
+		inChan <- r
+	}
+	close(inChan)
+
+	go func() {
+		transformer.TransformToFWT(inChan, outChan, badRowChan, stopChan)
+		close(outChan)
+	}()
+
+	var outputVals [][2]string
+	var summaryFlags []bool
+	for r := range outChan {
+		val1, _ := r.Row.GetColVal(0)
+		val2, _ := r.Row.GetColVal(1)
+		outputVals = append(outputVals, [2]string{string(val1.(types.String)), string(val2.(types.String))})
+		_, isSummary := r.Props.Get(SummaryRowProp)
+		summaryFlags = append(summaryFlags, isSummary)
+	}
+
+	expected := [][2]string{
+		{"col1 ", "col2   "},
+		{"a    ", "1      "},
+		{"bb   ", "22     "},
+		{"total", "1234567"},
+	}
+	assert.Equal(t, expected, outputVals)
+	assert.Equal(t, []bool{false, false, false, true}, summaryFlags)
+}
+
+func TestControlCharEscaping(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "col1", "col2"),
+		testRow(t, "a\tb", "line1\nline2"),
+		testRow(t, "c", "d"),
+	)
+
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithControlCharEscaping(4, HexEscapeControlChars))
+	inChan := make(chan pipeline.RowWithProps, len(inputRows))
+	outChan := make(chan pipeline.RowWithProps)
+	badRowChan := make(chan *pipeline.TransformRowFailure, len(inputRows))
+	stopChan := make(chan struct{})
+
+	for _, r := range inputRows {
+		inChan <- r
+	}
+	close(inChan)
+
+	go func() {
+		transformer.TransformToFWT(inChan, outChan, badRowChan, stopChan)
+		close(outChan)
+	}()
+
+	var outputVals [][2]string
+	for r := range outChan {
+		val1, _ := r.Row.GetColVal(0)
+		val2, _ := r.Row.GetColVal(1)
+		outputVals = append(outputVals, [2]string{string(val1.(types.String)), string(val2.(types.String))})
+	}
+
+	expected := [][2]string{
+		{"col1 ", "col2          "},
+		{"a   b", `line1\x0aline2`},
+		{"c    ", "d             "},
+	}
+	assert.Empty(t, badRowChan)
+	assert.Equal(t, expected, outputVals)
+}
+
+func TestBoolRendering(t *testing.T) {
+	nullRow := testRow(t, "d", "")
+	nullRowVal, err := nullRow.Row.SetColVal(1, nil, testSchema())
+	require.NoError(t, err)
+	nullRow.Row = nullRowVal
+
+	inputRows := rs(
+		testRow(t, "col1", "b"),
+		testRow(t, "a", "true"),
+		testRow(t, "b", "FALSE"),
+		testRow(t, "c", "1"),
+		nullRow,
+	)
+
+	rendering := CheckCrossBoolRendering
+	rendering.Null = "-"
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithBoolRendering(rendering, 1))
+	inChan := make(chan pipeline.RowWithProps, len(inputRows))
+	outChan := make(chan pipeline.RowWithProps)
+	badRowChan := make(chan *pipeline.TransformRowFailure, len(inputRows))
+	stopChan := make(chan struct{})
+
+	for _, r := range inputRows {
+		inChan <- r
+	}
+	close(inChan)
+
+	go func() {
+		transformer.TransformToFWT(inChan, outChan, badRowChan, stopChan)
+		close(outChan)
+	}()
+
+	var outputVals []string
+	for r := range outChan {
+		val, _ := r.Row.GetColVal(1)
+		outputVals = append(outputVals, string(val.(types.String)))
+	}
+
+	assert.Empty(t, badRowChan)
+	assert.Equal(t, []string{"b ", "✅", "❌", "✅", "- "}, outputVals)
+	for _, val := range outputVals {
+		assert.Equal(t, 2, StringWidth(val))
+	}
+}
+
+func TestFloatSpecialRendering(t *testing.T) {
+	nan, posInf, negInf := "not a number", "∞", "-∞"
+	rendering := typeinfo.FloatSpecialRendering{NaN: &nan, PosInf: &posInf, NegInf: &negInf}
+
+	inputRows := rs(
+		testRow(t, "a", "1.5"),
+		testRow(t, "b", "NaN"),
+		testRow(t, "c", "+Inf"),
+		testRow(t, "d", "-Inf"),
+		testRow(t, "e", "text"),
+	)
+
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithFloatSpecialRendering(rendering, 1))
+	assert.Equal(t, [][2]string{
+		{"a", "1.5         "},
+		{"b", "not a number"},
+		{"c", "∞           "},
+		{"d", "-∞          "},
+		{"e", "text        "},
+	}, stringVals(transformAll(t, transformer, inputRows)))
+}
+
+func TestFloatPrecision(t *testing.T) {
+	// the schema of the rows before they were converted to strings
+	typedSch := schema.UnkeyedSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("col1", 0, types.IntKind, false),
+		schema.NewColumn("col2", 1, types.FloatKind, false),
+	))
+
+	inputRows := rs(
+		testRow(t, "col1", "col2"),
+		testRow(t, "12", "3.14159"),
+		testRow(t, "7", "2.5"),
+		testRow(t, "1", "0.125"),
+		testRow(t, "2", "0.375"),
+		testRow(t, "3", "-0.001"),
+		testRow(t, "4", "NaN"),
+	)
+
+	// col1 is an integer column so its precision is ignored
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithFloatPrecision(typedSch, map[uint64]int{0: 2, 1: 2}))
+	assert.Equal(t, [][2]string{
+		{"col1", "col2"},
+		{"12  ", "3.14"},
+		{"7   ", "2.50"},
+		{"1   ", "0.12"},
+		{"2   ", "0.38"},
+		{"3   ", "0.00"},
+		{"4   ", "NaN "},
+	}, stringVals(transformAll(t, transformer, inputRows)))
+
+	inputRows = rs(
+		testRow(t, "a", "3.14159"),
+		testRow(t, "b", "0.03125"),
+		testRow(t, "c", "0.09375"),
+		testRow(t, "d", "1e3"),
+	)
+
+	transformer = NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithFloatPrecision(typedSch, map[uint64]int{1: 4}))
+	assert.Equal(t, [][2]string{
+		{"a", "3.1416   "},
+		{"b", "0.0312   "},
+		{"c", "0.0938   "},
+		{"d", "1000.0000"},
+	}, stringVals(transformAll(t, transformer, inputRows)))
+}
+
+func TestTimeLayouts(t *testing.T) {
+	mustCol := func(name string, tag uint64, ti typeinfo.TypeInfo) schema.Column {
+		col, err := schema.NewColumnWithTypeInfo(name, tag, ti, false, "", false, "")
+		require.NoError(t, err)
+		return col
+	}
+
+	// the schemas of the rows before they were converted to strings
+	dateTimeSch := schema.UnkeyedSchemaFromCols(schema.NewColCollection(
+		mustCol("col1", 0, typeinfo.DateType),
+		mustCol("col2", 1, typeinfo.TimeType),
+	))
+	yearIntSch := schema.UnkeyedSchemaFromCols(schema.NewColCollection(
+		mustCol("col1", 0, typeinfo.YearType),
+		mustCol("col2", 1, typeinfo.Int64Type),
+	))
+
+	_, err := NewTimeLayouts(dateTimeSch, map[uint64]string{0: "Jan 2, 2006", 1: "hh:mm"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `column 'col2'`)
+	assert.Contains(t, err.Error(), `layout "hh:mm" has no time elements`)
+	assert.Error(t, ValidateTimeLayout(""))
+
+	inputRows := rs(
+		testRow(t, "col1", "col2"),
+		testRow(t, "2021-03-14", "09:05:00"),
+		testRow(t, "2020-12-01", "23:59:59.5"),
+		testRow(t, "not a date", "-01:00:00"),
+		testRow(t, "2021-03-14", "25:00:00"),
+	)
+
+	layouts, err := NewTimeLayouts(dateTimeSch, map[uint64]string{0: "Jan 2, 2006", 1: "3:04:05.0 PM"})
+	require.NoError(t, err)
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithTimeLayouts(layouts))
+	assert.Equal(t, [][2]string{
+		{"col1        ", "col2         "},
+		{"Mar 14, 2021", "9:05:00.0 AM "},
+		{"Dec 1, 2020 ", "11:59:59.5 PM"},
+		{"not a date  ", "-01:00:00    "},
+		{"Mar 14, 2021", "25:00:00     "},
+	}, stringVals(transformAll(t, transformer, inputRows)))
+
+	// a layout wider than the default textual form widens the column
+	inputRows = rs(
+		testRow(t, "2021-03-14", "09:05:00"),
+		testRow(t, "2021-11-23", "19:08:07"),
+	)
+
+	layouts, err = NewTimeLayouts(dateTimeSch, map[uint64]string{0: "Monday, January 2, 2006"})
+	require.NoError(t, err)
+	transformer = NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithTimeLayouts(layouts))
+	assert.Equal(t, [][2]string{
+		{"Sunday, March 14, 2021    ", "09:05:00"},
+		{"Tuesday, November 23, 2021", "19:08:07"},
+	}, stringVals(transformAll(t, transformer, inputRows)))
+
+	// years are formatted as their first day and layouts of non temporal columns are ignored
+	inputRows = rs(
+		testRow(t, "2021", "12"),
+		testRow(t, "1999", "7"),
+	)
+
+	layouts, err = NewTimeLayouts(yearIntSch, map[uint64]string{0: "'06", 1: "garbage"})
+	require.NoError(t, err)
+	transformer = NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithTimeLayouts(layouts))
+	assert.Equal(t, [][2]string{
+		{"'21", "12"},
+		{"'99", "7 "},
+	}, stringVals(transformAll(t, transformer, inputRows)))
+}
+
+func TestGroupSeparators(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "category", "item"),
+		testRow(t, "fruit", "apple"),
+		testRow(t, "fruit", "pear"),
+		testRow(t, "nut", "almond"),
+		testRow(t, "veg", "kale"),
+		testRow(t, "veg", "leek"),
+		testRow(t, "veg", "okra"),
+	)
+
+	groupedVals := func(rows []pipeline.RowWithProps) []string {
+		var vals []string
+		for _, r := range rows {
+			val, _ := r.Row.GetColVal(1)
+			if _, isRule := r.Props.Get(GroupSeparatorProp); isRule {
+				vals = append(vals, "---")
+			} else {
+				vals = append(vals, strings.TrimSpace(string(val.(types.String))))
+			}
+		}
+
+		return vals
+	}
+
+	// sampling all rows, and sampling only some with the rest streamed
+	for _, numSamples := range []int{100, 3} {
+		transformer := NewAutoSizingFWTTransformer(testSchema(), PrintAllWhenTooLong, numSamples, WithGroupSeparators(0))
+		assert.Equal(t, []string{"item", "apple", "pear", "---", "almond", "---", "kale", "leek", "okra"}, groupedVals(transformAll(t, transformer, inputRows)))
+	}
+
+	// no rule follows a re-emitted header, and the summary row isn't grouped
+	transformer := NewAutoSizingFWTTransformer(testSchema(), PrintAllWhenTooLong, 100, WithGroupSeparators(0), WithHeaderInterval(2), WithSummaryRow(testRow(t, "total", "6")))
+	assert.Equal(t, []string{"item", "apple", "pear", "item", "almond", "---", "kale", "item", "leek", "okra", "6"}, groupedVals(transformAll(t, transformer, inputRows)))
+
+	// the rule row is formatted like a data row
+	transformer = NewAutoSizingFWTTransformer(testSchema(), PrintAllWhenTooLong, 100, WithGroupSeparators(0))
+	rows := transformAll(t, transformer, inputRows)
+	assert.Equal(t, [2]string{"nut     ", "almond"}, stringVals(rows[3:4])[0])
+}
+
+func TestFillRune(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "name", "value"),
+		testRow(t, "a", "1"),
+		testRow(t, "abcde", "22"),
+	)
+
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithFillRune('.', 0))
+	assert.Equal(t, [][2]string{
+		{"name.", "value"},
+		{"a....", "1    "},
+		{"abcde", "22   "},
+	}, stringVals(transformAll(t, transformer, inputRows)))
+
+	// a fill rune two cells wide fills what it can and pads the remaining cell with a space
+	transformer = NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithFillRune('＊'))
+	outputVals := stringVals(transformAll(t, transformer, inputRows))
+	assert.Equal(t, [][2]string{
+		{"name ", "value"},
+		{"a＊＊", "1＊＊"},
+		{"abcde", "22＊ "},
+	}, outputVals)
+
+	for _, vals := range outputVals {
+		assert.Equal(t, 5, StringWidth(vals[0]))
+		assert.Equal(t, 5, StringWidth(vals[1]))
+	}
+}
+
+func TestHeaderCase(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "first_name", "id"),
+		testRow(t, "ann", "first_name"),
+		testRow(t, "bob", "x"),
+	)
+
+	tests := []struct {
+		name     string
+		hc       HeaderCase
+		expected [][2]string
+	}{
+		{
+			name:     "as is",
+			hc:       AsIsHeaderCase,
+			expected: [][2]string{{"first_name", "id        "}, {"ann       ", "first_name"}, {"bob       ", "x         "}},
+		},
+		{
+			name:     "upper",
+			hc:       UpperHeaderCase,
+			expected: [][2]string{{"FIRST_NAME", "ID        "}, {"ann       ", "first_name"}, {"bob       ", "x         "}},
+		},
+		{
+			name:     "title",
+			hc:       TitleHeaderCase,
+			expected: [][2]string{{"First_Name", "Id        "}, {"ann       ", "first_name"}, {"bob       ", "x         "}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithHeaderCase(test.hc))
+			assert.Equal(t, test.expected, stringVals(transformAll(t, transformer, inputRows)))
+		})
+	}
+
+	// the first column is sized to its title cased name, which is wider than any of its values
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithHeaderCase(TitleHeaderCase), WithHeaderInterval(1))
+	outputVals := stringVals(transformAll(t, transformer, rs(
+		testRow(t, "total AMOUNT", "b"),
+		testRow(t, "1", "c"),
+		testRow(t, "22", "d"),
+	)))
+	assert.Equal(t, [][2]string{{"Total Amount", "B"}, {"1           ", "c"}, {"Total Amount", "B"}, {"22          ", "d"}}, outputVals)
+
+	for _, vals := range outputVals {
+		assert.Equal(t, StringWidth("Total Amount"), StringWidth(vals[0]))
+	}
+
+	assert.Equal(t, "Ünïcode_Ökay 2nd", titleCase("üNÏCODE_ökay 2ND"))
+}
+
+func TestForcedWidths(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "col1", "col2"),
+		testRow(t, "a", "1"),
+		testRow(t, "bbbbbbbb", "22"),
+	)
+
+	tests := []struct {
+		name      string
+		tooLngBhv TooLongBehavior
+		expected  [][2]string
+	}{
+		{
+			name:      "truncate",
+			tooLngBhv: TruncateWhenTooLong,
+			expected:  [][2]string{{"col1  ", "col2"}, {"a     ", "1   "}, {"bbbbbb", "22  "}},
+		},
+		{
+			name:      "hash fill",
+			tooLngBhv: HashFillWhenTooLong,
+			expected:  [][2]string{{"col1  ", "col2"}, {"a     ", "1   "}, {"######", "22  "}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transformer := NewAutoSizingFWTTransformer(testSchema(), test.tooLngBhv, 100, WithForcedWidths(map[uint64]int{0: 6}))
+			outputVals := stringVals(transformAll(t, transformer, inputRows))
+			assert.Equal(t, test.expected, outputVals)
+		})
+	}
+}
+
+func TestMaxWidth(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "col1", "col2"),
+		testRow(t, "a", "a much longer value"),
+		testRow(t, "bbbbbbbb", "22"),
+	)
+
+	tests := []struct {
+		name      string
+		tooLngBhv TooLongBehavior
+		opts      []AutoSizingOption
+		expected  [][2]string
+	}{
+		{
+			name:      "one column capped",
+			tooLngBhv: TruncateWhenTooLong,
+			opts:      []AutoSizingOption{WithMaxWidth(5, 0)},
+			expected:  [][2]string{{"col1 ", "col2               "}, {"a    ", "a much longer value"}, {"bbbbb", "22                 "}},
+		},
+		{
+			name:      "cap wider than values",
+			tooLngBhv: TruncateWhenTooLong,
+			opts:      []AutoSizingOption{WithMaxWidth(40, 0)},
+			expected:  [][2]string{{"col1    ", "col2               "}, {"a       ", "a much longer value"}, {"bbbbbbbb", "22                 "}},
+		},
+		{
+			name:      "default cap with override",
+			tooLngBhv: HashFillWhenTooLong,
+			opts:      []AutoSizingOption{WithMaxWidth(6), WithMaxWidth(10, 0)},
+			expected:  [][2]string{{"col1    ", "col2  "}, {"a       ", "######"}, {"bbbbbbbb", "22    "}},
+		},
+		{
+			name:      "forced widths are not capped",
+			tooLngBhv: TruncateWhenTooLong,
+			opts:      []AutoSizingOption{WithMaxWidth(3), WithForcedWidths(map[uint64]int{1: 6})},
+			expected:  [][2]string{{"col", "col2  "}, {"a  ", "a much"}, {"bbb", "22    "}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transformer := NewAutoSizingFWTTransformer(testSchema(), test.tooLngBhv, 100, test.opts...)
+			assert.Equal(t, test.expected, stringVals(transformAll(t, transformer, inputRows)))
+		})
+	}
+}
+
+func TestColumnRoles(t *testing.T) {
+	currencyRows := rs(
+		testRow(t, "name", "price"),
+		testRow(t, "a", "3.14159"),
+		testRow(t, "b", "-12.5"),
+		testRow(t, "c", "1000"),
+		testRow(t, "d", "n/a"),
+	)
+
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, WithColumnRoles(map[uint64]ColumnRole{1: CurrencyRole}))
+	assert.Equal(t, [][2]string{
+		{"name", "  price"},
+		{"a   ", "   3.14"},
+		{"b   ", " -12.50"},
+		{"c   ", "1000.00"},
+		{"d   ", "    n/a"},
+	}, stringVals(transformAll(t, transformer, currencyRows)))
+
+	// an explicit alignment overrides the role's regardless of the order of the options
+	for _, opts := range [][]AutoSizingOption{
+		{WithColumnRoles(map[uint64]ColumnRole{1: CurrencyRole}), WithAlignment(AlignLeft, 1)},
+		{WithAlignment(AlignLeft), WithColumnRoles(map[uint64]ColumnRole{1: CurrencyRole})},
+	} {
+		transformer = NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 100, opts...)
+		assert.Equal(t, [][2]string{
+			{"name", "price  "},
+			{"a   ", "3.14   "},
+			{"b   ", "-12.50 "},
+			{"c   ", "1000.00"},
+			{"d   ", "n/a    "},
+		}, stringVals(transformAll(t, transformer, currencyRows)))
+	}
+
+	idRows := rs(
+		testRow(t, "id", "note"),
+		testRow(t, "abc", "short"),
+		testRow(t, "user-000123", "a much longer note"),
+	)
+
+	roles := map[uint64]ColumnRole{0: IdentifierRole, 1: FreeTextRole}
+	transformer = NewAutoSizingFWTTransformer(testSchema(), PrintAllWhenTooLong, 100, WithColumnRoles(roles), WithForcedWidths(map[uint64]int{0: 6, 1: 8}))
+	assert.Equal(t, [][2]string{
+		{"id    ", "note    "},
+		{"abc   ", "short   "},
+		{"…00123", "a much …"},
+	}, stringVals(transformAll(t, transformer, idRows)))
+}
+
+func TestEarlyFlush(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "aaa", "b"),
+		testRow(t, "a", "bb"),
+		testRow(t, "aa", "b"),
+		testRow(t, "a", "b"),
+		testRow(t, "aa", "bb"),
+	)
+
+	transformer := NewAutoSizingFWTTransformer(testSchema(), HashFillWhenTooLong, 100, WithEarlyFlush(2))
+	outChan := make(chan pipeline.RowWithProps, len(inputRows))
+	badRowChan := make(chan *pipeline.TransformRowFailure, len(inputRows))
+	stopChan := make(chan struct{})
+
+	for _, r := range inputRows[:3] {
+		transformer.handleRow(r, outChan, badRowChan, stopChan)
+	}
+
+	assert.Empty(t, outChan, "flushed before the widths were stable")
+	assert.NotNil(t, transformer.rowBuffer)
+
+	transformer.handleRow(inputRows[3], outChan, badRowChan, stopChan)
+	assert.Nil(t, transformer.rowBuffer, "sampling should stop once the widths are unchanged for 2 rows")
+	assert.Len(t, outChan, 4)
+
+	transformer.handleRow(inputRows[4], outChan, badRowChan, stopChan)
+	transformer.flush(outChan, badRowChan, stopChan)
+	close(outChan)
+
+	var outputRows []pipeline.RowWithProps
+	for r := range outChan {
+		outputRows = append(outputRows, r)
+	}
+
+	assert.Empty(t, badRowChan)
+	assert.Equal(t, [][2]string{
+		{"aaa", "b "},
+		{"a  ", "bb"},
+		{"aa ", "b "},
+		{"a  ", "b "},
+		{"aa ", "bb"},
+	}, stringVals(outputRows))
+}
+
+func TestSampleLimit(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "a", "b"),
+		testRow(t, "a", "b"),
+		testRow(t, "a", "b"),
+		testRow(t, "aaaa", "b"),
+	)
+
+	// the row which arrives once the sample buffer is full is output after the buffered rows rather than dropped
+	transformer := NewAutoSizingFWTTransformer(testSchema(), PrintAllWhenTooLong, 2)
+	outputVals := stringVals(transformAll(t, transformer, inputRows))
+	assert.Equal(t, [][2]string{{"a", "b"}, {"a", "b"}, {"a", "b"}, {"aaaa", "b"}}, outputVals)
+}
+
+func TestRawOutput(t *testing.T) {
+	inputRows := rs(
+		testRow(t, "col1", "col2"),
+		testRow(t, "a", "1"),
+		testRow(t, "a much longer value", "22"),
+		testRow(t, "a,b", `say "hi"`),
+		testRow(t, "line\nbreak", ""),
+	)
+
+	transformer := NewAutoSizingFWTTransformer(testSchema(), HashFillWhenTooLong, 2, WithRawOutput(','), WithHeaderInterval(1), WithSummaryRow(testRow(t, "total", "23")))
+	outputRows := transformAll(t, transformer, inputRows)
+	assert.Equal(t, [][2]string{
+		{"col1", "col2"},
+		{"a", "1"},
+		{"a much longer value", "22"},
+		{`"a,b"`, `"say ""hi"""`},
+		{"\"line\nbreak\"", ""},
+		{"total", "23"},
+	}, stringVals(outputRows))
+
+	for _, r := range outputRows {
+		sep, ok := r.Props.Get(RawRowProp)
+		assert.True(t, ok)
+		assert.Equal(t, ',', sep)
+	}
+
+	_, isSummary := outputRows[len(outputRows)-1].Props.Get(SummaryRowProp)
+	assert.True(t, isSummary)
+
+	// a file is not a terminal
+	f, err := ioutil.TempFile("", "raw_output")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	transformer = NewAutoSizingFWTTransformer(testSchema(), HashFillWhenTooLong, 100, WithRawOutputWhenNotTerminal(f.Fd(), '\t'))
+	assert.Equal(t, [][2]string{
+		{"col1", "col2"},
+		{"a", "1"},
+		{"a much longer value", "22"},
+	}, stringVals(transformAll(t, transformer, inputRows[:3])))
+}
+
+func TestStopMidFlush(t *testing.T) {
+	var inputRows []pipeline.RowWithProps
+	for i := 0; i < 1000; i++ {
+		inputRows = append(inputRows, testRow(t, strconv.Itoa(i), "value"))
+	}
+
+	// a clean shutdown emits every row
+	transformer := NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 0)
+	assert.Len(t, transformAll(t, transformer, inputRows), len(inputRows))
+
+	inChan := make(chan pipeline.RowWithProps, len(inputRows))
+	outChan := make(chan pipeline.RowWithProps)
+	badRowChan := make(chan *pipeline.TransformRowFailure, len(inputRows))
+	stopChan := make(chan struct{})
+
+	for _, r := range inputRows {
+		inChan <- r
+	}
+	close(inChan)
+
+	done := make(chan struct{})
+	transformer = NewAutoSizingFWTTransformer(testSchema(), ErrorWhenTooLong, 0)
+	go func() {
+		transformer.TransformToFWT(inChan, outChan, badRowChan, stopChan)
+		close(done)
+	}()
+
+	// the consumer reads a few rows of the flush then goes away
+	for i := 0; i < 3; i++ {
+		r := <-outChan
+		val, _ := r.Row.GetColVal(0)
+		assert.Equal(t, strconv.Itoa(i), strings.TrimSpace(string(val.(types.String))))
+	}
+	close(stopChan)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("transformer blocked sending to a consumer which stopped")
+	}
+}
+
+func transformAll(t *testing.T, transformer *AutoSizingFWTTransformer, inputRows []pipeline.RowWithProps) []pipeline.RowWithProps {
+	inChan := make(chan pipeline.RowWithProps, len(inputRows))
+	outChan := make(chan pipeline.RowWithProps)
+	badRowChan := make(chan *pipeline.TransformRowFailure, len(inputRows)+1)
+	stopChan := make(chan struct{})
+
+	for _, r := range inputRows {
+		inChan <- r
+	}
+	close(inChan)
+
+	go func() {
+		transformer.TransformToFWT(inChan, outChan, badRowChan, stopChan)
+		close(outChan)
+	}()
+
+	var outputRows []pipeline.RowWithProps
+	for r := range outChan {
+		outputRows = append(outputRows, r)
+	}
+
+	assert.Empty(t, badRowChan)
+	return outputRows
+}
+
+// stringVals returns the values of the rows of testSchema given
+func stringVals(rows []pipeline.RowWithProps) [][2]string {
+	var vals [][2]string
+	for _, r := range rows {
+		val1, _ := r.Row.GetColVal(0)
+		val2, _ := r.Row.GetColVal(1)
+		vals = append(vals, [2]string{string(val1.(types.String)), string(val2.(types.String))})
+	}
+
+	return vals
+}
+
 func testSchema() schema.Schema {
 	col1 := schema.NewColumn("col1", 0, types.StringKind, false)
 	col2 := schema.NewColumn("col2", 1, types.StringKind, false)
@@ -0,0 +1,62 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"strings"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// BoolRendering holds the strings printed in place of the true, false and NULL values of a boolean column.  An empty
+// Null leaves NULL values untouched so that a later stage, such as a null printer, can handle them.
+type BoolRendering struct {
+	True  string
+	False string
+	Null  string
+}
+
+// CheckCrossBoolRendering renders true as a check mark and false as a cross
+var CheckCrossBoolRendering = BoolRendering{True: "✅", False: "❌"}
+
+// YesNoBoolRendering renders true as yes and false as no
+var YesNoBoolRendering = BoolRendering{True: "yes", False: "no"}
+
+// render returns the rendered form of a value of a boolean column.  The value is expected to be the textual form of a
+// boolean, either true / false or 1 / 0 in any case.  Any other value is returned unchanged.
+func (br BoolRendering) render(val types.Value) types.Value {
+	if types.IsNull(val) {
+		if br.Null != "" {
+			return types.String(br.Null)
+		}
+
+		return val
+	}
+
+	str, ok := val.(types.String)
+
+	if !ok {
+		return val
+	}
+
+	switch strings.ToLower(string(str)) {
+	case "true", "1":
+		return types.String(br.True)
+	case "false", "0":
+		return types.String(br.False)
+	default:
+		return val
+	}
+}
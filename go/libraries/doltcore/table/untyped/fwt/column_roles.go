@@ -0,0 +1,147 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ColumnRole is the meaning of the values of a column in a report.  A column's role picks defaults for how it is
+// aligned, how its values are rounded, and how values too long for it are cut, so report authors can state their
+// intent once rather than configure each behavior.
+type ColumnRole int
+
+const (
+	// FreeTextRole columns are aligned left and too long values are ellipsized at their end
+	FreeTextRole ColumnRole = iota
+	// IdentifierRole columns are aligned left and too long values are ellipsized at their start, keeping the end of
+	// identifiers which share a common prefix
+	IdentifierRole
+	// CurrencyRole columns are aligned right and their numeric values are rounded to 2 decimal places.  Too long
+	// values are hash filled as an amount is never shown cut short.
+	CurrencyRole
+	// PercentageRole columns are aligned right and their numeric values are rounded to 1 decimal place.  Too long
+	// values are hash filled.
+	PercentageRole
+)
+
+// alignment returns the default alignment of columns with the role
+func (role ColumnRole) alignment() Alignment {
+	switch role {
+	case CurrencyRole, PercentageRole:
+		return AlignRight
+	default:
+		return AlignLeft
+	}
+}
+
+// decimalPlaces returns the number of decimal places the numeric values of columns with the role are rounded to.
+// false is returned if values are not rounded.
+func (role ColumnRole) decimalPlaces() (int32, bool) {
+	switch role {
+	case CurrencyRole:
+		return 2, true
+	case PercentageRole:
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// tooLong returns the default handling of values which are too long for columns with the role
+func (role ColumnRole) tooLong() ColumnTooLong {
+	switch role {
+	case IdentifierRole:
+		return ColumnTooLong{Behavior: EllipsizeWhenTooLong, Ellipsis: EllipsisHead}
+	case CurrencyRole, PercentageRole:
+		return ColumnTooLong{Behavior: HashFillWhenTooLong}
+	default:
+		return ColumnTooLong{Behavior: EllipsizeWhenTooLong, Ellipsis: EllipsisTail}
+	}
+}
+
+// WithColumnRoles gives the columns with the tags in roles the defaults of their ColumnRole.  Options which configure
+// the same behavior explicitly, WithAlignment and WithFloatPrecision, take precedence over a role regardless of the
+// order options are given in, and columns without a role use the transformer's defaults.  Role rounding applies to any
+// value which is a decimal number, including those of decimal and integer columns, and leaves other values unchanged.
+// Values are rounded before they are sampled so the rounded widths are measured.
+func WithColumnRoles(roles map[uint64]ColumnRole) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.roles = roles
+	}
+}
+
+// WithAlignment aligns the columns with the tags given, overriding the alignment of their role.  When no tags are given
+// every column is aligned.
+func WithAlignment(align Alignment, tags ...uint64) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		alignTags := tags
+		if len(alignTags) == 0 {
+			alignTags = asTr.sch.GetAllCols().Tags
+		}
+
+		if asTr.alignments == nil {
+			asTr.alignments = make(map[uint64]Alignment, len(alignTags))
+		}
+
+		for _, tag := range alignTags {
+			asTr.alignments[tag] = align
+		}
+	}
+}
+
+// roundDecimalPlaces returns a value which is the text of a decimal number rounded half away from zero to places
+// decimal places.  Any other value is returned unchanged.
+func roundDecimalPlaces(places int32, val types.Value) types.Value {
+	str, ok := val.(types.String)
+
+	if !ok {
+		return val
+	}
+
+	d, err := decimal.NewFromString(string(str))
+
+	if err != nil {
+		return val
+	}
+
+	return types.String(d.StringFixed(places))
+}
+
+// withRolesAndAlignments returns a copy of the formatter given which aligns each column and handles its values which
+// are too long as set by WithAlignment and WithColumnRoles
+func (asTr *AutoSizingFWTTransformer) withRolesAndAlignments(fwf FixedWidthFormatter) FixedWidthFormatter {
+	allCols := asTr.sch.GetAllCols()
+	alignments := make([]Alignment, allCols.Size())
+	colTooLong := make(map[int]ColumnTooLong, len(asTr.roles))
+
+	for i, tag := range allCols.Tags {
+		role, hasRole := asTr.roles[tag]
+
+		if align, ok := asTr.alignments[tag]; ok {
+			alignments[i] = align
+		} else if hasRole {
+			alignments[i] = role.alignment()
+		}
+
+		if hasRole {
+			colTooLong[i] = role.tooLong()
+		}
+	}
+
+	return fwf.WithAlignments(alignments).WithColumnTooLong(colTooLong)
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ColumnWidths holds the widest value seen in each column of a table, keyed by column tag
+type ColumnWidths struct {
+	// PrintWidths is the number of cells needed to print the widest value of each column as measured by StringWidth
+	PrintWidths map[uint64]int
+	// ByteLengths is the length in bytes of the longest value of each column
+	ByteLengths map[uint64]int
+}
+
+// MeasureColumnWidths reads every row from rd and returns the maximum print width and byte length of the values of
+// each column in its schema.  Unlike the AutoSizingFWTTransformer, which sizes columns from a sample of rows, every row
+// is measured and no value is ever truncated, which makes this suitable for inferring column sizes for an import.
+// String values are measured as they are, and other values by their human readable string.  Columns with only NULL
+// values have a width of 0.  Reading stops at the first error, including bad rows.
+func MeasureColumnWidths(ctx context.Context, rd table.TableReader) (ColumnWidths, error) {
+	allCols := rd.GetSchema().GetAllCols()
+	widths := ColumnWidths{
+		PrintWidths: make(map[uint64]int, allCols.Size()),
+		ByteLengths: make(map[uint64]int, allCols.Size()),
+	}
+
+	for _, tag := range allCols.Tags {
+		widths.PrintWidths[tag] = 0
+		widths.ByteLengths[tag] = 0
+	}
+
+	for {
+		r, err := rd.ReadRow(ctx)
+
+		if err == io.EOF {
+			return widths, nil
+		} else if err != nil {
+			return ColumnWidths{}, err
+		}
+
+		_, err = r.IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+			if types.IsNull(val) {
+				return false, nil
+			}
+
+			var str string
+			if strVal, ok := val.(types.String); ok {
+				str = string(strVal)
+			} else {
+				str = val.HumanReadableString()
+			}
+
+			if printWidth := StringWidth(str); printWidth > widths.PrintWidths[tag] {
+				widths.PrintWidths[tag] = printWidth
+			}
+
+			if len(str) > widths.ByteLengths[tag] {
+				widths.ByteLengths[tag] = len(str)
+			}
+
+			return false, nil
+		})
+
+		if err != nil {
+			return ColumnWidths{}, err
+		}
+	}
+}
+
+// MergePrintWidths combines maps of column tag to print width, such as those sampled from separate chunks of a table,
+// into one holding the widest width of each tag in any of them.  Tags missing from some of the maps take the widest
+// width of the maps they are in.
+func MergePrintWidths(maps ...map[uint64]int) map[uint64]int {
+	return mergeMaxByTag(maps)
+}
+
+// MergeMaxRunes combines maps of column tag to maximum number of runes in the same way MergePrintWidths combines print
+// widths
+func MergeMaxRunes(maps ...map[uint64]int) map[uint64]int {
+	return mergeMaxByTag(maps)
+}
+
+// mergeMaxByTag returns a new map holding the largest value of each tag in the maps given
+func mergeMaxByTag(maps []map[uint64]int) map[uint64]int {
+	merged := make(map[uint64]int)
+	for _, m := range maps {
+		for tag, n := range m {
+			if curr, ok := merged[tag]; !ok || n > curr {
+				merged[tag] = n
+			}
+		}
+	}
+
+	return merged
+}
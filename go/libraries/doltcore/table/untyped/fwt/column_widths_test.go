@@ -0,0 +1,71 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestMeasureColumnWidths(t *testing.T) {
+	nullCol2, err := row.New(types.Format_7_18, testSchema(), row.TaggedValues{0: types.String("a")})
+	require.NoError(t, err)
+
+	rows := []row.Row{
+		testRow(t, "a", "short").Row,
+		// the widest value by print width isn't the longest in bytes
+		testRow(t, "日本語", "a much longer value").Row,
+		testRow(t, "abcdefgh", "").Row,
+		nullCol2,
+	}
+
+	imt := table.NewInMemTableWithData(testSchema(), rows)
+	widths, err := MeasureColumnWidths(context.Background(), table.NewInMemTableReader(imt))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[uint64]int{0: 8, 1: 19}, widths.PrintWidths)
+	assert.Equal(t, map[uint64]int{0: 9, 1: 19}, widths.ByteLengths)
+
+	// columns without any values have a width of 0
+	widths, err = MeasureColumnWidths(context.Background(), table.NewInMemTableReader(table.NewInMemTable(testSchema())))
+	require.NoError(t, err)
+	assert.Equal(t, map[uint64]int{0: 0, 1: 0}, widths.PrintWidths)
+	assert.Equal(t, map[uint64]int{0: 0, 1: 0}, widths.ByteLengths)
+}
+
+func TestMergePrintWidths(t *testing.T) {
+	partials := []map[uint64]int{
+		{0: 3, 1: 10},
+		{0: 8, 2: 0},
+		{1: 4, 3: 7},
+	}
+
+	expected := map[uint64]int{0: 8, 1: 10, 2: 0, 3: 7}
+	assert.Equal(t, expected, MergePrintWidths(partials...))
+	assert.Equal(t, expected, MergeMaxRunes(partials...))
+
+	// the maps merged are not modified
+	assert.Equal(t, map[uint64]int{0: 3, 1: 10}, partials[0])
+
+	assert.Equal(t, map[uint64]int{}, MergePrintWidths())
+	assert.Equal(t, map[uint64]int{0: 1}, MergePrintWidths(nil, map[uint64]int{0: 1}))
+}
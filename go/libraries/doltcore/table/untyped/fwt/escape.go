@@ -0,0 +1,81 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ControlCharStyle determines how control characters are escaped
+type ControlCharStyle int
+
+const (
+	// HexEscapeControlChars replaces control characters with a \xNN escape sequence
+	HexEscapeControlChars ControlCharStyle = iota
+	// PictureEscapeControlChars replaces control characters with the matching symbol from the Unicode Control
+	// Pictures block, such as ␊ for a newline
+	PictureEscapeControlChars
+)
+
+const (
+	controlPicturesStart = '␀'
+	deletePicture        = '␡'
+)
+
+func isControlChar(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+func escapeControlChar(sb *strings.Builder, r rune, style ControlCharStyle) {
+	switch style {
+	case PictureEscapeControlChars:
+		if r == 0x7f {
+			sb.WriteRune(deletePicture)
+		} else {
+			sb.WriteRune(controlPicturesStart + r)
+		}
+	default:
+		sb.WriteString(fmt.Sprintf("\\x%02x", r))
+	}
+}
+
+// ExpandTabsAndEscape returns the string given with each tab expanded to spaces up to the next multiple of tabWidth
+// and every other control character escaped using the style given.  When tabWidth is 0 or less tabs are escaped like
+// any other control character.  Strings without control characters are returned unchanged.
+func ExpandTabsAndEscape(str string, tabWidth int, style ControlCharStyle) string {
+	if strings.IndexFunc(str, isControlChar) == -1 {
+		return str
+	}
+
+	var sb strings.Builder
+	// the start of the text written since the last tab and the display width of everything before it
+	segStart, width := 0, 0
+	for _, r := range str {
+		if r == '\t' && tabWidth > 0 {
+			width += StringWidth(sb.String()[segStart:])
+			numSpaces := tabWidth - width%tabWidth
+			sb.WriteString(strings.Repeat(" ", numSpaces))
+			width += numSpaces
+			segStart = sb.Len()
+		} else if isControlChar(r) {
+			escapeControlChar(&sb, r, style)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
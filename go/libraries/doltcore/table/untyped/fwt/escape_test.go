@@ -0,0 +1,45 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandTabsAndEscape(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		tabWidth int
+		style    ControlCharStyle
+		expected string
+	}{
+		{"no control chars", "hello", 4, HexEscapeControlChars, "hello"},
+		{"leading tab", "\tx", 4, HexEscapeControlChars, "    x"},
+		{"tab stops", "a\tbcd\te", 4, HexEscapeControlChars, "a   bcd e"},
+		{"tab after wide chars", "世\tx", 4, HexEscapeControlChars, "世  x"},
+		{"tab escaped when width 0", "a\tb", 0, HexEscapeControlChars, `a\x09b`},
+		{"newline hex", "a\nb", 4, HexEscapeControlChars, `a\x0ab`},
+		{"newline picture", "a\nb\x7f", 4, PictureEscapeControlChars, "a␊b␡"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ExpandTabsAndEscape(test.str, test.tabWidth, test.style))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// renderFloatSpecial returns the rendered form of a value of a float column if it is NaN or infinite.  The value may be
+// a types.Float or its textual form.  Any other value is returned unchanged.
+func renderFloatSpecial(rendering typeinfo.FloatSpecialRendering, val types.Value) types.Value {
+	var f float64
+	switch typedVal := val.(type) {
+	case types.Float:
+		f = float64(typedVal)
+	case types.String:
+		var err error
+		f, err = strconv.ParseFloat(string(typedVal), 64)
+
+		if err != nil {
+			return val
+		}
+	default:
+		return val
+	}
+
+	rendered, ok := rendering.Render(f)
+
+	if !ok {
+		return val
+	} else if rendered == nil {
+		return types.NullValue
+	}
+
+	return types.String(*rendered)
+}
+
+// renderFloatPrecision returns the value of a float column rounded to places decimal places.  Ties are rounded to the
+// nearest even digit of the value's exact binary representation.  The value may be a types.Float or its textual form.
+// NaN, infinities, and any other value are returned unchanged.
+func renderFloatPrecision(places int, val types.Value) types.Value {
+	var f float64
+	switch typedVal := val.(type) {
+	case types.Float:
+		f = float64(typedVal)
+	case types.String:
+		var err error
+		f, err = strconv.ParseFloat(string(typedVal), 64)
+
+		if err != nil {
+			return val
+		}
+	default:
+		return val
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return val
+	}
+
+	rounded := strconv.FormatFloat(f, 'f', places, 64)
+
+	// values which round to zero are printed without a sign
+	if strings.HasPrefix(rounded, "-") && strings.Trim(rounded[1:], "0.") == "" {
+		rounded = rounded[1:]
+	}
+
+	return types.String(rounded)
+}
@@ -17,6 +17,10 @@ package fwt
 import (
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 
@@ -39,8 +43,41 @@ const (
 	// PrintAllWhenTooLong will print the entire column for every row.  When this happens results will not be valid
 	// fixed width text files
 	PrintAllWhenTooLong
+	// EllipsizeWhenTooLong will cut columns that are too long down to the column width replacing the removed text with
+	// an ellipsis.  Which part of the text is removed is set with WithEllipsisPosition and defaults to the end.
+	EllipsizeWhenTooLong
+)
+
+// EllipsisPosition determines which part of a column's text is replaced by an ellipsis when using EllipsizeWhenTooLong
+type EllipsisPosition int
+
+const (
+	// EllipsisTail keeps the start of the text: "abc…"
+	EllipsisTail EllipsisPosition = iota
+	// EllipsisHead keeps the end of the text: "…xyz"
+	EllipsisHead
+	// EllipsisMiddle keeps an equal amount of the start and end of the text: "ab…yz"
+	EllipsisMiddle
 )
 
+const ellipsis = "…"
+
+// Alignment determines which side of a column its values are placed against when they are narrower than the column
+type Alignment int
+
+const (
+	// AlignLeft places values against the left of the column, padding them on the right
+	AlignLeft Alignment = iota
+	// AlignRight places values against the right of the column, padding them on the left, as is usual for numbers
+	AlignRight
+)
+
+// ColumnTooLong overrides the TooLongBehavior and EllipsisPosition of a formatter for a single column
+type ColumnTooLong struct {
+	Behavior TooLongBehavior
+	Ellipsis EllipsisPosition
+}
+
 // ErrRowCountMismatch is returned when the number of columns does not match the expected count
 var ErrRowCountMismatch = errors.New("number of columns passed to formatter does not match expected count")
 
@@ -57,6 +94,14 @@ type FixedWidthFormatter struct {
 
 	runeBuff  [][]rune
 	tooLngBhv TooLongBehavior
+	// fillRunes holds the rune used to pad each column.  A nil slice, or a 0 for a column, pads with spaces.
+	fillRunes   []rune
+	ellipsisPos EllipsisPosition
+	// alignments holds the alignment of each column.  A nil slice aligns every column left.
+	alignments []Alignment
+	// colTooLong maps the indexes of columns to the handling of their values which are too long, overriding tooLngBhv
+	// and ellipsisPos
+	colTooLong map[int]ColumnTooLong
 }
 
 // NewFixedWidthFormatter returns a new fixed width formatter
@@ -93,6 +138,51 @@ func NewFixedWidthFormatter(tooLongBhv TooLongBehavior, printWidths []int, maxRu
 	}
 }
 
+// WithFillRunes returns a copy of the formatter which pads each column with the rune at the column's index in
+// fillRunes rather than with spaces.  Columns with a fill rune of 0 are padded with spaces.  A fill rune which is two
+// cells wide is repeated as many times as fits, and any remaining cell is padded with a space.
+func (fwf FixedWidthFormatter) WithFillRunes(fillRunes []rune) FixedWidthFormatter {
+	fwf.fillRunes = fillRunes
+	return fwf
+}
+
+// WithEllipsisPosition returns a copy of the formatter which, when its TooLongBehavior is EllipsizeWhenTooLong, removes
+// the part of each column given by pos
+func (fwf FixedWidthFormatter) WithEllipsisPosition(pos EllipsisPosition) FixedWidthFormatter {
+	fwf.ellipsisPos = pos
+	return fwf
+}
+
+// WithAlignments returns a copy of the formatter which aligns each column as given by the alignment at the column's
+// index in alignments.  Columns without an alignment are aligned left.
+func (fwf FixedWidthFormatter) WithAlignments(alignments []Alignment) FixedWidthFormatter {
+	fwf.alignments = alignments
+	return fwf
+}
+
+// WithColumnTooLong returns a copy of the formatter which handles the values that are too long for the columns with the
+// indexes in overrides as given for each, rather than with the formatter's TooLongBehavior and EllipsisPosition
+func (fwf FixedWidthFormatter) WithColumnTooLong(overrides map[int]ColumnTooLong) FixedWidthFormatter {
+	fwf.colTooLong = overrides
+	return fwf
+}
+
+func (fwf FixedWidthFormatter) alignment(colIdx int) Alignment {
+	if colIdx < len(fwf.alignments) {
+		return fwf.alignments[colIdx]
+	}
+
+	return AlignLeft
+}
+
+func (fwf FixedWidthFormatter) fillRune(colIdx int) rune {
+	if colIdx < len(fwf.fillRunes) && fwf.fillRunes[colIdx] != 0 {
+		return fwf.fillRunes[colIdx]
+	}
+
+	return ' '
+}
+
 // FixedWidthFormatterForSchema takes a schema and creates a FixedWidthFormatter based on the columns within that schema
 func FixedWidthFormatterForSchema(sch schema.Schema, tooLongBhv TooLongBehavior, tagToPrintWidth map[uint64]int, tagToMaxRunes map[uint64]int) FixedWidthFormatter {
 	allCols := sch.GetAllCols()
@@ -188,7 +278,12 @@ func (fwf FixedWidthFormatter) FormatColumn(colStr string, colIdx int) (string,
 	strWidth := StringWidth(colStr)
 
 	if strWidth > colWidth {
-		switch fwf.tooLngBhv {
+		tooLngBhv, ellipsisPos := fwf.tooLngBhv, fwf.ellipsisPos
+		if override, ok := fwf.colTooLong[colIdx]; ok {
+			tooLngBhv, ellipsisPos = override.Behavior, override.Ellipsis
+		}
+
+		switch tooLngBhv {
 		case ErrorWhenTooLong:
 			return "", fmt.Errorf("for column %d '%s' exceeds the maximum length of %d: %w", colIdx, colStr, colWidth, ErrColumnTooLong)
 		case TruncateWhenTooLong:
@@ -197,11 +292,21 @@ func (fwf FixedWidthFormatter) FormatColumn(colStr string, colIdx int) (string,
 			colStr = fwf.noFitStrs[colIdx]
 		case PrintAllWhenTooLong:
 			break
+		case EllipsizeWhenTooLong:
+			colStr = ellipsize(colStr, colWidth, ellipsisPos)
 		}
 	}
 
-	buf := fwf.runeBuff[colIdx]
 	strWidth = StringWidth(colStr)
+	if fwf.alignment(colIdx) == AlignRight && strWidth < colWidth {
+		return padWithFill("", colWidth-strWidth, fwf.fillRune(colIdx)) + colStr, nil
+	}
+
+	if fill := fwf.fillRune(colIdx); fill != ' ' && strWidth < colWidth {
+		return padWithFill(colStr, colWidth-strWidth, fill), nil
+	}
+
+	buf := fwf.runeBuff[colIdx]
 	if strWidth > colWidth {
 		buf = []rune(colStr)
 	} else {
@@ -210,7 +315,69 @@ func (fwf FixedWidthFormatter) FormatColumn(colStr string, colIdx int) (string,
 		for i := 0; n+i < len(buf); i++ {
 			buf[n+i] = ' '
 		}
+
+		// A column whose widest value contains wide characters needs more padding than its rune count provides
+		if paddedWidth := strWidth + len(buf) - n; paddedWidth < colWidth {
+			return string(buf) + strings.Repeat(" ", colWidth-paddedWidth), nil
+		}
 	}
 
 	return string(buf), nil
 }
+
+// ellipsize cuts str down to at most width cells, replacing the part of str given by pos with an ellipsis.  Whole
+// grapheme clusters are kept or removed so that combining characters and wide characters are never split.
+func ellipsize(str string, width int, pos EllipsisPosition) string {
+	var graphemes []string
+	var widths []int
+	g := uniseg.NewGraphemes(str)
+	for g.Next() {
+		graphemes = append(graphemes, g.Str())
+		widths = append(widths, StringWidth(g.Str()))
+	}
+
+	remaining := width - StringWidth(ellipsis)
+	if remaining < 0 {
+		return ""
+	}
+
+	// head and tail are the number of graphemes kept from the start and end of str
+	head, tail := 0, 0
+	headWidth, tailWidth := 0, 0
+	for head+tail < len(graphemes) {
+		fromHead := pos == EllipsisTail || (pos == EllipsisMiddle && headWidth <= tailWidth)
+
+		if fromHead {
+			w := widths[head]
+			if w > remaining {
+				break
+			}
+
+			head++
+			headWidth += w
+			remaining -= w
+		} else {
+			w := widths[len(graphemes)-1-tail]
+			if w > remaining {
+				break
+			}
+
+			tail++
+			tailWidth += w
+			remaining -= w
+		}
+	}
+
+	return strings.Join(graphemes[:head], "") + ellipsis + strings.Join(graphemes[len(graphemes)-tail:], "")
+}
+
+// padWithFill appends padWidth cells of padding to str using the fill rune given
+func padWithFill(str string, padWidth int, fill rune) string {
+	fillWidth := runewidth.RuneWidth(fill)
+
+	if fillWidth <= 0 {
+		fill, fillWidth = ' ', 1
+	}
+
+	return str + strings.Repeat(string(fill), padWidth/fillWidth) + strings.Repeat(" ", padWidth%fillWidth)
+}
@@ -13,3 +13,98 @@
 // limitations under the License.
 
 package fwt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEllipsize(t *testing.T) {
+	tests := []struct {
+		str      string
+		width    int
+		pos      EllipsisPosition
+		expected string
+	}{
+		{"abcdefgh", 4, EllipsisTail, "abc…"},
+		{"abcdefgh", 4, EllipsisHead, "…fgh"},
+		{"abcdefgh", 4, EllipsisMiddle, "ab…h"},
+		{"abcdefgh", 5, EllipsisMiddle, "ab…gh"},
+		{"abcdefgh", 7, EllipsisMiddle, "abc…fgh"},
+		{"abcdefgh", 2, EllipsisTail, "a…"},
+		{"abcdefgh", 2, EllipsisHead, "…h"},
+		{"abcdefgh", 2, EllipsisMiddle, "a…"},
+		{"abcdefgh", 1, EllipsisTail, "…"},
+		{"abcdefgh", 1, EllipsisHead, "…"},
+		{"abcdefgh", 1, EllipsisMiddle, "…"},
+		// combining characters stay with the character they modify
+		{"éééé", 3, EllipsisTail, "éé…"},
+		{"éééé", 3, EllipsisHead, "…éé"},
+		{"éééé", 3, EllipsisMiddle, "é…é"},
+		// wide characters which don't fit are left out rather than split
+		{"日本語テキスト", 6, EllipsisTail, "日本…"},
+		{"日本語テキスト", 6, EllipsisHead, "…スト"},
+		{"日本語テキスト", 6, EllipsisMiddle, "日…ト"},
+		{"日本語テキスト", 2, EllipsisMiddle, "…"},
+	}
+
+	for _, test := range tests {
+		actual := ellipsize(test.str, test.width, test.pos)
+		assert.Equal(t, test.expected, actual, "ellipsize(%q, %d, %d)", test.str, test.width, test.pos)
+		assert.True(t, StringWidth(actual) <= test.width)
+	}
+}
+
+func TestEllipsizeWhenTooLong(t *testing.T) {
+	fwf := NewFixedWidthFormatter(EllipsizeWhenTooLong, []int{6}, []int{6})
+
+	for _, test := range []struct {
+		pos      EllipsisPosition
+		expected string
+	}{
+		{EllipsisTail, "/usr/…"},
+		{EllipsisHead, "…ib/go"},
+		{EllipsisMiddle, "/us…go"},
+	} {
+		actual, err := fwf.WithEllipsisPosition(test.pos).FormatColumn("/usr/local/lib/go", 0)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, actual)
+	}
+
+	// short values are padded as usual
+	actual, err := fwf.WithEllipsisPosition(EllipsisMiddle).FormatColumn("ab", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "ab    ", actual)
+
+	// wide characters which are left out are made up for with padding
+	fwf = NewFixedWidthFormatter(EllipsizeWhenTooLong, []int{6}, []int{3})
+	actual, err = fwf.FormatColumn("日本語テキスト", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "日本… ", actual)
+	assert.Equal(t, 6, StringWidth(actual))
+}
+
+func TestAlignmentsAndColumnTooLong(t *testing.T) {
+	fwf := NewFixedWidthFormatter(ErrorWhenTooLong, []int{6, 6, 6}, []int{6, 6, 6}).
+		WithAlignments([]Alignment{AlignRight, AlignLeft}).
+		WithColumnTooLong(map[int]ColumnTooLong{1: {Behavior: EllipsizeWhenTooLong, Ellipsis: EllipsisHead}})
+
+	formatted, err := fwf.Format([]string{"12", "ab", "xyz"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"    12", "ab    ", "xyz   "}, formatted)
+
+	formatted, err = fwf.Format([]string{"日本", "abcdefgh", "x"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"  日本", "…defgh", "x     "}, formatted)
+
+	// columns without an override use the formatter's TooLongBehavior
+	_, err = fwf.Format([]string{"1234567", "ab", "x"})
+	assert.Error(t, err)
+
+	// right aligned columns are padded with their fill rune
+	actual, err := fwf.WithFillRunes([]rune{'.'}).FormatColumn("12", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "....12", actual)
+}
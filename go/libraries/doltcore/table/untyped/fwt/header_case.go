@@ -0,0 +1,104 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// HeaderCase is a transformation applied to the text of the header row regardless of the case of the column names
+type HeaderCase int
+
+const (
+	// AsIsHeaderCase prints column names as they are stored
+	AsIsHeaderCase HeaderCase = iota
+	// UpperHeaderCase prints column names in upper case
+	UpperHeaderCase
+	// LowerHeaderCase prints column names in lower case
+	LowerHeaderCase
+	// TitleHeaderCase prints column names with the first letter of each word in upper case and the rest in lower case.
+	// Words are separated by any character which is not a letter or a digit, such as a space or an underscore, which is
+	// kept, so first_name is printed as First_Name.
+	TitleHeaderCase
+)
+
+// apply returns str in the header case
+func (hc HeaderCase) apply(str string) string {
+	switch hc {
+	case UpperHeaderCase:
+		return strings.ToUpper(str)
+	case LowerHeaderCase:
+		return strings.ToLower(str)
+	case TitleHeaderCase:
+		return titleCase(str)
+	default:
+		return str
+	}
+}
+
+func titleCase(str string) string {
+	var sb strings.Builder
+	wordStart := true
+	for _, r := range str {
+		if wordStart {
+			sb.WriteRune(unicode.ToUpper(r))
+		} else {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+
+		wordStart = !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}
+
+	return sb.String()
+}
+
+// WithHeaderCase causes the transformer to print the header row, the first row it is sent, in the header case given.
+// The header is transformed before it is sampled, so a column whose values are all narrower than its name is sized to
+// the transformed name, and re-emitted headers are printed in the same case.  Data rows and the summary row are never
+// transformed.
+func WithHeaderCase(hc HeaderCase) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		asTr.headerCase = hc
+	}
+}
+
+// caseHeader returns the header row given with each column name in the transformer's header case
+func (asTr *AutoSizingFWTTransformer) caseHeader(r pipeline.RowWithProps) (pipeline.RowWithProps, error) {
+	taggedVals := make(row.TaggedValues)
+	_, err := r.Row.IterSchema(asTr.sch, func(tag uint64, val types.Value) (stop bool, err error) {
+		if !types.IsNull(val) {
+			taggedVals[tag] = types.String(asTr.headerCase.apply(string(val.(types.String))))
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return r, err
+	}
+
+	cased, err := row.New(r.Row.Format(), asTr.sch, taggedVals)
+
+	if err != nil {
+		return r, err
+	}
+
+	return pipeline.RowWithProps{Row: cased, Props: r.Props}, nil
+}
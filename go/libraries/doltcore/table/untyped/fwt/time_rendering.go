@@ -0,0 +1,187 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fwt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// layoutReferenceTime is the time formatted and parsed back to check that a layout is usable.  Each of its elements
+// differs from those of Go's reference time so that misplaced elements are caught.
+var layoutReferenceTime = time.Date(2021, time.November, 23, 19, 8, 7, 654321000, time.UTC)
+
+// TimeLayouts holds the Go time layouts, such as "Jan 2, 2006" or "15:04", that the values of date, datetime, timestamp,
+// time, and year columns are printed with.  Create one with NewTimeLayouts and apply it with WithTimeLayouts.
+type TimeLayouts struct {
+	layouts map[uint64]timeLayout
+}
+
+// timeLayout is the layout of a single column along with the type of the column, which determines how values are read
+type timeLayout struct {
+	layout string
+	typeID typeinfo.Identifier
+}
+
+// NewTimeLayouts returns the TimeLayouts for the layouts given, which map the tags of columns to the layout their values
+// are printed with.  sch is the schema of the rows before they were converted to strings, which is used to find the
+// temporal columns.  Tags of columns of other types are ignored.  An error is returned for a layout which has no time
+// elements or which can't be parsed back, as it would print the same text for every value or print values which can't
+// be distinguished.  The values of time columns are printed as the time of day that many hours, minutes, and seconds
+// after midnight, so values that are negative or of 24 hours or more, which have no time of day, are printed unchanged.
+// Year columns are printed as the first instant of the year.
+func NewTimeLayouts(sch schema.Schema, layouts map[uint64]string) (TimeLayouts, error) {
+	tl := TimeLayouts{layouts: make(map[uint64]timeLayout, len(layouts))}
+	for tag, layout := range layouts {
+		col, ok := sch.GetAllCols().GetByTag(tag)
+
+		if !ok {
+			continue
+		}
+
+		typeID := col.TypeInfo.GetTypeIdentifier()
+		switch typeID {
+		case typeinfo.DatetimeTypeIdentifier, typeinfo.TimeTypeIdentifier, typeinfo.YearTypeIdentifier:
+		default:
+			continue
+		}
+
+		err := ValidateTimeLayout(layout)
+
+		if err != nil {
+			return TimeLayouts{}, fmt.Errorf("invalid layout for column '%s': %w", col.Name, err)
+		}
+
+		tl.layouts[tag] = timeLayout{layout: layout, typeID: typeID}
+	}
+
+	return tl, nil
+}
+
+// ValidateTimeLayout returns an error if the Go time layout given has no time elements or if text formatted with it
+// can't be parsed back
+func ValidateTimeLayout(layout string) error {
+	formatted := layoutReferenceTime.Format(layout)
+
+	if formatted == layout {
+		return fmt.Errorf(`layout "%s" has no time elements`, layout)
+	}
+
+	_, err := time.Parse(layout, formatted)
+
+	if err != nil {
+		return fmt.Errorf(`layout "%s" can't be parsed back: %s`, layout, err.Error())
+	}
+
+	return nil
+}
+
+// WithTimeLayouts causes the transformer to print the values of temporal columns with the Go time layouts given.  Values
+// are rewritten before they are sampled so the widths of the formatted values are measured.  Columns without a layout
+// print their textual form.
+func WithTimeLayouts(layouts TimeLayouts) AutoSizingOption {
+	return func(asTr *AutoSizingFWTTransformer) {
+		if len(layouts.layouts) == 0 {
+			return
+		}
+
+		if asTr.timeLayouts == nil {
+			asTr.timeLayouts = make(map[uint64]timeLayout, len(layouts.layouts))
+		}
+
+		for tag, layout := range layouts.layouts {
+			asTr.timeLayouts[tag] = layout
+		}
+	}
+}
+
+// render returns a value of the layout's column formatted with the layout.  The value may be a types.Timestamp or the
+// textual form of a value of the column's type.  NULLs and values which can't be read are returned unchanged.
+func (tl timeLayout) render(val types.Value) types.Value {
+	var t time.Time
+	var ok bool
+	switch typedVal := val.(type) {
+	case types.Timestamp:
+		t, ok = time.Time(typedVal), true
+	case types.String:
+		t, ok = tl.parse(string(typedVal))
+	}
+
+	if !ok {
+		return val
+	}
+
+	return types.String(t.Format(tl.layout))
+}
+
+// parse reads the textual form of a value of the layout's column
+func (tl timeLayout) parse(str string) (time.Time, bool) {
+	switch tl.typeID {
+	case typeinfo.DatetimeTypeIdentifier:
+		for _, layout := range []string{sql.TimestampDatetimeLayout, sql.DateLayout, time.RFC3339Nano} {
+			if t, err := time.Parse(layout, str); err == nil {
+				return t, true
+			}
+		}
+	case typeinfo.TimeTypeIdentifier:
+		if d, ok := parseTimeOfDay(str); ok {
+			return time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC).Add(d), true
+		}
+	case typeinfo.YearTypeIdentifier:
+		if year, err := strconv.Atoi(str); err == nil {
+			return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// parseTimeOfDay reads the textual form of a TIME value, such as "12:30:00" or "01:02:03.5", returning false if it
+// can't be read or is not a time of day between 00:00:00 and 23:59:59.999999
+func parseTimeOfDay(str string) (time.Duration, bool) {
+	parts := strings.Split(str, ":")
+
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+
+	if err != nil || hours < 0 || hours > 23 || strings.HasPrefix(parts[0], "-") {
+		return 0, false
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+
+	if err != nil || seconds < 0 || seconds >= 60 {
+		return 0, false
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second)+0.5), true
+}
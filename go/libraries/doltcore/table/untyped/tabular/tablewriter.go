@@ -27,6 +27,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fwt"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 	"github.com/dolthub/dolt/go/store/types"
@@ -45,6 +46,8 @@ type TextTableWriter struct {
 	lastWritten   *row.Row
 	numHeaderRows int
 	numHrsWritten int
+	// rawWritten is true once a row with the fwt.RawRowProp property has been written, after which no footer is drawn
+	rawWritten bool
 }
 
 // NewTextTableWriter writes rows to the given WriteCloser based on the Schema provided, with a single table header row.
@@ -68,12 +71,12 @@ func NewTextTableWriterWithNumHeaderRows(wr io.WriteCloser, sch schema.Schema, n
 	}
 
 	bwr := bufio.NewWriterSize(wr, writeBufSize)
-	return &TextTableWriter{wr, bwr, sch, nil, numHeaderRows, 0}, nil
+	return &TextTableWriter{wr, bwr, sch, nil, numHeaderRows, 0, false}, nil
 }
 
-// writeTableHeader writes a table header with the column names given in the row provided, which is assumed to be
-// string-typed and to have the appropriate fixed width set.
-func (ttw *TextTableWriter) writeTableHeader(r row.Row) error {
+// headerLines returns the separator line and the column name line for the header row provided, which is assumed to
+// be string-typed and to have the appropriate fixed width set.
+func (ttw *TextTableWriter) headerLines(r row.Row) (string, string, error) {
 	allCols := ttw.sch.GetAllCols()
 
 	var separator strings.Builder
@@ -101,6 +104,18 @@ func (ttw *TextTableWriter) writeTableHeader(r row.Row) error {
 		return false, nil
 	})
 
+	if err != nil {
+		return "", "", err
+	}
+
+	return separator.String(), colnames.String(), nil
+}
+
+// writeTableHeader writes a table header with the column names given in the row provided, which is assumed to be
+// string-typed and to have the appropriate fixed width set.
+func (ttw *TextTableWriter) writeTableHeader(r row.Row) error {
+	separator, colnames, err := ttw.headerLines(r)
+
 	if err != nil {
 		return err
 	}
@@ -109,18 +124,18 @@ func (ttw *TextTableWriter) writeTableHeader(r row.Row) error {
 
 	// Write the separators and the column headers as necessary
 	if ttw.numHrsWritten == 0 {
-		if err := iohelp.WriteLines(ttw.bWr, separator.String()); err != nil {
+		if err := iohelp.WriteLines(ttw.bWr, separator); err != nil {
 			return err
 		}
 	}
 
-	if err := iohelp.WriteLines(ttw.bWr, colnames.String()); err != nil {
+	if err := iohelp.WriteLines(ttw.bWr, colnames); err != nil {
 		return err
 	}
 
 	ttw.numHrsWritten++
 	if ttw.numHrsWritten == ttw.numHeaderRows {
-		if err := iohelp.WriteLines(ttw.bWr, separator.String()); err != nil {
+		if err := iohelp.WriteLines(ttw.bWr, separator); err != nil {
 			return err
 		}
 	}
@@ -128,6 +143,44 @@ func (ttw *TextTableWriter) writeTableHeader(r row.Row) error {
 	return nil
 }
 
+// writeRepeatedHeader writes a header row re-emitted in the middle of the table, surrounded by separators
+func (ttw *TextTableWriter) writeRepeatedHeader(r row.Row) error {
+	separator, colnames, err := ttw.headerLines(r)
+
+	if err != nil {
+		return err
+	}
+
+	ttw.lastWritten = &r
+	return iohelp.WriteLines(ttw.bWr, separator, colnames, separator)
+}
+
+// writeSummaryRow writes a summary row below the data rows, separated from them by a rule
+func (ttw *TextTableWriter) writeSummaryRow(ctx context.Context, r row.Row) error {
+	separator, _, err := ttw.headerLines(r)
+
+	if err != nil {
+		return err
+	}
+
+	if err := iohelp.WriteLine(ttw.bWr, separator); err != nil {
+		return err
+	}
+
+	return ttw.WriteRow(ctx, r)
+}
+
+// writeGroupSeparator writes the rule between two groups of rows, sized to the row given
+func (ttw *TextTableWriter) writeGroupSeparator(r row.Row) error {
+	separator, _, err := ttw.headerLines(r)
+
+	if err != nil {
+		return err
+	}
+
+	return iohelp.WriteLine(ttw.bWr, separator)
+}
+
 // writeTableFooter writes the final separator line for a table
 func (ttw *TextTableWriter) writeTableFooter() error {
 	if ttw.lastWritten == nil {
@@ -166,6 +219,66 @@ func (ttw *TextTableWriter) GetSchema() schema.Schema {
 	return ttw.sch
 }
 
+// WriteRowWithProps writes a row to the table.  Rows with the fwt.HeaderRowProp property set are written as a header
+// surrounded by separators rather than as data, and rows with the fwt.SummaryRowProp property set are written below a
+// separating rule.  Rows with the fwt.GroupSeparatorProp property set are written as a rule alone.  Rows with the
+// fwt.RawRowProp property set are written as their cells joined by the separator it holds, without any table drawing.
+func (ttw *TextTableWriter) WriteRowWithProps(ctx context.Context, r row.Row, props pipeline.ReadableMap) error {
+	if sep, isRaw := props.Get(fwt.RawRowProp); isRaw {
+		return ttw.writeRawRow(r, sep.(rune))
+	}
+
+	headerDone := ttw.lastWritten != nil && ttw.numHrsWritten >= ttw.numHeaderRows
+
+	if _, isHeader := props.Get(fwt.HeaderRowProp); isHeader && headerDone {
+		return ttw.writeRepeatedHeader(r)
+	}
+
+	if _, isSummary := props.Get(fwt.SummaryRowProp); isSummary && headerDone {
+		return ttw.writeSummaryRow(ctx, r)
+	}
+
+	if _, isRule := props.Get(fwt.GroupSeparatorProp); isRule {
+		if !headerDone {
+			return nil
+		}
+
+		return ttw.writeGroupSeparator(r)
+	}
+
+	return ttw.WriteRow(ctx, r)
+}
+
+// ProcFuncForTextTableWriter returns a pipeline.OutFunc that writes to the TextTableWriter given, honoring repeated
+// headers emitted by fwt.WithHeaderInterval, summary rows emitted by fwt.WithSummaryRow, and rules emitted by
+// fwt.WithGroupSeparators.
+func ProcFuncForTextTableWriter(ctx context.Context, ttw *TextTableWriter) pipeline.OutFunc {
+	return pipeline.ProcFuncForSinkFunc(func(r row.Row, props pipeline.ReadableMap) error {
+		return ttw.WriteRowWithProps(ctx, r, props)
+	})
+}
+
+// writeRawRow writes the cells of a row emitted by fwt.WithRawOutput joined by sep
+func (ttw *TextTableWriter) writeRawRow(r row.Row, sep rune) error {
+	var cells []string
+	_, err := r.IterSchema(ttw.sch, func(tag uint64, val types.Value) (stop bool, err error) {
+		if types.IsNull(val) {
+			cells = append(cells, "")
+		} else {
+			cells = append(cells, string(val.(types.String)))
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	ttw.rawWritten = true
+	return iohelp.WriteLine(ttw.bWr, strings.Join(cells, string(sep)))
+}
+
 // WriteRow will write a row to a table
 func (ttw *TextTableWriter) WriteRow(ctx context.Context, r row.Row) error {
 	// Handle writing header rows as asked for
@@ -201,10 +314,12 @@ func (ttw *TextTableWriter) WriteRow(ctx context.Context, r row.Row) error {
 // Close should flush all writes, release resources being held
 func (ttw *TextTableWriter) Close(ctx context.Context) error {
 	if ttw.closer != nil {
-		// Write the table footer to finish the table off
-		errFt := ttw.writeTableFooter()
-		if errFt != nil {
-			return errFt
+		// Write the table footer to finish the table off.  Raw rows aren't drawn as a table so they have no footer.
+		if !ttw.rawWritten {
+			errFt := ttw.writeTableFooter()
+			if errFt != nil {
+				return errFt
+			}
 		}
 
 		errFl := ttw.bWr.Flush()
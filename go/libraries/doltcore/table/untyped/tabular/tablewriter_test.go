@@ -20,10 +20,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fwt"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -288,3 +291,44 @@ func TestEastAsianLanguages(t *testing.T) {
 		assert.Equal(t, expectedTableString, stringWr.String())
 	})
 }
+
+func TestWriteRawRows(t *testing.T) {
+	_, sch := untyped.NewUntypedSchema(nameColName, ageColName)
+	rawProps := pipeline.NoProps.Set(map[string]interface{}{fwt.RawRowProp: ','})
+
+	var stringWr StringBuilderCloser
+	tableWr, err := NewTextTableWriter(&stringWr, sch)
+	require.NoError(t, err)
+
+	for _, vals := range [][]string{{"name", "age"}, {"Michael Scott", "43"}, {`"Scott, Michael"`, ""}} {
+		r, err := row.New(types.Format_7_18, sch, row.TaggedValues{nameColTag: types.String(vals[0]), ageColTag: types.String(vals[1])})
+		require.NoError(t, err)
+		require.NoError(t, tableWr.WriteRowWithProps(context.Background(), r, rawProps))
+	}
+
+	require.NoError(t, tableWr.Close(context.Background()))
+	assert.Equal(t, "name,age\nMichael Scott,43\n\"Scott, Michael\",\n", stringWr.String())
+}
+
+func TestWriteGroupSeparators(t *testing.T) {
+	_, sch := untyped.NewUntypedSchema(nameColName, ageColName)
+	ruleProps := pipeline.NoProps.Set(map[string]interface{}{fwt.GroupSeparatorProp: true})
+
+	var stringWr StringBuilderCloser
+	tableWr, err := NewTextTableWriter(&stringWr, sch)
+	require.NoError(t, err)
+
+	for _, vals := range [][]string{{"name", "age"}, {"a", "1"}, {"a", "2"}, {"b", "3"}} {
+		r, err := row.New(types.Format_7_18, sch, row.TaggedValues{nameColTag: types.String(vals[0]), ageColTag: types.String(vals[1])})
+		require.NoError(t, err)
+
+		if vals[0] == "b" {
+			require.NoError(t, tableWr.WriteRowWithProps(context.Background(), r, ruleProps))
+		}
+
+		require.NoError(t, tableWr.WriteRowWithProps(context.Background(), r, pipeline.NoProps))
+	}
+
+	require.NoError(t, tableWr.Close(context.Background()))
+	assert.Equal(t, "+------+-----+\n| name | age |\n+------+-----+\n| a | 1 |\n| a | 2 |\n+---+---+\n| b | 3 |\n+---+---+\n", stringWr.String())
+}
@@ -51,6 +51,10 @@ type WritableFS interface {
 	// it will be overwritten.
 	OpenForWrite(fp string, perm os.FileMode) (io.WriteCloser, error)
 
+	// OpenForWriteAt opens an existing file for writing after truncating it to size bytes, so that what is written
+	// follows its first size bytes.  An error is returned if the file does not exist or is shorter than size.
+	OpenForWriteAt(fp string, size int64) (io.WriteCloser, error)
+
 	// WriteFile writes the entire data buffer to a given file.  The file will be created if it does not exist,
 	// and if it does exist it will be overwritten.
 	WriteFile(fp string, data []byte) error
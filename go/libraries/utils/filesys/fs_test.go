@@ -103,6 +103,23 @@ func TestFilesystems(t *testing.T) {
 			dataRead, err = fs.ReadFile(movedFilePath)
 			require.NoError(t, err)
 			require.Equal(t, dataRead, data)
+
+			// Test continuing to write a file after truncating it
+			wr, err := fs.OpenForWriteAt(movedFilePath, 1024)
+			require.NoError(t, err)
+			_, err = wr.Write([]byte(testString))
+			require.NoError(t, err)
+			require.NoError(t, wr.Close())
+
+			dataRead, err = fs.ReadFile(movedFilePath)
+			require.NoError(t, err)
+			require.Equal(t, append(append([]byte{}, data[:1024]...), testString...), dataRead)
+
+			// Test failure to truncate a file to more than its size, or one which doesn't exist
+			_, err = fs.OpenForWriteAt(movedFilePath, 1024+testStringLen+1)
+			require.Error(t, err)
+			_, err = fs.OpenForWriteAt(fp, 0)
+			require.Error(t, err)
 		})
 	}
 }
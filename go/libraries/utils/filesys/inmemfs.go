@@ -17,6 +17,7 @@ package filesys
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -320,6 +321,35 @@ func (fs *InMemFS) OpenForWrite(fp string, perm os.FileMode) (io.WriteCloser, er
 	return &inMemFSWriteCloser{fp, parentDir, fs, bytes.NewBuffer(make([]byte, 0, 512)), fs.rwLock}, nil
 }
 
+// OpenForWriteAt opens an existing file for writing after truncating it to size bytes, so that what is written
+// follows its first size bytes.  An error is returned if the file does not exist or is shorter than size.
+func (fs *InMemFS) OpenForWriteAt(fp string, size int64) (io.WriteCloser, error) {
+	fs.rwLock.Lock()
+	defer fs.rwLock.Unlock()
+
+	fp = fs.getAbsPath(fp)
+	obj, ok := fs.objs[fp]
+
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	mf, ok := obj.(*memFile)
+
+	if !ok {
+		return nil, ErrIsDir
+	}
+
+	if int64(len(mf.data)) < size {
+		return nil, fmt.Errorf("%s is %d bytes which is shorter than %d bytes", fp, len(mf.data), size)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, size+512))
+	buf.Write(mf.data[:size])
+
+	return &inMemFSWriteCloser{fp, mf.parentDir, fs, buf, fs.rwLock}, nil
+}
+
 // WriteFile writes the entire data buffer to a given file.  The file will be created if it does not exist,
 // and if it does exist it will be overwritten.
 func (fs *InMemFS) WriteFile(fp string, data []byte) error {
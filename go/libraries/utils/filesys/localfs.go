@@ -31,6 +31,12 @@ type localFS struct {
 	cwd string
 }
 
+// IsLocalFS returns whether fs is backed by the local filesystem, so that its paths can be used with the os package
+func IsLocalFS(fs ReadableFS) bool {
+	_, ok := fs.(*localFS)
+	return ok
+}
+
 // LocalFilesysWithWorkingDir returns a new Filesys implementation backed by the local filesystem with the supplied
 // working directory.  Path relative operations occur relative to this directory.
 func LocalFilesysWithWorkingDir(cwd string) (Filesys, error) {
@@ -170,6 +176,53 @@ func (fs *localFS) OpenForWrite(fp string, perm os.FileMode) (io.WriteCloser, er
 	return os.OpenFile(fp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
 }
 
+// OpenForWriteAt opens an existing file for writing after truncating it to size bytes, so that what is written
+// follows its first size bytes.  An error is returned if the file does not exist or is shorter than size.
+func (fs *localFS) OpenForWriteAt(fp string, size int64) (io.WriteCloser, error) {
+	var err error
+	fp, err = fs.Abs(fp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(fp, os.O_WRONLY, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = truncateForWriteAt(f, size)
+
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func truncateForWriteAt(f *os.File, size int64) error {
+	info, err := f.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	if info.Size() < size {
+		return fmt.Errorf("%s is %d bytes which is shorter than %d bytes", f.Name(), info.Size(), size)
+	}
+
+	err = f.Truncate(size)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Seek(size, io.SeekStart)
+	return err
+}
+
 // WriteFile writes the entire data buffer to a given file.  The file will be created if it does not exist,
 // and if it does exist it will be overwritten.
 func (fs *localFS) WriteFile(fp string, data []byte) error {
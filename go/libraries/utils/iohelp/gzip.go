@@ -0,0 +1,164 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iohelp
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+)
+
+// ErrGzipAborted is returned when writing to a GzipWriteCloser after its output has been abandoned with Abort
+var ErrGzipAborted = errors.New("gzip output aborted")
+
+// GzipOption configures optional behavior of a GzipWriteCloser
+type GzipOption func(gzw *GzipWriteCloser)
+
+// WithGzipLevel sets the compression level, which is gzip.DefaultCompression by default.  See compress/gzip for the
+// valid levels.
+func WithGzipLevel(level int) GzipOption {
+	return func(gzw *GzipWriteCloser) {
+		gzw.level = level
+	}
+}
+
+// WithGzipFlushInterval causes the compressed stream to be flushed to the underlying writer each time another n
+// uncompressed bytes have been written, so a reader of the output, such as a pipe, receives data while a long export
+// is running.  Each flush costs a few bytes of output.  An n <= 0 disables periodic flushing, which is the default.
+func WithGzipFlushInterval(n int) GzipOption {
+	return func(gzw *GzipWriteCloser) {
+		gzw.flushInterval = n
+	}
+}
+
+// GzipWriteCloser is an io.WriteCloser which gzip compresses everything written to it to an underlying
+// io.WriteCloser, such as a file an export is being written to.  The gzip stream is only finalized by Close when every
+// write has succeeded.  After a failed write, or a call to Abort, the stream is left without its trailer so a partial
+// export can't be mistaken for a complete one when it is decompressed.
+type GzipWriteCloser struct {
+	gz            *gzip.Writer
+	closer        io.Closer
+	level         int
+	flushInterval int
+	sinceFlush    int
+	err           error
+	closed        bool
+}
+
+var _ io.WriteCloser = (*GzipWriteCloser)(nil)
+var _ Aborter = (*GzipWriteCloser)(nil)
+
+// Aborter is implemented by writers whose output can be abandoned, instead of completed by Close, when what is being
+// written fails part way through
+type Aborter interface {
+	Abort() error
+}
+
+// AbortOrClose aborts c if it is an Aborter and closes it otherwise
+func AbortOrClose(c io.Closer) error {
+	if a, ok := c.(Aborter); ok {
+		return a.Abort()
+	}
+
+	return c.Close()
+}
+
+// NewGzipWriteCloser returns a GzipWriteCloser which writes compressed output to wr.  An error is returned if the
+// compression level is invalid.
+func NewGzipWriteCloser(wr io.WriteCloser, opts ...GzipOption) (*GzipWriteCloser, error) {
+	gzw := &GzipWriteCloser{closer: wr, level: gzip.DefaultCompression}
+
+	for _, opt := range opts {
+		opt(gzw)
+	}
+
+	gz, err := gzip.NewWriterLevel(wr, gzw.level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	gzw.gz = gz
+	return gzw, nil
+}
+
+// Write compresses p to the underlying writer.  Once a write has failed every later write returns the same error.
+func (gzw *GzipWriteCloser) Write(p []byte) (int, error) {
+	if gzw.err != nil {
+		return 0, gzw.err
+	}
+
+	n, err := gzw.gz.Write(p)
+
+	if err != nil {
+		gzw.err = err
+		return n, err
+	}
+
+	gzw.sinceFlush += n
+	if gzw.flushInterval > 0 && gzw.sinceFlush >= gzw.flushInterval {
+		gzw.sinceFlush = 0
+
+		if err := gzw.gz.Flush(); err != nil {
+			gzw.err = err
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// Close finalizes the gzip stream and closes the underlying writer.  If a write has failed the stream is not finalized,
+// the underlying writer is closed, and the write's error is returned.
+func (gzw *GzipWriteCloser) Close() error {
+	if gzw.closed {
+		return errors.New("already closed")
+	}
+
+	gzw.closed = true
+
+	if gzw.err != nil {
+		_ = gzw.closer.Close()
+
+		if gzw.err == ErrGzipAborted {
+			return nil
+		}
+
+		return gzw.err
+	}
+
+	errGz := gzw.gz.Close()
+	errCl := gzw.closer.Close()
+
+	if errGz != nil {
+		return errGz
+	}
+
+	return errCl
+}
+
+// Abort abandons the output, closing the underlying writer without finalizing the gzip stream.  It is used when an
+// export fails for a reason other than a failed write, and makes any later writes fail with ErrGzipAborted.
+func (gzw *GzipWriteCloser) Abort() error {
+	if gzw.closed {
+		return nil
+	}
+
+	if gzw.err == nil {
+		gzw.err = ErrGzipAborted
+	}
+
+	return gzw.Close()
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iohelp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (bc *bufferCloser) Close() error {
+	bc.closed = true
+	return nil
+}
+
+// failingWriteCloser fails every write once failAfter bytes have been written
+type failingWriteCloser struct {
+	bufferCloser
+	failAfter int
+}
+
+func (fwc *failingWriteCloser) Write(p []byte) (int, error) {
+	if fwc.Len()+len(p) > fwc.failAfter {
+		return 0, errors.New("disk full")
+	}
+
+	return fwc.Buffer.Write(p)
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	rd, err := gzip.NewReader(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(rd)
+}
+
+func TestGzipWriteCloser(t *testing.T) {
+	_, err := NewGzipWriteCloser(&bufferCloser{}, WithGzipLevel(42))
+	assert.Error(t, err)
+
+	input := strings.Repeat("a,b,c\n1,2,3\n", 1000)
+	for _, level := range []int{gzip.NoCompression, gzip.BestSpeed, gzip.BestCompression} {
+		var out bufferCloser
+		gzw, err := NewGzipWriteCloser(&out, WithGzipLevel(level))
+		require.NoError(t, err)
+
+		_, err = gzw.Write([]byte(input))
+		require.NoError(t, err)
+		require.NoError(t, gzw.Close())
+		assert.True(t, out.closed)
+		assert.Error(t, gzw.Close())
+
+		decompressed, err := gunzip(out.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, input, string(decompressed))
+	}
+}
+
+func TestGzipWriteCloserFlushInterval(t *testing.T) {
+	var out bufferCloser
+	gzw, err := NewGzipWriteCloser(&out, WithGzipFlushInterval(10))
+	require.NoError(t, err)
+
+	_, err = gzw.Write([]byte("0123456789"))
+	require.NoError(t, err)
+
+	// the flushed data can be read before the stream is finalized
+	rd, err := gzip.NewReader(bytes.NewReader(out.Bytes()))
+	require.NoError(t, err)
+	buf := make([]byte, 10)
+	n, err := rd.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(buf[:n]))
+
+	require.NoError(t, gzw.Close())
+}
+
+func TestGzipWriteCloserPartialOutput(t *testing.T) {
+	input := []byte(strings.Repeat("some text which doesn't compress down to nothing ", 100))
+
+	t.Run("failed write", func(t *testing.T) {
+		out := &failingWriteCloser{failAfter: 64}
+		gzw, err := NewGzipWriteCloser(out, WithGzipFlushInterval(1))
+		require.NoError(t, err)
+
+		_, err = gzw.Write(input)
+		require.Error(t, err)
+		_, err = gzw.Write(input)
+		assert.EqualError(t, err, "disk full")
+
+		assert.EqualError(t, gzw.Close(), "disk full")
+		assert.True(t, out.closed)
+
+		_, err = gunzip(out.Bytes())
+		assert.Error(t, err)
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		var out bufferCloser
+		gzw, err := NewGzipWriteCloser(&out, WithGzipFlushInterval(1))
+		require.NoError(t, err)
+
+		_, err = gzw.Write(input)
+		require.NoError(t, err)
+		require.NoError(t, gzw.Abort())
+		assert.True(t, out.closed)
+
+		_, err = gzw.Write(input)
+		assert.Equal(t, ErrGzipAborted, err)
+
+		// the data written is there, but the stream is incomplete
+		_, err = gunzip(out.Bytes())
+		assert.Error(t, err)
+	})
+}
@@ -228,7 +228,10 @@ func BenchmarkMapItr(b *testing.B) {
 		closeFunc = cl.Close
 	}
 
-	dmItr := sqle.NewDoltMapIter(ctx, itr.NextTuple, closeFunc, sqle.NewKVToSqlRowConverterForCols(m.Format(), testDataCols))
+	conv, err := sqle.NewKVToSqlRowConverterForCols(m.Format(), testDataCols)
+	require.NoError(b, err)
+
+	dmItr := sqle.NewDoltMapIter(ctx, itr.NextTuple, closeFunc, conv)
 
 	b.ResetTimer()
 	for {
@@ -275,7 +278,10 @@ func BenchmarkMapItr(b *testing.B) {
 	itr, err := m.RangeIterator(ctx, 0, uint64(b.N))
 	require.NoError(b, err)
 
-	dmItr := sqle.NewDoltMapIter(ctx, itr.NextTuple, closeFunc, sqle.NewKVToSqlRowConverterForCols(m.Format(), testDataCols))
+	conv, err := sqle.NewKVToSqlRowConverterForCols(m.Format(), testDataCols)
+	require.NoError(b, err)
+
+	dmItr := sqle.NewDoltMapIter(ctx, itr.NextTuple, closeFunc, conv)
 
 	b.ResetTimer()
 	for {